@@ -4,11 +4,18 @@ import (
 	"fmt"
 	"os"
 
-	"zakaranda/internal/ui"
+	"github.com/dahromy/zakaranda/internal/cli"
+	"github.com/dahromy/zakaranda/internal/ui"
 )
 
 func main() {
-	if err := ui.Run(); err != nil {
+	// Subcommands run non-interactively; no arguments starts the TUI
+	run := ui.Run
+	if len(os.Args) > 1 {
+		run = func() error { return cli.Run(os.Args[1:]) }
+	}
+
+	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
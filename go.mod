@@ -1,4 +1,4 @@
-module zakaranda
+module github.com/dahromy/zakaranda
 
 go 1.21
 
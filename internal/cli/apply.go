@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/config"
+	"github.com/dahromy/zakaranda/internal/theme"
+	"github.com/dahromy/zakaranda/pkg/zakaranda"
+)
+
+// runApply applies a theme, or a light/dark pair, to the selected apps and
+// prints a line per app
+func runApply(args []string) error {
+	fs := newFlagSet("apply")
+	var apps stringList
+	fs.Var(&apps, "app", "only apply to this app (repeatable; default: all)")
+	pairName := fs.String("pair", "", "opposite-appearance theme for apps that follow the system appearance")
+	prefer := fs.String("prefer", "", "side of the pair for other apps (default: the configured preference)")
+	dryRun := fs.Bool("dry-run", false, "show what would be applied without writing files")
+	root := fs.String("root", "", "directory to use instead of the home directory (skips changes outside it)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("apply: no theme name given")
+	}
+
+	client, err := newClient(clientOptions(*dryRun, *root)...)
+	if err != nil {
+		return err
+	}
+	t, err := findTheme(client, strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	req := zakaranda.Request{Theme: t, Apps: apps}
+	if *pairName != "" {
+		other, err := findTheme(client, *pairName)
+		if err != nil {
+			return err
+		}
+		pair, err := theme.NewPair(t, other)
+		if err != nil {
+			return err
+		}
+		req.Pair = &pair
+		if req.Preferred, err = preferredAppearance(*prefer); err != nil {
+			return err
+		}
+	}
+
+	results, err := client.Apply(req)
+	return reportResults("apply", results, err)
+}
+
+// runRestore restores app configurations from their backups
+func runRestore(args []string) error {
+	fs := newFlagSet("restore")
+	var apps stringList
+	fs.Var(&apps, "app", "only restore this app (repeatable; default: all apps with backups)")
+	dryRun := fs.Bool("dry-run", false, "show what would be restored without writing files")
+	root := fs.String("root", "", "directory to use instead of the home directory (skips changes outside it)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := newClient(clientOptions(*dryRun, *root)...)
+	if err != nil {
+		return err
+	}
+
+	results, err := client.Restore(apps...)
+	if err == nil && len(results) == 0 {
+		fmt.Fprintln(stdout, "No apps keep backups")
+	}
+	return reportResults("restore", results, err)
+}
+
+// clientOptions returns the client options for the --dry-run and --root flags
+func clientOptions(dryRun bool, root string) []zakaranda.Option {
+	opts := []zakaranda.Option{zakaranda.WithDryRun(dryRun)}
+	if root != "" {
+		opts = append(opts, zakaranda.WithRoot(root))
+	}
+	return opts
+}
+
+// preferredAppearance parses the --prefer flag, falling back to the configured preference
+func preferredAppearance(prefer string) (theme.Appearance, error) {
+	if prefer == "" {
+		cm, err := config.NewConfigManager()
+		if err != nil {
+			return "", err
+		}
+		prefer = cm.GetPreferredAppearance()
+	}
+	appearance, err := theme.ParseAppearance(prefer)
+	if err != nil {
+		return "", err
+	}
+	if appearance == "" {
+		appearance = theme.AppearanceDark
+	}
+	return appearance, nil
+}
+
+// reportResults prints a line per result and summarizes the failures
+func reportResults(cmd string, results []zakaranda.Result, err error) error {
+	failed := 0
+	for _, result := range results {
+		fmt.Fprintln(stdout, result)
+		if result.Status == zakaranda.Failed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s: %d of %d apps failed", cmd, failed, len(results))
+	}
+	// Errors before any app ran, such as an unknown app name
+	return err
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dahromy/zakaranda/internal/config"
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// runAudit prints the WCAG contrast of a theme's text colors. With --fix, the
+// failing colors are adjusted and saved as a custom variant of the theme's
+// family extending the original; otherwise failures fail the command.
+func runAudit(args []string) error {
+	fs := newFlagSet("audit")
+	targetFlag := fs.String("target", "AA", "AA, AAA or a contrast ratio")
+	fix := fs.Bool("fix", false, "save a custom theme with the failing colors adjusted")
+	name := fs.String("name", "", "name of the fixed theme (default: \"<theme> Accessible\")")
+	format := fs.String("format", "toml", "toml, yaml or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("audit: no theme name given")
+	}
+	target, err := theme.ParseContrastTarget(*targetFlag)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	t, err := findTheme(client, strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	report := theme.Audit(t)
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LEVEL\tRATIO\tCHECK")
+	for _, check := range report.Checks {
+		fmt.Fprintf(w, "%s\t%.2f\t%s\n", check.Level(), check.Ratio, check)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	failing := report.Failing(target)
+	fmt.Fprintf(stdout, "%d of %d checks below %.1f:1\n", len(failing), len(report.Checks), target)
+	if len(failing) == 0 {
+		return nil
+	}
+	if !*fix {
+		return fmt.Errorf("audit: %s has %d colors below %.1f:1 (use --fix to adjust them)", t.Name, len(failing), target)
+	}
+
+	family, ok := client.Registry().FamilyOf(t.Name)
+	if !ok {
+		return fmt.Errorf("audit: no family holds %s", t.Name)
+	}
+	fixed, changed := theme.FixContrast(t, target)
+	fixed.Name = *name
+	if fixed.Name == "" {
+		fixed.Name = t.Name + " Accessible"
+	}
+	fixed.Description = fmt.Sprintf("%s with contrast of at least %.1f:1", t.Name, target)
+	fixed.Extends = t.Name
+	fixed.Aliases, fixed.Source, fixed.Base, fixed.Overrides = nil, nil, "", nil
+	if _, ok := theme.NewBuiltInRegistry().Lookup(fixed.Name); ok {
+		return fmt.Errorf("audit: a built-in theme is named %q; choose another with --name", fixed.Name)
+	}
+
+	cm, err := config.NewConfigManager()
+	if err != nil {
+		return err
+	}
+	if err := theme.NewThemeLoader(cm.GetCustomThemesPath()).SaveCustomVariant(family.Name, fixed, *format); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Saved %s in the %s family (adjusted %s)\n", fixed.Name, family.Name, strings.ReplaceAll(strings.Join(changed, ", "), "_", " "))
+	return nil
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/config"
+	"github.com/dahromy/zakaranda/pkg/zakaranda"
+)
+
+// Output streams, replaceable in tests
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// command is a CLI subcommand
+type command struct {
+	name    string
+	usage   string // Arguments, e.g. "[--tag name] [query]"
+	summary string
+	run     func(args []string) error
+}
+
+// commands returns all subcommands in help order
+func commands() []command {
+	return []command{
+		{"list", "[--tag name]... [--appearance light|dark] [query]", "List themes, optionally filtered", runList},
+		{"lint", "<file>...", "Check theme files for unknown keys, missing colors and invalid values", runLint},
+		{"export", "[--format toml|yaml|json] [--colors truecolor|256|16] [-o file] <theme>", "Export a theme to a file, or to stdout without -o; --colors 256 or 16 exports the nearest palette indexes", runExport},
+		{"import", "[base16|iterm2|alacritty|vscode|warp|kitty|xresources|windows-terminal|gogh] [--format toml|yaml|json] [file-or-dir]...", "Import color schemes from other tools as custom themes, detecting the format when none is given (iterm2 defaults to its Custom Color Presets, vscode to the installed extensions, warp to ~/.warp/themes)", runImport},
+		{"generate", "(--from-image <file> [--appearance light|dark] | --seed <color> [--background color]) [--name name] [--format toml|yaml|json]", "Generate a custom theme from the colors of a JPEG or PNG image, which also becomes its wallpaper, or a dark and light family from a brand color", runGenerate},
+		{"derive", "[--appearance light|dark] [--name name] [--format toml|yaml|json] <theme>", "Derive a variant with the other appearance and add it to the theme's family", runDerive},
+		{"audit", "[--target AA|AAA|ratio] [--fix] [--name name] [--format toml|yaml|json] <theme>", "Check the WCAG contrast of a theme's text colors, or save a fixed copy with --fix", runAudit},
+		{"transition", "--from <theme> --to <theme> [--over duration] [--steps n] [--app name]... [--dry-run] [--root dir]", "Fade the apps that take a palette (Alacritty, Warp, iTerm2, VS Code) from one theme to another, applying blends over time", runTransition},
+		{"apply", "[--app name]... [--pair theme] [--prefer light|dark] [--dry-run] [--root dir] <theme>", "Apply a theme to all or some apps", runApply},
+		{"restore", "[--app name]... [--dry-run] [--root dir]", "Restore app configurations from the backups made when applying", runRestore},
+		{"schema", "", "Print the JSON Schema of theme files", runSchema},
+	}
+}
+
+// Run executes the subcommand named by args[0]
+func Run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command given")
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(stdout)
+		return nil
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd.run(args[1:])
+		}
+	}
+
+	printUsage(stderr)
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  zakaranda                 Start the interactive theme manager")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %s\n", strings.TrimSpace("zakaranda "+cmd.name+" "+cmd.usage))
+		fmt.Fprintf(w, "      %s\n", cmd.summary)
+	}
+}
+
+// newFlagSet returns a flag set that reports errors instead of exiting
+func newFlagSet(cmd string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// newClient returns a library client with the custom themes and families
+// from the configured themes directory and the Alacritty community themes registered
+func newClient(opts ...zakaranda.Option) (*zakaranda.Client, error) {
+	cm, err := config.NewConfigManager()
+	if err != nil {
+		return nil, err
+	}
+	client := zakaranda.New(append([]zakaranda.Option{zakaranda.WithBrightSynthesis(cm.GetBrightSynthesis())}, opts...)...)
+	// Custom themes may extend or join the Alacritty community family
+	if err := client.LoadAlacrittyThemes(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not load some Alacritty themes: %v\n", err)
+	}
+	if err := client.LoadThemes(cm.GetCustomThemesPath()); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not load some custom themes: %v\n", err)
+	}
+	return client, nil
+}
+
+// findTheme returns the registered theme with the given name or alias (case-insensitive)
+func findTheme(client *zakaranda.Client, name string) (zakaranda.Theme, error) {
+	t, err := client.Lookup(name)
+	if err != nil {
+		return t, fmt.Errorf("%w (see zakaranda list)", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// run executes a subcommand with the output streams captured and returns them
+func run(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	stdout, stderr = &out, &errOut
+	t.Cleanup(func() {
+		stdout, stderr = os.Stdout, os.Stderr
+	})
+	err := Run(args)
+	return out.String(), errOut.String(), err
+}
+
+// setHome points the home directory at a new temporary directory
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return home
+}
+
+// TestRunFlags verifies that commands reject bad arguments and flags before
+// doing any work
+func TestRunFlags(t *testing.T) {
+	setHome(t)
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{"no command", nil, "no command given"},
+		{"unknown command", []string{"paint"}, `unknown command "paint"`},
+		{"unknown flag", []string{"export", "--colour", "16", "Nord"}, "flag provided but not defined"},
+		{"missing flag value", []string{"list", "--appearance"}, "flag needs an argument"},
+		{"invalid appearance", []string{"list", "--appearance", "dim"}, "dim"},
+		{"invalid color profile", []string{"export", "--colors", "8", "Nord"}, "8"},
+		{"invalid target", []string{"audit", "--target", "AAAA", "Nord"}, "AAAA"},
+		{"export without theme", []string{"export", "--format", "yaml"}, "export: no theme name given"},
+		{"audit without theme", []string{"audit", "--fix"}, "audit: no theme name given"},
+		{"unknown theme", []string{"export", "No Such Theme"}, "see zakaranda list"},
+		{"import without source", []string{"import"}, "import: no source format or file given"},
+		{"import of neither format nor file", []string{"import", "nothing.conf"}, "neither a source format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := run(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+
+	out, _, err := run(t, "help")
+	if err != nil || !strings.Contains(out, "zakaranda import") {
+		t.Errorf("Expected usage on stdout, got %q (%v)", out, err)
+	}
+}
+
+const kittyTheme = `## name: Harbor Night
+background #1d2021
+foreground #ebdbb2
+color0 #282828
+color1 #cc241d
+color2 #98971a
+color3 #d79921
+color4 #458588
+color5 #b16286
+color6 #689d6a
+color7 #a89984
+color8 #928374
+color9 #fb4934
+color10 #b8bb26
+color11 #fabd2f
+color12 #83a598
+color13 #d3869b
+color14 #8ec07c
+color15 #ebdbb2
+`
+
+// TestRunImportDetected verifies that a file given without a source format is
+// detected and saved as a custom theme in the requested format
+func TestRunImportDetected(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    func(path string) []string
+		file    string
+		content string
+		err     string
+		saved   string
+	}{
+		{
+			name:    "kitty",
+			args:    func(path string) []string { return []string{"import", path} },
+			file:    "harbor.conf",
+			content: kittyTheme,
+			saved:   "*.toml",
+		},
+		{
+			name:    "kitty with format",
+			args:    func(path string) []string { return []string{"import", "--format", "yaml", path} },
+			file:    "harbor.conf",
+			content: kittyTheme,
+			saved:   "*.yaml",
+		},
+		{
+			name:    "unknown format",
+			args:    func(path string) []string { return []string{"import", path} },
+			file:    "notes.txt",
+			content: "nothing to see here\n",
+			err:     "notes.txt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := setHome(t)
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			out, _, err := run(t, tt.args(path)...)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected error containing %q, got %v", tt.err, err)
+				}
+				if !strings.Contains(out, "0 theme(s) saved") {
+					t.Errorf("Expected nothing to be saved, got %q", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, "Imported Harbor Night") {
+				t.Errorf("Expected the theme to be imported, got %q", out)
+			}
+
+			dir := filepath.Join(home, ".config", "theme-manager", "themes")
+			matches, _ := filepath.Glob(filepath.Join(dir, tt.saved))
+			if len(matches) != 1 {
+				t.Fatalf("Expected one %s file in %s, got %v", tt.saved, dir, matches)
+			}
+			out, _, err = run(t, "export", "Harbor Night")
+			if err != nil || !strings.Contains(out, `background = "#1d2021"`) {
+				t.Errorf("Expected the saved theme to load, got %q (%v)", out, err)
+			}
+		})
+	}
+}
+
+// TestRunExportStdout verifies that export without -o writes the theme, or
+// its palette indexes, to stdout in the requested format
+func TestRunExportStdout(t *testing.T) {
+	setHome(t)
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"toml by default", []string{"export", "Nord"}, []string{`name = "Nord"`, `background = "#2e3440"`}},
+		{"yaml", []string{"export", "--format", "yaml", "nord"}, []string{"name: Nord", "bright_black:"}},
+		{"json", []string{"export", "--format", "json", "Nord"}, []string{`"name": "Nord"`, `"bright_black":`}},
+		{"256 colors", []string{"export", "--colors", "256", "Nord"}, []string{`name = "Nord"`, "background = "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, err := run(t, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("Expected %q in output:\n%s", want, out)
+				}
+			}
+			if strings.Contains(out, "Exported") {
+				t.Errorf("Expected only the theme on stdout, got:\n%s", out)
+			}
+		})
+	}
+}
+
+// TestRunAuditStatus verifies that audit fails when colors are below the
+// target, unless --fix saves an adjusted variant in the theme's family
+func TestRunAuditStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+		out  string
+	}{
+		{"failing", []string{"audit", "Nord"}, "below 4.5:1", "checks below 4.5:1"},
+		{"failing ratio", []string{"audit", "--target", "3", "Nord"}, "below 3.0:1", "checks below 3.0:1"},
+		{"passing", []string{"audit", "--target", "1", "Nord"}, "", "0 of"},
+		{"fixed", []string{"audit", "--fix", "Rose Pine Dawn"}, "", "Saved Rose Pine Dawn Accessible in the Rose Pine family"},
+		{"fixed under a built-in name", []string{"audit", "--fix", "--name", "Dracula", "Nord"}, "a built-in theme is named", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setHome(t)
+			out, _, err := run(t, tt.args...)
+			if tt.err == "" && err != nil {
+				t.Errorf("Expected success, got %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+			if !strings.Contains(out, tt.out) {
+				t.Errorf("Expected %q in output:\n%s", tt.out, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/config"
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// runDerive saves a variant of a theme with the other appearance in the
+// theme's family
+func runDerive(args []string) error {
+	fs := newFlagSet("derive")
+	appearanceFlag := fs.String("appearance", "", "light or dark (default: the opposite of the theme's)")
+	name := fs.String("name", "", "name of the new variant (default: \"<theme> Light\" or \"<theme> Dark\")")
+	format := fs.String("format", "toml", "toml, yaml or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("derive: no theme name given")
+	}
+	appearance, err := theme.ParseAppearance(*appearanceFlag)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	t, err := findTheme(client, strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+	family, ok := client.Registry().FamilyOf(t.Name)
+	if !ok {
+		return fmt.Errorf("derive: no family holds %s", t.Name)
+	}
+	if appearance == "" {
+		appearance = theme.AppearanceLight
+		if t.IsLight() {
+			appearance = theme.AppearanceDark
+		}
+	}
+
+	derived, err := theme.DeriveAppearance(t, appearance)
+	if err != nil {
+		return fmt.Errorf("derive: %w", err)
+	}
+	if *name != "" {
+		derived.Name = *name
+	}
+	if _, ok := theme.NewBuiltInRegistry().Lookup(derived.Name); ok {
+		return fmt.Errorf("derive: a built-in theme is named %q; choose another with --name", derived.Name)
+	}
+
+	cm, err := config.NewConfigManager()
+	if err != nil {
+		return err
+	}
+	if err := theme.NewThemeLoader(cm.GetCustomThemesPath()).SaveCustomVariant(family.Name, derived, *format); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Derived %s in the %s family\n", derived.Name, family.Name)
+	return nil
+}
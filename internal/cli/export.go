@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// runExport writes a theme in the canonical schema to a file or stdout. With
+// --colors 256 or 16, it writes the nearest palette index of each color instead.
+func runExport(args []string) error {
+	fs := newFlagSet("export")
+	format := fs.String("format", "", "toml, yaml or json (default: from the file extension, or toml)")
+	output := fs.String("o", "", "output file (default: stdout)")
+	colors := fs.String("colors", "truecolor", "truecolor, or 256 or 16 for palette indexes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("export: no theme name given")
+	}
+	profile, err := theme.ParseColorProfile(*colors)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	t, err := findTheme(client, strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	if profile != theme.TrueColor {
+		return exportIndexed(t, profile, *format, *output)
+	}
+
+	if *output == "" {
+		if *format == "" {
+			*format = "toml"
+		}
+		if err := t.Normalize(); err != nil {
+			return fmt.Errorf("invalid theme %q: %w", t.Name, err)
+		}
+		data, err := theme.MarshalTheme(t, *format)
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(data)
+		return err
+	}
+
+	path := *output
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	switch {
+	case ext == "":
+		// Add the extension the format is read back by
+		if *format == "" {
+			*format = "toml"
+		}
+		path += "." + formatName(*format)
+	case *format != "" && formatName(ext) != formatName(*format):
+		return fmt.Errorf("export: --format %s doesn't match the extension of %s", *format, path)
+	}
+
+	// Exporting doesn't touch the custom themes directory
+	if err := theme.NewThemeLoader("").ExportTheme(t, path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Exported %s to %s\n", t.Name, path)
+	return nil
+}
+
+// exportIndexed writes the theme's nearest palette indexes to a file or stdout
+func exportIndexed(t theme.Theme, profile theme.ColorProfile, format, output string) error {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(output), ".")
+		if format == "" {
+			format = "toml"
+		}
+	}
+	if err := t.Normalize(); err != nil {
+		return fmt.Errorf("invalid theme %q: %w", t.Name, err)
+	}
+	data, err := theme.MarshalIndexedTheme(t, profile, format)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	fmt.Fprintf(stdout, "Exported %s with %s to %s\n", t.Name, profile, output)
+	return nil
+}
+
+// formatName returns the canonical name of a theme file format
+func formatName(format string) string {
+	format = strings.ToLower(format)
+	if format == "yml" {
+		return "yaml"
+	}
+	return format
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dahromy/zakaranda/internal/config"
+	"github.com/dahromy/zakaranda/internal/generator"
+	"github.com/dahromy/zakaranda/internal/importers"
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// runGenerate creates a custom theme from the colors of an image, which also
+// becomes the theme's wallpaper, or a dark and light family from a seed color
+func runGenerate(args []string) error {
+	fs := newFlagSet("generate")
+	fromImage := fs.String("from-image", "", "JPEG or PNG image to take the colors from")
+	seed := fs.String("seed", "", "accent color to build a dark and light family around")
+	background := fs.String("background", "", "background color for the seed's family (optional)")
+	name := fs.String("name", "", "theme or family name (default for images: from the file name)")
+	appearanceFlag := fs.String("appearance", "", "light or dark (default: from the image's lightness)")
+	format := fs.String("format", "toml", "toml, yaml or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch {
+	case *fromImage != "" && *seed != "":
+		return fmt.Errorf("generate: use either --from-image or --seed")
+	case *seed != "":
+		return generateFromSeed(*seed, *background, *name, *format)
+	case *fromImage == "":
+		return fmt.Errorf("generate: no image or seed given; use --from-image <file> or --seed <color>")
+	}
+	appearance, err := theme.ParseAppearance(*appearanceFlag)
+	if err != nil {
+		return err
+	}
+
+	path, err := filepath.Abs(*fromImage)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	img, err := generator.DecodeImage(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", *fromImage, err)
+	}
+	colors, appearance, err := generator.FromImage(img, appearance)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *fromImage, err)
+	}
+
+	if *name == "" {
+		*name = importers.FileThemeName(path)
+	}
+	// A custom theme can't take a built-in name, so it would never load
+	if _, ok := theme.NewBuiltInRegistry().Lookup(*name); ok {
+		return fmt.Errorf("generate: a built-in theme is named %q; choose another with --name", *name)
+	}
+
+	t := theme.Theme{
+		Name:        *name,
+		Description: "Generated from " + filepath.Base(path),
+		Colors:      colors,
+		Appearance:  appearance,
+		Tags:        []string{"generated"},
+		Wallpaper:   path,
+	}
+	cm, err := config.NewConfigManager()
+	if err != nil {
+		return err
+	}
+	if err := theme.NewThemeLoader(cm.GetCustomThemesPath()).SaveCustomTheme(t, *format); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Generated %s (%s) in %s\n", t.Name, appearance, cm.GetCustomThemesPath())
+	return nil
+}
+
+// generateFromSeed saves a family with a dark and a light variant built
+// around an accent color and optional background
+func generateFromSeed(accent, background, name, format string) error {
+	var seed generator.Seed
+	var err error
+	if seed.Accent, err = theme.ParseColor(accent); err != nil {
+		return fmt.Errorf("generate: seed: %w", err)
+	}
+	if background != "" {
+		bg, err := theme.ParseColor(background)
+		if err != nil {
+			return fmt.Errorf("generate: background: %w", err)
+		}
+		seed.Background = &bg
+	}
+	if name == "" {
+		return fmt.Errorf("generate: a seed family needs a --name")
+	}
+
+	family := seed.Family(name)
+	builtIns := theme.NewBuiltInRegistry()
+	for _, v := range family.Variants {
+		if _, ok := builtIns.Lookup(v.FullName); ok {
+			return fmt.Errorf("generate: a built-in theme is named %q; choose another with --name", v.FullName)
+		}
+	}
+	cm, err := config.NewConfigManager()
+	if err != nil {
+		return err
+	}
+	if err := theme.NewThemeLoader(cm.GetCustomThemesPath()).SaveCustomFamily(family, format); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Generated %s with %d variants in %s\n", family.Name, len(family.Variants), cm.GetCustomThemesPath())
+	return nil
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/config"
+	"github.com/dahromy/zakaranda/internal/importers"
+	"github.com/dahromy/zakaranda/internal/integrations"
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// themeImporter reads the themes of one source format from a file or directory
+type themeImporter struct {
+	read         func(path string) ([]theme.Theme, error)
+	defaultPaths func(home string) []string // Used when no path is given; nil if one is required
+}
+
+// themeImporters lists the importers by source format
+var themeImporters = map[string]themeImporter{
+	"base16":    {read: importers.ImportBase16},
+	"alacritty": {read: importers.ImportAlacritty},
+	"iterm2": {read: importers.ImportITermColors, defaultPaths: func(home string) []string {
+		return []string{importers.ITermPreferencesPath(home)}
+	}},
+	"vscode": {read: importers.ImportVSCodeExtensions, defaultPaths: integrations.VSCodeExtensionDirs},
+	"warp": {read: importers.ImportWarp, defaultPaths: func(home string) []string {
+		return []string{importers.WarpThemesPath(home)}
+	}},
+	"kitty":            {read: importWith(importers.Kitty)},
+	"xresources":       {read: importWith(importers.Xresources)},
+	"windows-terminal": {read: importWith(importers.WindowsTerminal)},
+	"gogh":             {read: importWith(importers.Gogh)},
+}
+
+// importWith reads files with a file importer, or the files of a directory it detects
+func importWith(importer theme.Importer) func(path string) ([]theme.Theme, error) {
+	return func(path string) ([]theme.Theme, error) {
+		return importers.Import(importer, path)
+	}
+}
+
+// runImport converts color schemes from other tools and saves them as custom
+// themes. Without a source format, the format of each file is detected.
+func runImport(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("import: no source format or file given")
+	}
+	kind := strings.ToLower(args[0])
+	importer, ok := themeImporters[kind]
+	if ok {
+		args = args[1:]
+	} else {
+		if _, err := os.Stat(args[0]); err != nil && !strings.HasPrefix(args[0], "-") {
+			return fmt.Errorf("import: %q is neither a source format (%s) nor a file", args[0], strings.Join(importKinds(), ", "))
+		}
+		kind = "auto"
+		importer = themeImporter{read: importers.ImportDetected}
+	}
+
+	fs := newFlagSet("import " + kind)
+	format := fs.String("format", "toml", "toml, yaml or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		home, err := os.UserHomeDir()
+		if importer.defaultPaths == nil || err != nil {
+			return fmt.Errorf("import: no file or directory given")
+		}
+		if paths = importer.defaultPaths(home); len(paths) == 0 {
+			return fmt.Errorf("import: no %s themes found; give a file or directory", kind)
+		}
+	}
+
+	cm, err := config.NewConfigManager()
+	if err != nil {
+		return err
+	}
+	loader := theme.NewThemeLoader(cm.GetCustomThemesPath())
+	builtIns := theme.NewBuiltInRegistry()
+
+	var errs []error
+	imported := 0
+	saved := make(map[string]bool)
+	for _, path := range paths {
+		themes, err := importer.read(path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, t := range themes {
+			// A custom theme can't take a built-in name, so it would never load
+			if _, ok := builtIns.Lookup(t.Name); ok {
+				fmt.Fprintf(stderr, "Skipped %s: a built-in theme has that name\n", t.Name)
+				continue
+			}
+			// The same theme may be installed in several places, e.g. for
+			// VS Code and Cursor
+			if saved[strings.ToLower(t.Name)] {
+				continue
+			}
+			if err := loader.SaveCustomTheme(t, *format); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			saved[strings.ToLower(t.Name)] = true
+			fmt.Fprintf(stdout, "Imported %s\n", t.Name)
+			imported++
+		}
+	}
+
+	fmt.Fprintf(stdout, "%d theme(s) saved to %s\n", imported, cm.GetCustomThemesPath())
+	return errors.Join(errs...)
+}
+
+// importKinds returns the source formats, sorted
+func importKinds() []string {
+	kinds := make([]string, 0, len(themeImporters))
+	for kind := range themeImporters {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
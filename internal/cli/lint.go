@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// runLint checks theme files against the schema and prints every issue.
+// Warnings alone don't fail the command.
+func runLint(args []string) error {
+	fs := newFlagSet("lint")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("lint: no theme file given")
+	}
+
+	failed := 0
+	for _, path := range fs.Args() {
+		issues, err := theme.LintThemeFile(path)
+		if err != nil {
+			fmt.Fprintf(stdout, "%v\n", err)
+			failed++
+			continue
+		}
+
+		errorCount := 0
+		for _, issue := range issues {
+			fmt.Fprintf(stdout, "%s: %s\n", path, issue)
+			if !issue.Warning {
+				errorCount++
+			}
+		}
+		if errorCount > 0 {
+			failed++
+		} else if len(issues) == 0 {
+			fmt.Fprintf(stdout, "%s: ok\n", path)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("lint: %d of %d files have errors", failed, fs.NArg())
+	}
+	return nil
+}
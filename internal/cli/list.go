@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// runList prints themes matching the tag, appearance and search filters
+func runList(args []string) error {
+	fs := newFlagSet("list")
+	var tags stringList
+	fs.Var(&tags, "tag", "only themes with this tag (repeatable)")
+	appearance := fs.String("appearance", "", "only light or dark themes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter, err := theme.ParseFilter(strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+	filter.Tags = append(filter.Tags, tags...)
+	if *appearance != "" {
+		if filter.Appearance, err = theme.ParseAppearance(*appearance); err != nil {
+			return err
+		}
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	baseThemes := client.Registry().Families()
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFAMILY\tAPPEARANCE\tAUTHOR\tTAGS")
+	count := 0
+	for _, baseTheme := range theme.FilterBaseThemes(baseThemes, filter) {
+		for _, variant := range baseTheme.Variants {
+			t := variant.Theme(baseTheme)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Name, baseTheme.Name, t.ResolvedAppearance(), t.Author, strings.Join(t.Tags, ", "))
+			count++
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		fmt.Fprintf(stderr, "No themes match %q\n", filter.String())
+	}
+	return nil
+}
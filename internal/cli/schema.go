@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// runSchema prints the JSON Schema of theme files
+func runSchema(args []string) error {
+	fs := newFlagSet("schema")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	schema, err := theme.JSONSchema()
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(schema)
+	return err
+}
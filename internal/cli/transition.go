@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+	"github.com/dahromy/zakaranda/pkg/zakaranda"
+)
+
+// runTransition fades the palette-capable apps from one theme to another,
+// applying a blend of the two right away and then every --over/--steps
+func runTransition(args []string) error {
+	fs := newFlagSet("transition")
+	from := fs.String("from", "", "theme to start from")
+	to := fs.String("to", "", "theme to end with")
+	over := fs.Duration("over", time.Hour, "duration of the transition")
+	steps := fs.Int("steps", 12, "number of themes applied after the first")
+	var apps stringList
+	fs.Var(&apps, "app", "only apply to this app (repeatable; default: all apps that take a palette)")
+	dryRun := fs.Bool("dry-run", false, "show the steps without waiting or writing files")
+	root := fs.String("root", "", "directory to use instead of the home directory (skips changes outside it)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return fmt.Errorf("transition: --from and --to are required")
+	}
+	if *steps < 1 || *over < 0 {
+		return fmt.Errorf("transition: --steps must be at least 1 and --over not negative")
+	}
+
+	client, err := newClient(clientOptions(*dryRun, *root)...)
+	if err != nil {
+		return err
+	}
+	start, err := findTheme(client, *from)
+	if err != nil {
+		return err
+	}
+	end, err := findTheme(client, *to)
+	if err != nil {
+		return err
+	}
+
+	req := zakaranda.Request{Apps: apps, PaletteOnly: true}
+	if len(apps) == 0 {
+		for _, app := range client.Integrations() {
+			if _, ok := app.(zakaranda.PaletteIntegration); ok && app.IsInstalled() {
+				req.Integrations = append(req.Integrations, app)
+			}
+		}
+		if len(req.Integrations) == 0 {
+			return fmt.Errorf("transition: no installed app takes a palette")
+		}
+	}
+
+	interval := *over / time.Duration(*steps)
+	for i := 0; i <= *steps; i++ {
+		if i > 0 && !*dryRun {
+			time.Sleep(interval)
+		}
+		req.Theme = theme.Blend(start, end, float64(i)/float64(*steps))
+		req.Backup = i == 0 // So restore returns to before the transition
+		fmt.Fprintf(stdout, "Step %d/%d at +%s: %s\n", i, *steps, interval*time.Duration(i), req.Theme.Description)
+		results, err := client.Apply(req)
+		if err := reportResults("transition", results, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
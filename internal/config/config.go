@@ -14,6 +14,10 @@ type Config struct {
 	MaxBackups       int               `json:"max_backups"`
 	CustomThemesPath string            `json:"custom_themes_path"`
 	Preferences      map[string]string `json:"preferences"`
+
+	// SynthesizeBrights turns bright synthesis on or off per app name,
+	// whatever the theme asks for
+	SynthesizeBrights map[string]bool `json:"synthesize_brights,omitempty"`
 }
 
 type ConfigManager struct {
@@ -118,6 +122,10 @@ func (cm *ConfigManager) GetMaxBackups() int {
 	return cm.config.MaxBackups
 }
 
+func (cm *ConfigManager) GetBrightSynthesis() map[string]bool {
+	return cm.config.SynthesizeBrights
+}
+
 func (cm *ConfigManager) GetCustomThemesPath() string {
 	return cm.config.CustomThemesPath
 }
@@ -135,6 +143,21 @@ func (cm *ConfigManager) SetPreference(key, value string) error {
 	return cm.Save()
 }
 
+// preferredAppearanceKey stores which side of a light/dark pair is applied
+// to apps that can't switch with the system appearance
+const preferredAppearanceKey = "appearance"
+
+func (cm *ConfigManager) GetPreferredAppearance() string {
+	if val, ok := cm.GetPreference(preferredAppearanceKey); ok && val == "light" {
+		return "light"
+	}
+	return "dark"
+}
+
+func (cm *ConfigManager) SetPreferredAppearance(appearance string) error {
+	return cm.SetPreference(preferredAppearanceKey, appearance)
+}
+
 // Backup management
 func (cm *ConfigManager) CleanOldBackups(appName string) error {
 	if !cm.config.AutoBackup {
@@ -0,0 +1,151 @@
+// Package generator creates theme palettes from images and seed colors
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register the decoders image.Decode uses
+	_ "image/png"
+	"io"
+	"math"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// Sampling and clustering parameters
+const (
+	maxSamplesPerSide = 128 // Images are sampled on a grid of at most this many pixels per side
+	swatchCount       = 16  // Clusters the image is quantized to
+	kmeansIterations  = 24
+)
+
+// swatch is a color of the source with its share of the sampled pixels
+type swatch struct {
+	color  theme.OKLab
+	weight float64
+}
+
+// DecodeImage decodes a JPEG or PNG image
+func DecodeImage(r io.Reader) (image.Image, error) {
+	img, format, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, fmt.Errorf("unsupported image format %q", format)
+	}
+	return img, nil
+}
+
+// FromImage builds a palette from the colors of an image, quantized with
+// k-means in OKLab. With an empty appearance, the image's average lightness
+// decides between a dark and a light theme.
+func FromImage(img image.Image, appearance theme.Appearance) (theme.ColorPalette, theme.Appearance, error) {
+	samples := sampleImage(img)
+	if len(samples) == 0 {
+		return theme.ColorPalette{}, "", errors.New("image has no opaque pixels")
+	}
+
+	if appearance == "" {
+		var lightness float64
+		for _, s := range samples {
+			lightness += s.L
+		}
+		appearance = theme.AppearanceDark
+		if lightness/float64(len(samples)) > 0.6 {
+			appearance = theme.AppearanceLight
+		}
+	}
+	return buildPalette(kmeans(samples, swatchCount), appearance), appearance, nil
+}
+
+// sampleImage returns the opaque pixels of a grid over the image in OKLab
+func sampleImage(img image.Image) []theme.OKLab {
+	bounds := img.Bounds()
+	step := max(1, max(bounds.Dx(), bounds.Dy())/maxSamplesPerSide)
+
+	var samples []theme.OKLab
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Undo the premultiplied alpha of translucent pixels
+			c := theme.Color{R: uint8(r * 0xff / a), G: uint8(g * 0xff / a), B: uint8(b * 0xff / a), A: 0xff}
+			samples = append(samples, c.OKLab())
+		}
+	}
+	return samples
+}
+
+// kmeans clusters the samples into at most k swatches, sorted by weight.
+// Centers start at mutually distant samples (maximin), which is
+// deterministic and spreads them over the image's distinct colors.
+func kmeans(samples []theme.OKLab, k int) []swatch {
+	centers := []theme.OKLab{samples[0]}
+	nearest := make([]float64, len(samples))
+	for i, s := range samples {
+		nearest[i] = s.DistanceTo(centers[0])
+	}
+	for len(centers) < k {
+		far := 0
+		for i := range samples {
+			if nearest[i] > nearest[far] {
+				far = i
+			}
+		}
+		if nearest[far] == 0 {
+			break // Fewer distinct colors than clusters
+		}
+		centers = append(centers, samples[far])
+		for i, s := range samples {
+			nearest[i] = math.Min(nearest[i], s.DistanceTo(samples[far]))
+		}
+	}
+
+	assignment := make([]int, len(samples))
+	counts := make([]int, len(centers))
+	for iter := 0; iter < kmeansIterations; iter++ {
+		changed := false
+		for i, s := range samples {
+			best := 0
+			for j, c := range centers {
+				if s.DistanceTo(c) < s.DistanceTo(centers[best]) {
+					best = j
+				}
+			}
+			if assignment[i] != best || iter == 0 {
+				assignment[i], changed = best, true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([]theme.OKLab, len(centers))
+		counts = make([]int, len(centers))
+		for i, s := range samples {
+			j := assignment[i]
+			sums[j].L += s.L
+			sums[j].A += s.A
+			sums[j].B += s.B
+			counts[j]++
+		}
+		for j := range centers {
+			if n := float64(counts[j]); n > 0 {
+				centers[j] = theme.OKLab{L: sums[j].L / n, A: sums[j].A / n, B: sums[j].B / n}
+			}
+		}
+	}
+
+	var swatches []swatch
+	for j, c := range centers {
+		if counts[j] > 0 {
+			swatches = append(swatches, swatch{color: c, weight: float64(counts[j]) / float64(len(samples))})
+		}
+	}
+	sortSwatches(swatches)
+	return swatches
+}
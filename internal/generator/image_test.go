@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// stripes returns an image of vertical stripes, each color covering its
+// share of the width
+func stripes(width int, colors ...color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, 40))
+	for x := 0; x < width; x++ {
+		c := colors[x*len(colors)/width]
+		for y := 0; y < 40; y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// TestFromImage verifies generated palettes follow the image's lightness,
+// keep text readable and put each ANSI color near its named hue
+func TestFromImage(t *testing.T) {
+	night := stripes(400,
+		color.RGBA{0x10, 0x18, 0x30, 0xff}, color.RGBA{0x10, 0x18, 0x30, 0xff},
+		color.RGBA{0x20, 0x40, 0x90, 0xff}, color.RGBA{0xe0, 0x70, 0x30, 0xff})
+	snow := stripes(400,
+		color.RGBA{0xf4, 0xf4, 0xf8, 0xff}, color.RGBA{0xf4, 0xf4, 0xf8, 0xff},
+		color.RGBA{0xd0, 0xe0, 0xf0, 0xff}, color.RGBA{0x30, 0x80, 0x40, 0xff})
+
+	for _, tt := range []struct {
+		name string
+		img  image.Image
+		want theme.Appearance
+	}{
+		{"night", night, theme.AppearanceDark},
+		{"snow", snow, theme.AppearanceLight},
+	} {
+		p, appearance, err := FromImage(tt.img, "")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if appearance != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, appearance)
+		}
+		if err := p.Normalize(); err != nil {
+			t.Errorf("%s: invalid palette: %v", tt.name, err)
+		}
+
+		bg := theme.MustParseColor(p.Background)
+		if bg.IsLight() != (appearance == theme.AppearanceLight) {
+			t.Errorf("%s: background %s doesn't match the %s appearance", tt.name, p.Background, appearance)
+		}
+		if got := theme.ContrastRatio(theme.MustParseColor(p.Foreground), bg); got < foregroundContrast {
+			t.Errorf("%s: foreground contrast %.2f, expected at least %d", tt.name, got, foregroundContrast)
+		}
+
+		hues := map[string]float64{"red": 29, "yellow": 110, "green": 142, "cyan": 195, "blue": 264, "magenta": 328}
+		for slot, hex := range map[string]string{
+			"red": p.Red, "yellow": p.Yellow, "green": p.Green, "cyan": p.Cyan, "blue": p.Blue, "magenta": p.Magenta,
+		} {
+			c := theme.MustParseColor(hex)
+			if got := theme.ContrastRatio(c, bg); got < colorContrast {
+				t.Errorf("%s: %s %s has contrast %.2f, expected at least %.1f", tt.name, slot, hex, got, colorContrast)
+			}
+			if d := theme.HueDistance(c.OKLCH().H, hues[slot]); d > maxHueShift+5 {
+				t.Errorf("%s: %s %s is %.0f° from its hue", tt.name, slot, hex, d)
+			}
+		}
+	}
+}
+
+// TestFromImageForcedAppearance verifies a requested appearance overrides the image's
+func TestFromImageForcedAppearance(t *testing.T) {
+	img := stripes(100, color.RGBA{0xf0, 0xf0, 0xf0, 0xff})
+	p, appearance, err := FromImage(img, theme.AppearanceDark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if appearance != theme.AppearanceDark || theme.MustParseColor(p.Background).IsLight() {
+		t.Errorf("Expected a dark theme, got %s with background %s", appearance, p.Background)
+	}
+}
+
+// TestFromImageTransparent verifies fully transparent images are rejected
+func TestFromImageTransparent(t *testing.T) {
+	if _, _, err := FromImage(image.NewRGBA(image.Rect(0, 0, 10, 10)), ""); err == nil {
+		t.Error("Expected an error for an image without opaque pixels")
+	}
+}
+
+// TestDecodeImage verifies PNG images decode and other data is rejected
+func TestDecodeImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, stripes(10, color.RGBA{0xff, 0, 0, 0xff})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecodeImage(&buf); err != nil {
+		t.Errorf("Expected the PNG to decode, got %v", err)
+	}
+	if _, err := DecodeImage(bytes.NewReader([]byte("GIF89a"))); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// ansiHues are the OKLCH hues of the sRGB colors the ANSI colors are named
+// after. Generated colors stay within maxHueShift of them, so red still
+// reads as red whatever the image's nearest hue is.
+var ansiHues = []float64{29, 110, 142, 195, 264, 328} // Red, yellow, green, cyan, blue, magenta
+
+const (
+	maxHueShift = 15
+	minChroma   = 0.05 // Swatches below this are treated as grays
+)
+
+// Minimum WCAG contrast against the background
+const (
+	foregroundContrast = 7   // Body text (WCAG AAA)
+	colorContrast      = 4.5 // Normal ANSI colors (WCAG AA)
+	dimContrast        = 3   // Bright black, used for comments and hints
+)
+
+// tones are the target lightness and chroma scale of each kind of color for
+// one appearance
+type tones struct {
+	background, foreground [2]float64 // Lightness range of the background; foreground lightness
+	black, white           [2]float64 // Normal and bright lightness
+	normal, bright         float64    // Lightness of the six hues
+}
+
+var darkTones = tones{
+	background: [2]float64{0.14, 0.24}, foreground: [2]float64{0.90},
+	black: [2]float64{0.30, 0.55}, white: [2]float64{0.82, 0.95},
+	normal: 0.72, bright: 0.80,
+}
+
+var lightTones = tones{
+	background: [2]float64{0.94, 0.98}, foreground: [2]float64{0.28},
+	black: [2]float64{0.30, 0.55}, white: [2]float64{0.88, 0.96},
+	normal: 0.52, bright: 0.60,
+}
+
+// tonesFor returns the tones of an appearance
+func tonesFor(appearance theme.Appearance) tones {
+	if appearance == theme.AppearanceLight {
+		return lightTones
+	}
+	return darkTones
+}
+
+// sortSwatches orders swatches by weight, heaviest first
+func sortSwatches(swatches []swatch) {
+	sort.SliceStable(swatches, func(i, j int) bool {
+		return swatches[i].weight > swatches[j].weight
+	})
+}
+
+// buildPalette assigns the swatches of a source to the palette: a background
+// from its darkest (or lightest) prominent color, toned down to a calm,
+// near-neutral color, and the other colors as in derivePalette
+func buildPalette(swatches []swatch, appearance theme.Appearance) theme.ColorPalette {
+	t := tonesFor(appearance)
+
+	// The darkest (lightest) swatch covering a noticeable part of the image
+	base := swatches[0]
+	for _, s := range swatches {
+		if s.weight < 0.05 {
+			continue
+		}
+		if (appearance == theme.AppearanceLight) == (s.color.L > base.color.L) {
+			base = s
+		}
+	}
+	baseLCH := base.color.OKLCH()
+	bg := theme.OKLCH{
+		L: math.Max(t.background[0], math.Min(t.background[1], baseLCH.L)),
+		C: math.Min(baseLCH.C, 0.03),
+		H: baseLCH.H,
+	}
+	return derivePalette(bg.Color(), swatches, appearance)
+}
+
+// derivePalette builds the palette around a background: a readable
+// foreground and grays of the background's hue, and the ANSI colors from the
+// prominent swatches nearest each hue. Brights are lighter than the normals
+// and, as far as the gamut allows, more saturated.
+func derivePalette(bg theme.Color, swatches []swatch, appearance theme.Appearance) theme.ColorPalette {
+	t := tonesFor(appearance)
+	bgLCH := bg.OKLCH()
+
+	gray := func(l float64, min float64) string {
+		return theme.OKLCH{L: l, C: math.Min(bgLCH.C, 0.02), H: bgLCH.H}.WithContrast(bg, min).Hex()
+	}
+	p := theme.ColorPalette{
+		Background:  bg.Hex(),
+		Foreground:  gray(t.foreground[0], foregroundContrast),
+		Black:       theme.OKLCH{L: t.black[0], C: bgLCH.C, H: bgLCH.H}.Color().Hex(),
+		BrightBlack: gray(t.black[1], dimContrast),
+		White:       theme.OKLCH{L: t.white[0], C: math.Min(bgLCH.C, 0.02), H: bgLCH.H}.Color().Hex(),
+		BrightWhite: theme.OKLCH{L: t.white[1], C: math.Min(bgLCH.C, 0.01), H: bgLCH.H}.Color().Hex(),
+	}
+
+	chroma := medianChroma(swatches)
+	slots := [][2]*string{
+		{&p.Red, &p.BrightRed}, {&p.Yellow, &p.BrightYellow}, {&p.Green, &p.BrightGreen},
+		{&p.Cyan, &p.BrightCyan}, {&p.Blue, &p.BrightBlue}, {&p.Magenta, &p.BrightMagenta},
+	}
+	for i, target := range ansiHues {
+		hue, c := matchHue(swatches, target, chroma)
+		*slots[i][0] = theme.OKLCH{L: t.normal, C: c, H: hue}.WithContrast(bg, colorContrast).Hex()
+		*slots[i][1] = theme.OKLCH{L: t.bright, C: math.Min(c*1.15, 0.22), H: hue}.WithContrast(bg, dimContrast).Hex()
+	}
+
+	// Accent and selection: the most prominent colorful swatch
+	for _, s := range swatches {
+		lch := s.color.OKLCH()
+		if lch.C < minChroma*1.5 {
+			continue
+		}
+		c := math.Max(0.1, math.Min(lch.C, 0.2))
+		p.Accent = theme.OKLCH{L: t.normal, C: c, H: lch.H}.WithContrast(bg, dimContrast).Hex()
+		selection := theme.OKLCH{L: bgLCH.L + 0.12, C: c * 0.4, H: lch.H}
+		if appearance == theme.AppearanceLight {
+			selection.L = bgLCH.L - 0.12
+		}
+		p.SelectionBackground = selection.Color().Hex()
+		break
+	}
+	if p.Accent == "" {
+		p.Accent = p.Blue
+	}
+	return p
+}
+
+// matchHue returns the hue and chroma for the ANSI color at target: those of
+// the heaviest colorful swatch within 60° (its hue kept within maxHueShift of
+// the target), or the target hue at the source's typical chroma
+func matchHue(swatches []swatch, target, fallbackChroma float64) (float64, float64) {
+	best, bestScore := theme.OKLCH{}, 0.0
+	for _, s := range swatches {
+		lch := s.color.OKLCH()
+		d := theme.HueDistance(lch.H, target)
+		if lch.C < minChroma || d >= 60 {
+			continue
+		}
+		if score := s.weight * (1 - d/60); score > bestScore {
+			best, bestScore = lch, score
+		}
+	}
+	if bestScore == 0 {
+		return target, fallbackChroma
+	}
+
+	shift := math.Mod(best.H-target+540, 360) - 180 // Signed, in (-180, 180]
+	shift = math.Max(-maxHueShift, math.Min(maxHueShift, shift))
+	return math.Mod(target+shift+360, 360), math.Max(0.09, math.Min(best.C, 0.18))
+}
+
+// medianChroma returns the median chroma of the colorful swatches, clamped to
+// a range that keeps synthesized colors distinct but not garish
+func medianChroma(swatches []swatch) float64 {
+	var chromas []float64
+	for _, s := range swatches {
+		if c := s.color.OKLCH().C; c >= minChroma {
+			chromas = append(chromas, c)
+		}
+	}
+	if len(chromas) == 0 {
+		return 0.12
+	}
+	sort.Float64s(chromas)
+	return math.Max(0.09, math.Min(chromas[len(chromas)/2], 0.18))
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"math"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// Seed is the brand color a generated theme is built around, and optionally
+// its background
+type Seed struct {
+	Accent     theme.Color
+	Background *theme.Color // Used as is by the variant of its own appearance
+}
+
+// Palette builds a palette of the given appearance around the seed. The
+// background is the seed's, or else a near-neutral tint of the accent; the
+// ANSI colors sit at their conventional hues, those near the accent leaning
+// towards it, at the accent's chroma.
+func (s Seed) Palette(appearance theme.Appearance) theme.ColorPalette {
+	t := tonesFor(appearance)
+	accent := s.Accent.OKLCH()
+
+	var bg theme.Color
+	if s.Background != nil && theme.AppearanceOf(s.Background.Hex()) == appearance {
+		bg = *s.Background
+	} else {
+		tint := accent
+		if s.Background != nil {
+			tint = s.Background.OKLCH()
+		}
+		bg = theme.OKLCH{L: (t.background[0] + t.background[1]) / 2, C: math.Min(tint.C*0.2, 0.02), H: tint.H}.Color()
+	}
+
+	p := derivePalette(bg, []swatch{{color: s.Accent.OKLab(), weight: 1}}, appearance)
+	// Keep the brand color itself where it's readable
+	if theme.ContrastRatio(s.Accent, bg) >= dimContrast {
+		p.Accent = s.Accent.Hex()
+	}
+	return p
+}
+
+// Family returns a family with a dark and a light variant built from the seed
+func (s Seed) Family(name string) theme.BaseTheme {
+	family := theme.BaseTheme{
+		Name:        name,
+		Description: "Generated from " + s.Accent.Hex(),
+		Tags:        []string{"generated"},
+	}
+	for _, v := range []struct {
+		name       string
+		appearance theme.Appearance
+	}{
+		{"Dark", theme.AppearanceDark},
+		{"Light", theme.AppearanceLight},
+	} {
+		family.Variants = append(family.Variants, theme.ThemeVariant{
+			Name:        v.name,
+			DisplayName: v.name,
+			FullName:    name + " " + v.name,
+			Colors:      s.Palette(v.appearance),
+			Appearance:  v.appearance,
+		})
+	}
+	return family
+}
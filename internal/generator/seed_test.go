@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// TestSeedFamily verifies seed families have a readable dark and light
+// variant with conventional hues and brights lighter than the normals
+func TestSeedFamily(t *testing.T) {
+	seed := Seed{Accent: theme.MustParseColor("#e4572e")}
+	family := seed.Family("Acme")
+
+	if len(family.Variants) != 2 {
+		t.Fatalf("Expected 2 variants, got %d", len(family.Variants))
+	}
+	for _, v := range family.Variants {
+		p := v.Colors
+		if err := p.Normalize(); err != nil {
+			t.Fatalf("%s: invalid palette: %v", v.FullName, err)
+		}
+		bg := theme.MustParseColor(p.Background)
+		if theme.AppearanceOf(p.Background) != v.Appearance {
+			t.Errorf("%s: background %s doesn't match the %s appearance", v.FullName, p.Background, v.Appearance)
+		}
+		if got := theme.ContrastRatio(theme.MustParseColor(p.Foreground), bg); got < foregroundContrast {
+			t.Errorf("%s: foreground contrast %.2f", v.FullName, got)
+		}
+		if p.Accent != "#e4572e" {
+			t.Errorf("%s: expected the seed as accent, got %s", v.FullName, p.Accent)
+		}
+
+		pairs := [][3]string{
+			{"red", p.Red, p.BrightRed}, {"yellow", p.Yellow, p.BrightYellow}, {"green", p.Green, p.BrightGreen},
+			{"cyan", p.Cyan, p.BrightCyan}, {"blue", p.Blue, p.BrightBlue}, {"magenta", p.Magenta, p.BrightMagenta},
+		}
+		for i, pair := range pairs {
+			normal, bright := theme.MustParseColor(pair[1]).OKLCH(), theme.MustParseColor(pair[2]).OKLCH()
+			if d := theme.HueDistance(normal.H, ansiHues[i]); d > maxHueShift+5 {
+				t.Errorf("%s: %s %s is %.0f° from its hue", v.FullName, pair[0], pair[1], d)
+			}
+			if bright.L <= normal.L {
+				t.Errorf("%s: bright %s %s isn't lighter than %s", v.FullName, pair[0], pair[2], pair[1])
+			}
+			if got := theme.ContrastRatio(theme.MustParseColor(pair[1]), bg); got < colorContrast {
+				t.Errorf("%s: %s %s has contrast %.2f", v.FullName, pair[0], pair[1], got)
+			}
+		}
+	}
+}
+
+// TestSeedBackground verifies a seed background is kept by the variant of
+// its appearance and tints the other
+func TestSeedBackground(t *testing.T) {
+	bg := theme.MustParseColor("#fdf6e3")
+	seed := Seed{Accent: theme.MustParseColor("#268bd2"), Background: &bg}
+
+	if got := seed.Palette(theme.AppearanceLight).Background; got != "#fdf6e3" {
+		t.Errorf("Expected the light variant to keep the background, got %s", got)
+	}
+	dark := theme.MustParseColor(seed.Palette(theme.AppearanceDark).Background)
+	if dark.IsLight() {
+		t.Errorf("Expected a dark background for the dark variant, got %s", dark.Hex())
+	}
+	if d := theme.HueDistance(dark.OKLCH().H, bg.OKLCH().H); d > 20 {
+		t.Errorf("Expected the dark background to keep the seed background's hue, got %s", dark.Hex())
+	}
+}
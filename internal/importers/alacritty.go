@@ -0,0 +1,223 @@
+package importers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dahromy/zakaranda/internal/theme"
+	"gopkg.in/yaml.v3"
+)
+
+// Alacritty imports the colors of Alacritty theme files and configs
+var Alacritty theme.Importer = alacrittyImporter{}
+
+type alacrittyImporter struct{}
+
+func (alacrittyImporter) Name() string { return "alacritty" }
+
+func (alacrittyImporter) Detect(path string, data []byte) bool {
+	return hasExt(path, ".toml", ".yml", ".yaml") && bytes.Contains(data, []byte("colors")) && bytes.Contains(data, []byte("primary"))
+}
+
+func (alacrittyImporter) Parse(path string, data []byte) ([]theme.Theme, error) {
+	t, err := ParseAlacrittyColors(FileThemeName(path), path, data)
+	if err != nil {
+		return nil, err
+	}
+	return []theme.Theme{t}, nil
+}
+
+// AlacrittyFamilyName is the family the harvested Alacritty themes are registered as
+const AlacrittyFamilyName = "Alacritty community"
+
+// alacrittyColors is the colors table of an Alacritty config or theme file
+type alacrittyColors struct {
+	Primary   map[string]string `toml:"primary" yaml:"primary"`
+	Normal    map[string]string `toml:"normal" yaml:"normal"`
+	Bright    map[string]string `toml:"bright" yaml:"bright"`
+	Cursor    map[string]string `toml:"cursor" yaml:"cursor"`
+	Selection map[string]string `toml:"selection" yaml:"selection"`
+}
+
+// errNoAlacrittyColors is returned for configs that import their colors
+// instead of defining them
+var errNoAlacrittyColors = errors.New("no colors.primary table")
+
+// ParseAlacrittyColors converts the colors table of an Alacritty TOML or
+// YAML file (by the extension of path) into a theme with the given name.
+// Cursor and selection colors that follow the cell colors are left unset.
+func ParseAlacrittyColors(name, path string, data []byte) (theme.Theme, error) {
+	var file struct {
+		Colors alacrittyColors `toml:"colors" yaml:"colors"`
+	}
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(data, &file)
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		err = fmt.Errorf("unsupported Alacritty file type %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return theme.Theme{}, err
+	}
+	colors := file.Colors
+	if len(colors.Primary) == 0 {
+		return theme.Theme{}, errNoAlacrittyColors
+	}
+
+	// Themes without a bright table use the normal colors
+	if len(colors.Bright) == 0 {
+		colors.Bright = colors.Normal
+	}
+	c := func(table map[string]string, key string) string {
+		value := strings.TrimSpace(table[key])
+		if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+			return "#" + value[2:]
+		}
+		if strings.HasPrefix(value, "Cell") {
+			return "" // CellForeground or CellBackground
+		}
+		return value
+	}
+
+	t := theme.Theme{
+		Name: name,
+		Tags: []string{"alacritty"},
+		Colors: theme.ColorPalette{
+			Background:          c(colors.Primary, "background"),
+			Foreground:          c(colors.Primary, "foreground"),
+			Black:               c(colors.Normal, "black"),
+			Red:                 c(colors.Normal, "red"),
+			Green:               c(colors.Normal, "green"),
+			Yellow:              c(colors.Normal, "yellow"),
+			Blue:                c(colors.Normal, "blue"),
+			Magenta:             c(colors.Normal, "magenta"),
+			Cyan:                c(colors.Normal, "cyan"),
+			White:               c(colors.Normal, "white"),
+			BrightBlack:         c(colors.Bright, "black"),
+			BrightRed:           c(colors.Bright, "red"),
+			BrightGreen:         c(colors.Bright, "green"),
+			BrightYellow:        c(colors.Bright, "yellow"),
+			BrightBlue:          c(colors.Bright, "blue"),
+			BrightMagenta:       c(colors.Bright, "magenta"),
+			BrightCyan:          c(colors.Bright, "cyan"),
+			BrightWhite:         c(colors.Bright, "white"),
+			Cursor:              c(colors.Cursor, "cursor"),
+			CursorText:          c(colors.Cursor, "text"),
+			SelectionBackground: c(colors.Selection, "background"),
+			SelectionForeground: c(colors.Selection, "text"),
+		},
+	}
+	if err := t.Normalize(); err != nil {
+		return theme.Theme{}, err
+	}
+	return t, nil
+}
+
+// ImportAlacritty reads the Alacritty theme or config files in a file or,
+// recursively, in a directory, naming each theme after its file
+// ("tokyo_night_storm.toml" becomes "Tokyo Night Storm"). Files that fail to
+// parse are reported in the error; the others are still returned.
+func ImportAlacritty(path string) ([]theme.Theme, error) {
+	var themes []theme.Theme
+	var errs []error
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Skip .git and other hidden directories of a cloned repository
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".toml", ".yml", ".yaml":
+		default:
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		t, err := ParseAlacrittyColors(FileThemeName(p), p, data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+			return nil
+		}
+		themes = append(themes, t)
+		return nil
+	})
+	if err != nil {
+		return themes, err
+	}
+	return themes, errors.Join(errs...)
+}
+
+// AlacrittyCommunityFamily gathers the themes of the alacritty-theme
+// repository in themesDir and the colors of the user's config, if it defines
+// any, into one family. Variants are named "<theme> (Alacritty)" so they don't
+// clash with built-in themes of the same name. The error reports files that
+// failed to parse; the family holds every other theme and may be empty.
+func AlacrittyCommunityFamily(themesDir, configPath string) (theme.BaseTheme, error) {
+	family := theme.BaseTheme{
+		Name:        AlacrittyFamilyName,
+		Description: "Themes from the alacritty-theme repository and your Alacritty config",
+		Homepage:    "https://github.com/alacritty/alacritty-theme",
+		Tags:        []string{"alacritty"},
+	}
+
+	var errs []error
+	if data, err := os.ReadFile(configPath); err == nil {
+		t, err := ParseAlacrittyColors("Current Config", configPath, data)
+		switch {
+		case err == nil:
+			family.Variants = append(family.Variants, alacrittyVariant(t))
+		case !errors.Is(err, errNoAlacrittyColors):
+			errs = append(errs, fmt.Errorf("%s: %w", configPath, err))
+		}
+	}
+
+	if _, err := os.Stat(themesDir); err == nil {
+		themes, err := ImportAlacritty(themesDir)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		seen := make(map[string]bool)
+		for _, t := range themes {
+			// The repository has a few themes in both TOML and YAML
+			if seen[t.Name] {
+				continue
+			}
+			seen[t.Name] = true
+			family.Variants = append(family.Variants, alacrittyVariant(t))
+		}
+	}
+	return family, errors.Join(errs...)
+}
+
+// alacrittyVariant makes a harvested theme a variant of the community family
+func alacrittyVariant(t theme.Theme) theme.ThemeVariant {
+	appearance := t.ResolvedAppearance()
+	title := "Dark"
+	if appearance == theme.AppearanceLight {
+		title = "Light"
+	}
+	return theme.ThemeVariant{
+		Name:        t.Name,
+		DisplayName: fmt.Sprintf("%s (%s)", t.Name, title),
+		FullName:    t.Name + " (Alacritty)",
+		Colors:      t.Colors,
+		Appearance:  appearance,
+	}
+}
@@ -0,0 +1,170 @@
+package importers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+const alacrittyTOML = `[colors.primary]
+background = '#282a36'
+foreground = '#f8f8f2'
+
+[colors.cursor]
+text = 'CellBackground'
+cursor = 'CellForeground'
+
+[colors.selection]
+text = 'CellForeground'
+background = '#44475a'
+
+[colors.normal]
+black = '#21222c'
+red = '#ff5555'
+green = '#50fa7b'
+yellow = '#f1fa8c'
+blue = '#bd93f9'
+magenta = '#ff79c6'
+cyan = '#8be9fd'
+white = '#f8f8f2'
+
+[colors.bright]
+black = '#6272a4'
+red = '#ff6e6e'
+green = '#69ff94'
+yellow = '#ffffa5'
+blue = '#d6acff'
+magenta = '#ff92df'
+cyan = '#a4ffff'
+white = '#ffffff'
+`
+
+const alacrittyYAML = `colors:
+  primary:
+    background: '0xfdf6e3'
+    foreground: '0x586e75'
+  cursor:
+    text: '0xfdf6e3'
+    cursor: '0x586e75'
+  normal:
+    black:   '0x073642'
+    red:     '0xdc322f'
+    green:   '0x859900'
+    yellow:  '0xb58900'
+    blue:    '0x268bd2'
+    magenta: '0xd33682'
+    cyan:    '0x2aa198'
+    white:   '0xeee8d5'
+`
+
+// writeFiles writes files below dir, creating directories as needed
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestParseAlacrittyTOML verifies the color tables of a TOML theme and that
+// cursor and selection colors following the cell colors stay unset
+func TestParseAlacrittyTOML(t *testing.T) {
+	th, err := ParseAlacrittyColors("Dracula", "dracula.toml", []byte(alacrittyTOML))
+	if err != nil {
+		t.Fatalf("ParseAlacrittyColors failed: %v", err)
+	}
+
+	checks := map[string][2]string{
+		"background":           {th.Colors.Background, "#282a36"},
+		"magenta":              {th.Colors.Magenta, "#ff79c6"},
+		"bright_white":         {th.Colors.BrightWhite, "#ffffff"},
+		"cursor":               {th.Colors.Cursor, ""},
+		"selection_foreground": {th.Colors.SelectionForeground, ""},
+		"selection_background": {th.Colors.SelectionBackground, "#44475a"},
+	}
+	for slot, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %q, got %q", slot, c[1], c[0])
+		}
+	}
+}
+
+// TestParseAlacrittyYAML verifies 0x colors and that a missing bright table
+// falls back to the normal colors
+func TestParseAlacrittyYAML(t *testing.T) {
+	th, err := ParseAlacrittyColors("Solarized Light", "solarized_light.yml", []byte(alacrittyYAML))
+	if err != nil {
+		t.Fatalf("ParseAlacrittyColors failed: %v", err)
+	}
+	if th.Colors.Background != "#fdf6e3" || th.Colors.BrightRed != "#dc322f" || th.Colors.Cursor != "#586e75" {
+		t.Errorf("Unexpected colors: %+v", th.Colors)
+	}
+	if th.ResolvedAppearance() != theme.AppearanceLight {
+		t.Errorf("Expected a light theme, got %s", th.ResolvedAppearance())
+	}
+}
+
+// TestParseAlacrittyWithoutColors verifies that configs importing their
+// colors are told apart from broken files
+func TestParseAlacrittyWithoutColors(t *testing.T) {
+	config := "[general]\nimport = ['~/.config/alacritty/themes/alacritty/themes/nord.toml']\n"
+	if _, err := ParseAlacrittyColors("Config", "alacritty.toml", []byte(config)); !errors.Is(err, errNoAlacrittyColors) {
+		t.Errorf("Expected errNoAlacrittyColors, got %v", err)
+	}
+	if _, err := ParseAlacrittyColors("Broken", "broken.toml", []byte("[colors.primary]\nbackground = '#000000'\n")); err == nil || errors.Is(err, errNoAlacrittyColors) {
+		t.Errorf("Expected missing colors to be reported, got %v", err)
+	}
+}
+
+// TestAlacrittyCommunityFamily verifies that the repository themes and the
+// user's config become one family that registers next to the built-ins
+func TestAlacrittyCommunityFamily(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"repo/themes/dracula.toml":         alacrittyTOML,
+		"repo/themes/solarized_light.yaml": alacrittyYAML,
+		"repo/themes/solarized_light.toml": alacrittyTOML, // Same name as the YAML file
+		"repo/themes/.git/config.toml":     "not a theme",
+		"alacritty.toml":                   alacrittyTOML,
+	})
+
+	family, err := AlacrittyCommunityFamily(filepath.Join(dir, "repo", "themes"), filepath.Join(dir, "alacritty.toml"))
+	if err != nil {
+		t.Fatalf("AlacrittyCommunityFamily failed: %v", err)
+	}
+
+	var names []string
+	for _, variant := range family.Variants {
+		names = append(names, variant.FullName)
+	}
+	want := []string{"Current Config (Alacritty)", "Dracula (Alacritty)", "Solarized Light (Alacritty)"}
+	if len(names) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Expected variant %d to be %q, got %q", i, want[i], names[i])
+		}
+	}
+	if family.Variants[1].DisplayName != "Dracula (Dark)" {
+		t.Errorf("Expected display name Dracula (Dark), got %q", family.Variants[1].DisplayName)
+	}
+
+	if err := theme.NewBuiltInRegistry().Register(family); err != nil {
+		t.Errorf("Expected the family to register next to the built-ins: %v", err)
+	}
+
+	// Nothing cloned and no config yet
+	family, err = AlacrittyCommunityFamily(filepath.Join(dir, "missing"), filepath.Join(dir, "missing.toml"))
+	if err != nil || len(family.Variants) != 0 {
+		t.Errorf("Expected an empty family without errors, got %d variants (%v)", len(family.Variants), err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package importers converts color schemes from other tools into themes
+package importers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+	"gopkg.in/yaml.v3"
+)
+
+// Base16 imports base16 and base24 scheme files
+var Base16 theme.Importer = base16Importer{}
+
+type base16Importer struct{}
+
+func (base16Importer) Name() string { return "base16" }
+
+func (base16Importer) Detect(path string, data []byte) bool {
+	return hasExt(path, ".yaml", ".yml") && bytes.Contains(data, []byte("base00")) && bytes.Contains(bytes.ToLower(data), []byte("base0f"))
+}
+
+func (base16Importer) Parse(path string, data []byte) ([]theme.Theme, error) {
+	t, err := ParseBase16(data)
+	if err != nil {
+		return nil, err
+	}
+	return []theme.Theme{t}, nil
+}
+
+// base16Scheme is a tinted-theming base16 or base24 scheme. Legacy schemes
+// keep the name in Scheme and the colors at the top level; current ones use
+// Name, System, Variant and a Palette map.
+type base16Scheme struct {
+	System  string            `yaml:"system"`
+	Name    string            `yaml:"name"`
+	Scheme  string            `yaml:"scheme"`
+	Author  string            `yaml:"author"`
+	Variant string            `yaml:"variant"`
+	Palette map[string]string `yaml:"palette"`
+}
+
+// ParseBase16 converts a base16 or base24 scheme into a theme, using the
+// standard terminal mapping of each system. Base24 schemes also fill the
+// bright colors from base12–base17. The cursor and selection come from the
+// styling guidelines: base05, the default foreground, and base02.
+func ParseBase16(data []byte) (theme.Theme, error) {
+	var scheme base16Scheme
+	if err := yaml.Unmarshal(data, &scheme); err != nil {
+		return theme.Theme{}, err
+	}
+	if scheme.Palette == nil {
+		// Legacy schemes have the colors at the top level; decoding into
+		// strings keeps unquoted values such as 181818 as written
+		if err := yaml.Unmarshal(data, &scheme.Palette); err != nil {
+			return theme.Theme{}, err
+		}
+	}
+
+	name := scheme.Name
+	if name == "" {
+		name = scheme.Scheme
+	}
+	if name == "" {
+		return theme.Theme{}, fmt.Errorf("missing scheme name")
+	}
+
+	base := func(n int) string {
+		value := strings.TrimSpace(scheme.Palette[fmt.Sprintf("base%02X", n)])
+		if value != "" && !strings.HasPrefix(value, "#") {
+			value = "#" + value
+		}
+		return value
+	}
+	if base(0x00) == "" || base(0x05) == "" {
+		return theme.Theme{}, fmt.Errorf("%s: missing base00 or base05", name)
+	}
+
+	t := theme.Theme{
+		Name:   name,
+		Author: scheme.Author,
+		Tags:   []string{"base16"},
+		Colors: theme.ColorPalette{
+			Background:    base(0x00),
+			Foreground:    base(0x05),
+			Black:         base(0x00),
+			Red:           base(0x08),
+			Green:         base(0x0B),
+			Yellow:        base(0x0A),
+			Blue:          base(0x0D),
+			Magenta:       base(0x0E),
+			Cyan:          base(0x0C),
+			White:         base(0x05),
+			BrightBlack:   base(0x03),
+			BrightRed:     base(0x08),
+			BrightGreen:   base(0x0B),
+			BrightYellow:  base(0x0A),
+			BrightBlue:    base(0x0D),
+			BrightMagenta: base(0x0E),
+			BrightCyan:    base(0x0C),
+			BrightWhite:   base(0x07),
+
+			Cursor:              base(0x05),
+			SelectionBackground: base(0x02),
+		},
+	}
+
+	if strings.EqualFold(scheme.System, "base24") || base(0x12) != "" {
+		t.Tags = []string{"base24"}
+		c := &t.Colors
+		c.Black, c.White, c.BrightBlack = base(0x01), base(0x06), base(0x02)
+		c.BrightRed, c.BrightYellow, c.BrightGreen = base(0x12), base(0x13), base(0x14)
+		c.BrightCyan, c.BrightBlue, c.BrightMagenta = base(0x15), base(0x16), base(0x17)
+	}
+
+	if scheme.Variant != "" {
+		appearance, err := theme.ParseAppearance(scheme.Variant)
+		if err != nil {
+			return theme.Theme{}, fmt.Errorf("%s: variant: %w", name, err)
+		}
+		t.Appearance = appearance
+	}
+
+	if err := t.Normalize(); err != nil {
+		return theme.Theme{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return t, nil
+}
+
+// ImportBase16 reads the base16 and base24 schemes in a file or, recursively,
+// in a directory, skipping hidden directories and files that aren't schemes.
+// Schemes that fail to parse are reported in the error; the others are still
+// returned.
+func ImportBase16(path string) ([]theme.Theme, error) {
+	return Import(Base16, path)
+}
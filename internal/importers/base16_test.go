@@ -0,0 +1,176 @@
+package importers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+const legacyBase16 = `scheme: "Ocean"
+author: "Chris Kempson"
+base00: "2b303b"
+base01: "343d46"
+base02: "4f5b66"
+base03: "65737e"
+base04: "a7adba"
+base05: "c0c5ce"
+base06: "dfe1e8"
+base07: "eff1f5"
+base08: "bf616a"
+base09: "d08770"
+base0A: "ebcb8b"
+base0B: "a3be8c"
+base0C: "96b5b4"
+base0D: "8fa1b3"
+base0E: "b48ead"
+base0F: "ab7967"
+`
+
+const base24Scheme = `system: "base24"
+name: "Harbor Light"
+author: "Test"
+variant: "light"
+palette:
+  base00: "#fafafa"
+  base01: "#e5e5e6"
+  base02: "#d4d4d4"
+  base03: "#a0a1a7"
+  base04: "#696c77"
+  base05: "#383a42"
+  base06: "#202227"
+  base07: "#090a0b"
+  base08: "#ca1243"
+  base09: "#d75f00"
+  base0A: "#c18401"
+  base0B: "#50a14f"
+  base0C: "#0184bc"
+  base0D: "#4078f2"
+  base0E: "#a626a4"
+  base0F: "#986801"
+  base10: "#f0f0f0"
+  base11: "#e0e0e0"
+  base12: "#ec2258"
+  base13: "#f4a701"
+  base14: "#6db76c"
+  base15: "#01a7ef"
+  base16: "#709af5"
+  base17: "#d02fcd"
+`
+
+// TestParseBase16Legacy verifies the base16 terminal mapping of a legacy scheme
+func TestParseBase16Legacy(t *testing.T) {
+	th, err := ParseBase16([]byte(legacyBase16))
+	if err != nil {
+		t.Fatalf("ParseBase16 failed: %v", err)
+	}
+
+	if th.Name != "Ocean" || th.Author != "Chris Kempson" {
+		t.Errorf("Expected Ocean by Chris Kempson, got %q by %q", th.Name, th.Author)
+	}
+	checks := map[string][2]string{
+		"background":   {th.Colors.Background, "#2b303b"},
+		"foreground":   {th.Colors.Foreground, "#c0c5ce"},
+		"red":          {th.Colors.Red, "#bf616a"},
+		"blue":         {th.Colors.Blue, "#8fa1b3"},
+		"bright_black": {th.Colors.BrightBlack, "#65737e"},
+		"bright_red":   {th.Colors.BrightRed, "#bf616a"},
+		"bright_white": {th.Colors.BrightWhite, "#eff1f5"},
+		"cursor":       {th.Colors.Cursor, "#c0c5ce"},
+		"selection":    {th.Colors.SelectionBackground, "#4f5b66"},
+	}
+	for slot, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %s, got %s", slot, c[1], c[0])
+		}
+	}
+	if th.ResolvedAppearance() != theme.AppearanceDark {
+		t.Errorf("Expected a dark theme, got %s", th.ResolvedAppearance())
+	}
+}
+
+// TestParseBase16UnquotedColors verifies that unquoted hex values keep their digits
+func TestParseBase16UnquotedColors(t *testing.T) {
+	data := []byte("scheme: Digits\nbase00: 000000\nbase05: 181818\nbase03: 101010\nbase07: ffffff\n" +
+		"base08: 800000\nbase0A: 808000\nbase0B: 008000\nbase0C: 008080\nbase0D: 000080\nbase0E: 800080\n")
+	th, err := ParseBase16(data)
+	if err != nil {
+		t.Fatalf("ParseBase16 failed: %v", err)
+	}
+	if th.Colors.Background != "#000000" || th.Colors.Foreground != "#181818" {
+		t.Errorf("Expected #000000 on #181818, got %s on %s", th.Colors.Foreground, th.Colors.Background)
+	}
+}
+
+// TestParseBase24 verifies that base24 schemes fill the bright colors and keep their variant
+func TestParseBase24(t *testing.T) {
+	th, err := ParseBase16([]byte(base24Scheme))
+	if err != nil {
+		t.Fatalf("ParseBase16 failed: %v", err)
+	}
+
+	if th.Appearance != theme.AppearanceLight {
+		t.Errorf("Expected the light variant, got %q", th.Appearance)
+	}
+	if th.Tags[0] != "base24" {
+		t.Errorf("Expected the base24 tag, got %v", th.Tags)
+	}
+	checks := map[string][2]string{
+		"black":          {th.Colors.Black, "#e5e5e6"},
+		"white":          {th.Colors.White, "#202227"},
+		"bright_black":   {th.Colors.BrightBlack, "#d4d4d4"},
+		"bright_red":     {th.Colors.BrightRed, "#ec2258"},
+		"bright_yellow":  {th.Colors.BrightYellow, "#f4a701"},
+		"bright_magenta": {th.Colors.BrightMagenta, "#d02fcd"},
+	}
+	for slot, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %s, got %s", slot, c[1], c[0])
+		}
+	}
+}
+
+// TestParseBase16Invalid verifies that incomplete schemes are rejected
+func TestParseBase16Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no name":     "base00: \"000000\"\nbase05: \"ffffff\"\n",
+		"no colors":   "scheme: Empty\n",
+		"bad color":   "scheme: Bad\nbase00: \"zzzzzz\"\nbase05: \"ffffff\"\n",
+		"bad variant": "name: Odd\nvariant: dim\npalette:\n  base00: \"000000\"\n  base05: \"ffffff\"\n",
+	}
+	for name, data := range tests {
+		if _, err := ParseBase16([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// TestImportBase16Directory verifies that directories are walked, skipping
+// hidden directories and files that aren't schemes, and bad schemes reported
+func TestImportBase16Directory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ocean.yaml":          legacyBase16,
+		"base24/harbor.yml":   base24Scheme,
+		"broken.yaml":         "scheme: Broken\nbase00: \"000000\"\nbase0F: \"ffffff\"\n",
+		"README.md":           "# not a scheme",
+		"base24/notes/x.json": "{}",
+		"base24/notes/x.yaml": "title: notes\n",
+		".github/ci.yml":      "# checks base00 to base0F\non: push\n",
+	}
+	writeFiles(t, dir, files)
+
+	themes, err := ImportBase16(dir)
+	if err == nil || !strings.Contains(err.Error(), "broken.yaml") || strings.Contains(err.Error(), "ci.yml") {
+		t.Errorf("Expected an error for broken.yaml only, got %v", err)
+	}
+	if len(themes) != 2 {
+		t.Fatalf("Expected 2 themes, got %d", len(themes))
+	}
+
+	themes, err = ImportBase16(filepath.Join(dir, "ocean.yaml"))
+	if err != nil || len(themes) != 1 || themes[0].Name != "Ocean" {
+		t.Errorf("Expected Ocean from a single file, got %v (%v)", themes, err)
+	}
+}
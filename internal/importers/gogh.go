@@ -0,0 +1,71 @@
+package importers
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+	"gopkg.in/yaml.v3"
+)
+
+// Gogh imports the YAML themes of the Gogh collection
+var Gogh theme.Importer = goghImporter{}
+
+type goghImporter struct{}
+
+func (goghImporter) Name() string { return "gogh" }
+
+func (goghImporter) Detect(path string, data []byte) bool {
+	return hasExt(path, ".yml", ".yaml") && bytes.Contains(data, []byte("color_01"))
+}
+
+func (goghImporter) Parse(path string, data []byte) ([]theme.Theme, error) {
+	t, err := ParseGogh(data)
+	if err != nil {
+		return nil, err
+	}
+	if t.Name == "" {
+		t.Name = FileThemeName(path)
+	}
+	return []theme.Theme{t}, nil
+}
+
+// ParseGogh converts a Gogh theme, whose color_01–color_08 are the normal and
+// color_09–color_16 the bright colors, into a theme
+func ParseGogh(data []byte) (theme.Theme, error) {
+	var file map[string]string
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return theme.Theme{}, err
+	}
+
+	t := theme.Theme{
+		Name:   file["name"],
+		Author: file["author"],
+		Tags:   []string{"gogh"},
+		Colors: theme.ColorPalette{
+			Background: file["background"],
+			Foreground: file["foreground"],
+			Cursor:     file["cursor"],
+		},
+	}
+	for i, slot := range []*string{
+		&t.Colors.Black, &t.Colors.Red, &t.Colors.Green, &t.Colors.Yellow,
+		&t.Colors.Blue, &t.Colors.Magenta, &t.Colors.Cyan, &t.Colors.White,
+		&t.Colors.BrightBlack, &t.Colors.BrightRed, &t.Colors.BrightGreen, &t.Colors.BrightYellow,
+		&t.Colors.BrightBlue, &t.Colors.BrightMagenta, &t.Colors.BrightCyan, &t.Colors.BrightWhite,
+	} {
+		*slot = file[fmt.Sprintf("color_%02d", i+1)]
+	}
+	if variant := file["variant"]; variant != "" {
+		appearance, err := theme.ParseAppearance(variant)
+		if err != nil {
+			return theme.Theme{}, fmt.Errorf("variant: %w", err)
+		}
+		t.Appearance = appearance
+	}
+
+	if err := t.Normalize(); err != nil {
+		return theme.Theme{}, err
+	}
+	return t, nil
+}
@@ -0,0 +1,103 @@
+package importers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// All returns the file importers in detection order, most specific first
+func All() []theme.Importer {
+	return []theme.Importer{ITerm2, WindowsTerminal, Base16, Gogh, Warp, Alacritty, Kitty, Xresources}
+}
+
+// Import converts a file with importer or, recursively, the files of a
+// directory that importer detects. Files that fail to parse are reported in
+// the error; the others are still returned.
+func Import(importer theme.Importer, path string) ([]theme.Theme, error) {
+	return importFiles(path, func(p string, data []byte) (theme.Importer, bool) {
+		return importer, p == path || importer.Detect(p, data)
+	})
+}
+
+// ImportDetected converts a file or, recursively, the files of a directory,
+// detecting the format of each. Files in a directory whose format isn't
+// recognized are skipped; a file given directly is reported.
+func ImportDetected(path string) ([]theme.Theme, error) {
+	return importFiles(path, func(p string, data []byte) (theme.Importer, bool) {
+		return theme.DetectImporter(All(), p, data)
+	})
+}
+
+// importFiles walks path, skipping hidden directories, and parses each file
+// with the importer pick returns for it
+func importFiles(path string, pick func(p string, data []byte) (theme.Importer, bool)) ([]theme.Theme, error) {
+	var themes []theme.Theme
+	var errs []error
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		importer, ok := pick(p, data)
+		if !ok {
+			if p == path {
+				errs = append(errs, fmt.Errorf("%s: unrecognized color scheme format", p))
+			}
+			return nil
+		}
+		parsed, err := importer.Parse(p, data)
+		themes = append(themes, parsed...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+		}
+		return nil
+	})
+	if err != nil {
+		return themes, err
+	}
+	return themes, errors.Join(errs...)
+}
+
+// FileThemeName turns a theme file name into a theme name
+// ("tokyo_night_storm.toml" becomes "Tokyo Night Storm", ".Xresources" "Xresources")
+func FileThemeName(path string) string {
+	base := strings.TrimPrefix(filepath.Base(path), ".")
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	if name == "" {
+		name = base
+	}
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' '
+	})
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// hasExt reports whether path has one of the extensions, ignoring case
+func hasExt(path string, exts ...string) bool {
+	ext := filepath.Ext(path)
+	for _, e := range exts {
+		if strings.EqualFold(ext, e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package importers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+const kittyTheme = `# vim:ft=kitty
+## name: Harbor Night
+## author: Acme
+
+background #1d2021
+foreground	#ebdbb2
+cursor #fe8019
+cursor_text_color background
+selection_foreground none
+selection_background #504945
+color0 #282828
+color1 #cc241d
+color2 #98971a
+color3 #d79921
+color4 #458588
+color5 #b16286
+color6 #689d6a
+color7 #a89984
+color8 #928374
+color9 #fb4934
+color10 #b8bb26
+color11 #fabd2f
+color12 #83a598
+color13 #d3869b
+color14 #8ec07c
+color15 #ebdbb2
+color16 #fe8019
+`
+
+const xresourcesTheme = `! base16-xresources style
+#define base00 #1d2021
+#define base05 #ebdbb2
+#define red base08
+#define base08 #fb4934
+
+*.foreground:   base05
+*.background:   base00
+*.cursorColor:  base05
+URxvt*color0:   rgb:28/28/28
+*.color1:       red
+*color2:        #98971a
+*.color3:       rgb:d/9/2
+*.color4:       #458588
+*.color5:       #b16286
+*.color6:       #689d6a
+*.color7:       #a89984
+*.color8:       #928374
+*.color9:       #fb4934
+*.color10:      #b8bb26
+*.color11:      #fabd2f
+*.color12:      #83a598
+*.color13:      #d3869b
+*.color14:      #8ec07c
+*.color15:      #ebdbb2
+URxvt.font:     xft:Iosevka:size=12
+`
+
+const windowsTerminalSettings = `{
+	// Windows Terminal settings
+	"defaultProfile": "{61c54bbd-c053-5c2c-86d3-cc9b7fb3b5b7}",
+	"schemes": [
+		{
+			"name": "Harbor Night",
+			"background": "#1D2021", "foreground": "#EBDBB2",
+			"cursorColor": "#FE8019", "selectionBackground": "#504945",
+			"black": "#282828", "red": "#CC241D", "green": "#98971A", "yellow": "#D79921",
+			"blue": "#458588", "purple": "#B16286", "cyan": "#689D6A", "white": "#A89984",
+			"brightBlack": "#928374", "brightRed": "#FB4934", "brightGreen": "#B8BB26", "brightYellow": "#FABD2F",
+			"brightBlue": "#83A598", "brightPurple": "#D3869B", "brightCyan": "#8EC07C", "brightWhite": "#EBDBB2",
+		},
+		{"name": "Broken", "purple": "#b16286"},
+	],
+}`
+
+const goghTheme = `---
+name: 'Harbor Day'
+author: 'Acme'
+variant: 'light'
+
+color_01: '#fbf1c7'    # Black (Host)
+color_02: '#cc241d'    # Red (Syntax string)
+color_03: '#98971a'    # Green (Command)
+color_04: '#d79921'    # Yellow (Command second)
+color_05: '#458588'    # Blue (Path)
+color_06: '#b16286'    # Magenta (Syntax var)
+color_07: '#689d6a'    # Cyan (Prompt)
+color_08: '#7c6f64'    # White
+
+color_09: '#928374'    # Bright Black
+color_10: '#9d0006'    # Bright Red (Command error)
+color_11: '#79740e'    # Bright Green (Exec)
+color_12: '#b57614'    # Bright Yellow
+color_13: '#076678'    # Bright Blue (Folder)
+color_14: '#8f3f71'    # Bright Magenta
+color_15: '#427b58'    # Bright Cyan
+color_16: '#3c3836'    # Bright White
+
+background: '#fbf1c7'  # Background
+foreground: '#3c3836'  # Foreground (Text)
+
+cursor: '#3c3836'      # Cursor
+`
+
+// TestDetectImporter verifies that each format is recognized by name and
+// content, including files without their usual extension
+func TestDetectImporter(t *testing.T) {
+	tests := []struct {
+		path, data, want string
+	}{
+		{"Harbor.itermcolors", iTermPresetXML(testITermColors(), "sRGB"), "iterm2"},
+		{"settings.json", windowsTerminalSettings, "windows-terminal"},
+		{"ocean.yaml", "scheme: Ocean\nbase00: '2b303b'\nbase0F: 'ab7967'\n", "base16"},
+		{"harbor-day.yml", goghTheme, "gogh"},
+		{"dracula.toml", alacrittyTOML, "alacritty"},
+		{"harbor.conf", kittyTheme, "kitty"},
+		{"harbor-night", kittyTheme, "kitty"},
+		{".Xresources", xresourcesTheme, "xresources"},
+		{"colors", xresourcesTheme, "xresources"},
+		{"package.json", `{"name": "harbor", "version": "1.0.0"}`, ""},
+		{"notes.txt", "background is dark", ""},
+	}
+	for _, tt := range tests {
+		importer, ok := theme.DetectImporter(All(), tt.path, []byte(tt.data))
+		got := ""
+		if ok {
+			got = importer.Name()
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.want, got)
+		}
+	}
+}
+
+// TestParseKittyColors verifies the kitty options, the kitty-themes metadata
+// comments and that colors following the cell colors stay unset
+func TestParseKittyColors(t *testing.T) {
+	themes, err := Kitty.Parse("harbor.conf", []byte(kittyTheme))
+	if err != nil || len(themes) != 1 {
+		t.Fatalf("Expected 1 theme, got %d (%v)", len(themes), err)
+	}
+	th := themes[0]
+	checks := map[string][2]string{
+		"name":                 {th.Name, "Harbor Night"},
+		"author":               {th.Author, "Acme"},
+		"foreground":           {th.Colors.Foreground, "#ebdbb2"},
+		"bright_white":         {th.Colors.BrightWhite, "#ebdbb2"},
+		"cursor":               {th.Colors.Cursor, "#fe8019"},
+		"cursor_text":          {th.Colors.CursorText, ""},
+		"selection_foreground": {th.Colors.SelectionForeground, ""},
+		"selection_background": {th.Colors.SelectionBackground, "#504945"},
+	}
+	for field, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %q, got %q", field, c[1], c[0])
+		}
+	}
+
+	// A kitty.conf setting only some colors, and one setting none
+	th, err = ParseKittyColors("Kitty", []byte("font_size 12\nbackground #101010\ncolor1 #ff0000\n"))
+	if err != nil || th.Colors.Background != "#101010" || th.Colors.Red != "#ff0000" || th.Colors.Green != "#19cb00" {
+		t.Errorf("Expected kitty's defaults for the missing colors, got %+v (%v)", th.Colors, err)
+	}
+	if _, err := ParseKittyColors("Fonts", []byte("font_size 12\n")); err == nil {
+		t.Error("Expected a config without colors to be rejected")
+	}
+}
+
+// TestParseXresources verifies resource matching, #define indirection and
+// the X11 rgb: notation
+func TestParseXresources(t *testing.T) {
+	themes, err := Xresources.Parse("/home/me/.Xresources", []byte(xresourcesTheme))
+	if err != nil || len(themes) != 1 {
+		t.Fatalf("Expected 1 theme, got %d (%v)", len(themes), err)
+	}
+	th := themes[0]
+	checks := map[string][2]string{
+		"name":       {th.Name, "Xresources"},
+		"background": {th.Colors.Background, "#1d2021"},
+		"cursor":     {th.Colors.Cursor, "#ebdbb2"},
+		"black":      {th.Colors.Black, "#282828"},
+		"red":        {th.Colors.Red, "#fb4934"}, // Through two defines
+		"yellow":     {th.Colors.Yellow, "#dd9922"},
+	}
+	for field, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %q, got %q", field, c[1], c[0])
+		}
+	}
+
+	if _, err := ParseXresources("Bad", []byte("*.color0: rgb:12/34\n")); err == nil {
+		t.Error("Expected an invalid rgb: color to be rejected")
+	}
+}
+
+// TestParseWindowsTerminal verifies the schemes of a settings.json with
+// comments and that a broken scheme doesn't stop the others
+func TestParseWindowsTerminal(t *testing.T) {
+	themes, err := WindowsTerminal.Parse("settings.json", []byte(windowsTerminalSettings))
+	if err == nil || !strings.Contains(err.Error(), "Broken") {
+		t.Errorf("Expected the Broken scheme to be reported, got %v", err)
+	}
+	if len(themes) != 1 {
+		t.Fatalf("Expected 1 theme, got %d", len(themes))
+	}
+	th := themes[0]
+	if th.Name != "Harbor Night" || th.Colors.Magenta != "#b16286" || th.Colors.BrightMagenta != "#d3869b" || th.Colors.Cursor != "#fe8019" {
+		t.Errorf("Unexpected theme: %+v", th)
+	}
+
+	// A single scheme, as shared on windowsterminalthemes.dev
+	single := windowsTerminalSettings[strings.Index(windowsTerminalSettings, "\t\t{")+2 : strings.Index(windowsTerminalSettings, "\t\t{\"name\": \"Broken\"")]
+	themes, err = WindowsTerminal.Parse("harbor.json", []byte(strings.TrimSuffix(strings.TrimSpace(single), ",")))
+	if err != nil || len(themes) != 1 {
+		t.Errorf("Expected a single scheme to import, got %d (%v)", len(themes), err)
+	}
+}
+
+// TestParseGogh verifies the color_01–color_16 mapping and the variant
+func TestParseGogh(t *testing.T) {
+	themes, err := Gogh.Parse("harbor-day.yml", []byte(goghTheme))
+	if err != nil || len(themes) != 1 {
+		t.Fatalf("Expected 1 theme, got %d (%v)", len(themes), err)
+	}
+	th := themes[0]
+	if th.Name != "Harbor Day" || th.Author != "Acme" || th.Colors.Black != "#fbf1c7" || th.Colors.BrightWhite != "#3c3836" || th.Colors.Cursor != "#3c3836" {
+		t.Errorf("Unexpected theme: %+v", th)
+	}
+	if th.Appearance != theme.AppearanceLight {
+		t.Errorf("Expected a light theme, got %s", th.Appearance)
+	}
+}
+
+// TestImportDetected verifies that a directory of mixed formats imports
+// every recognized file and that an unrecognized file given directly fails
+func TestImportDetected(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"kitty/harbor.conf":     kittyTheme,
+		"x/.Xresources":         xresourcesTheme,
+		"gogh/harbor-day.yml":   goghTheme,
+		"wt/settings.json":      strings.Replace(windowsTerminalSettings, `{"name": "Broken", "purple": "#b16286"},`, "", 1),
+		"README.md":             "# Themes",
+		".git/config":           "[core]\n",
+		"alacritty/dracula.yml": "colors:\n  primary:\n    background: '#282a36'\n",
+	})
+
+	themes, err := ImportDetected(dir)
+	if err == nil || !strings.Contains(err.Error(), "dracula.yml") {
+		t.Errorf("Expected the incomplete Alacritty theme to be reported, got %v", err)
+	}
+	if len(themes) != 4 {
+		t.Errorf("Expected 4 themes, got %d", len(themes))
+	}
+
+	if _, err := ImportDetected(filepath.Join(dir, "README.md")); err == nil {
+		t.Error("Expected an unrecognized file to be reported")
+	}
+	themes, err = Import(Kitty, filepath.Join(dir, "kitty"))
+	if err != nil || len(themes) != 1 {
+		t.Errorf("Expected the kitty theme, got %d (%v)", len(themes), err)
+	}
+}
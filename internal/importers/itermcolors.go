@@ -0,0 +1,279 @@
+package importers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// ITerm2 imports .itermcolors presets and the Custom Color Presets of
+// iTerm2's preferences (.plist)
+var ITerm2 theme.Importer = iTermImporter{}
+
+type iTermImporter struct{}
+
+func (iTermImporter) Name() string { return "iterm2" }
+
+func (iTermImporter) Detect(path string, data []byte) bool {
+	if hasExt(path, ".itermcolors") {
+		return true
+	}
+	return hasExt(path, ".plist") && bytes.Contains(data, []byte("Custom Color Presets"))
+}
+
+func (iTermImporter) Parse(path string, data []byte) ([]theme.Theme, error) {
+	if hasExt(path, ".plist") {
+		return parseITermPreferences(data)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	t, err := ParseITermColors(strings.ReplaceAll(name, "_", " "), data)
+	if err != nil {
+		return nil, err
+	}
+	return []theme.Theme{t}, nil
+}
+
+// iTermPaletteKeys returns the palette slot each iTerm2 color preset key fills
+func iTermPaletteKeys(p *theme.ColorPalette) map[string]*string {
+	return map[string]*string{
+		"Ansi 0 Color":        &p.Black,
+		"Ansi 1 Color":        &p.Red,
+		"Ansi 2 Color":        &p.Green,
+		"Ansi 3 Color":        &p.Yellow,
+		"Ansi 4 Color":        &p.Blue,
+		"Ansi 5 Color":        &p.Magenta,
+		"Ansi 6 Color":        &p.Cyan,
+		"Ansi 7 Color":        &p.White,
+		"Ansi 8 Color":        &p.BrightBlack,
+		"Ansi 9 Color":        &p.BrightRed,
+		"Ansi 10 Color":       &p.BrightGreen,
+		"Ansi 11 Color":       &p.BrightYellow,
+		"Ansi 12 Color":       &p.BrightBlue,
+		"Ansi 13 Color":       &p.BrightMagenta,
+		"Ansi 14 Color":       &p.BrightCyan,
+		"Ansi 15 Color":       &p.BrightWhite,
+		"Background Color":    &p.Background,
+		"Foreground Color":    &p.Foreground,
+		"Cursor Color":        &p.Cursor,
+		"Cursor Text Color":   &p.CursorText,
+		"Selection Color":     &p.SelectionBackground,
+		"Selected Text Color": &p.SelectionForeground,
+	}
+}
+
+// ITermPreferencesPath returns where iTerm2 keeps its preferences, including
+// the Custom Color Presets, for the given home directory
+func ITermPreferencesPath(home string) string {
+	return filepath.Join(home, "Library", "Preferences", "com.googlecode.iterm2.plist")
+}
+
+// ParseITermColors converts an .itermcolors preset into a theme with the given name
+func ParseITermColors(name string, data []byte) (theme.Theme, error) {
+	root, err := decodePlist(data)
+	if err != nil {
+		return theme.Theme{}, err
+	}
+	preset, ok := root.(map[string]any)
+	if !ok {
+		return theme.Theme{}, fmt.Errorf("not an iTerm2 color preset")
+	}
+	return iTermPresetTheme(name, preset)
+}
+
+// ImportITermColors reads iTerm2 color presets from an .itermcolors file, from
+// iTerm2's preferences (com.googlecode.iterm2.plist or any other .plist file,
+// using its Custom Color Presets) or, recursively, from the .itermcolors files
+// in a directory. Presets that fail to parse are reported in the error; the
+// others are still returned.
+func ImportITermColors(path string) ([]theme.Theme, error) {
+	if strings.EqualFold(filepath.Ext(path), ".plist") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		themes, err := parseITermPreferences(data)
+		if err != nil {
+			return themes, fmt.Errorf("%s: %w", path, err)
+		}
+		return themes, nil
+	}
+
+	var themes []theme.Theme
+	var errs []error
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (p != path && !strings.EqualFold(filepath.Ext(p), ".itermcolors")) {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		t, err := ParseITermColors(strings.ReplaceAll(name, "_", " "), data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p, err))
+			return nil
+		}
+		themes = append(themes, t)
+		return nil
+	})
+	if err != nil {
+		return themes, err
+	}
+	return themes, errors.Join(errs...)
+}
+
+// parseITermPreferences converts the Custom Color Presets of iTerm2's
+// preferences, sorted by name
+func parseITermPreferences(data []byte) ([]theme.Theme, error) {
+	root, err := decodePlist(data)
+	if err != nil {
+		return nil, err
+	}
+	prefs, _ := root.(map[string]any)
+	presets, ok := prefs["Custom Color Presets"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("no Custom Color Presets found")
+	}
+
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var themes []theme.Theme
+	var errs []error
+	for _, name := range names {
+		preset, ok := presets[name].(map[string]any)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: not a color preset", name))
+			continue
+		}
+		t, err := iTermPresetTheme(name, preset)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		themes = append(themes, t)
+	}
+	return themes, errors.Join(errs...)
+}
+
+// iTermPresetTheme converts a decoded color preset
+func iTermPresetTheme(name string, preset map[string]any) (theme.Theme, error) {
+	t := theme.Theme{Name: name, Tags: []string{"iterm2"}}
+	for key, slot := range iTermPaletteKeys(&t.Colors) {
+		value, ok := preset[key]
+		if !ok {
+			// Presets with separate light and dark mode colors may only
+			// have the suffixed keys
+			value, ok = preset[key+" (Dark)"]
+		}
+		if !ok {
+			continue
+		}
+		color, err := iTermColor(value)
+		if err != nil {
+			return theme.Theme{}, fmt.Errorf("%s: %s: %w", name, key, err)
+		}
+		*slot = color
+	}
+
+	if err := t.Normalize(); err != nil {
+		return theme.Theme{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return t, nil
+}
+
+// iTermColor converts an iTerm2 color dictionary to an sRGB hex color.
+// Presets without a Color Space predate iTerm2 3.3 and are calibrated RGB.
+func iTermColor(value any) (string, error) {
+	dict, ok := value.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("not a color dictionary")
+	}
+
+	var rgb [3]float64
+	for i, key := range []string{"Red Component", "Green Component", "Blue Component"} {
+		switch v := dict[key].(type) {
+		case float64:
+			rgb[i] = v
+		case int64:
+			rgb[i] = float64(v)
+		default:
+			return "", fmt.Errorf("missing %s", key)
+		}
+	}
+
+	space, _ := dict["Color Space"].(string)
+	switch strings.ToLower(space) {
+	case "srgb", "device":
+	case "p3":
+		rgb = displayP3ToSRGB(rgb)
+	case "", "calibrated":
+		rgb = genericRGBToSRGB(rgb)
+	default:
+		return "", fmt.Errorf("unsupported color space %q", space)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", channel(rgb[0]), channel(rgb[1]), channel(rgb[2])), nil
+}
+
+// displayP3ToSRGB converts Display P3 components to sRGB. Both spaces share
+// the sRGB transfer function and D65 white; colors outside sRGB are clipped.
+func displayP3ToSRGB(rgb [3]float64) [3]float64 {
+	r, g, b := srgbDecode(rgb[0]), srgbDecode(rgb[1]), srgbDecode(rgb[2])
+	return [3]float64{
+		srgbEncode(1.2249401*r - 0.2249404*g),
+		srgbEncode(-0.0420569*r + 1.0420571*g),
+		srgbEncode(-0.0196376*r - 0.0786361*g + 1.0982735*b),
+	}
+}
+
+// genericRGBToSRGB converts calibrated (Generic RGB) components to sRGB.
+// Generic RGB's primaries are close to sRGB's, so only its 1.8 gamma is converted.
+func genericRGBToSRGB(rgb [3]float64) [3]float64 {
+	for i, v := range rgb {
+		rgb[i] = srgbEncode(math.Pow(clamp01(v), 1.8))
+	}
+	return rgb
+}
+
+// srgbDecode converts an sRGB component to linear light
+func srgbDecode(v float64) float64 {
+	v = clamp01(v)
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// srgbEncode converts linear light to an sRGB component
+func srgbEncode(v float64) float64 {
+	v = clamp01(v)
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// channel converts a component in [0, 1] to 0–255
+func channel(v float64) uint8 {
+	return uint8(math.Round(clamp01(v) * 255))
+}
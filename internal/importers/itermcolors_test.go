@@ -0,0 +1,202 @@
+package importers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// iTermPresetXML writes an .itermcolors preset with every color in the given
+// color space, or without a Color Space key when space is empty
+func iTermPresetXML(colors map[string]string, space string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	for key, hex := range colors {
+		c, _ := theme.ParseColor(hex)
+		fmt.Fprintf(&b, "\t<key>%s</key>\n\t<dict>\n", key)
+		fmt.Fprintf(&b, "\t\t<key>Alpha Component</key>\n\t\t<real>1</real>\n")
+		fmt.Fprintf(&b, "\t\t<key>Blue Component</key>\n\t\t<real>%f</real>\n", float64(c.B)/255)
+		if space != "" {
+			fmt.Fprintf(&b, "\t\t<key>Color Space</key>\n\t\t<string>%s</string>\n", space)
+		}
+		fmt.Fprintf(&b, "\t\t<key>Green Component</key>\n\t\t<real>%f</real>\n", float64(c.G)/255)
+		fmt.Fprintf(&b, "\t\t<key>Red Component</key>\n\t\t<integer>%d</integer>\n", c.R/255)
+		b.WriteString("\t</dict>\n")
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// testITermColors returns a complete preset whose red components are 0 or 1,
+// since the XML above writes them as integers
+func testITermColors() map[string]string {
+	colors := map[string]string{
+		"Background Color":    "#002b36",
+		"Foreground Color":    "#ffffff",
+		"Selection Color":     "#0073ff",
+		"Selected Text Color": "#ff2b36",
+		"Badge Color":         "#ff0000",
+	}
+	for i := 0; i < 16; i++ {
+		colors[fmt.Sprintf("Ansi %d Color", i)] = fmt.Sprintf("#%02x%02x%02x", (i%2)*255, i*16, 255-i*16)
+	}
+	return colors
+}
+
+// TestParseITermColors verifies that an sRGB preset maps onto the palette unchanged
+func TestParseITermColors(t *testing.T) {
+	th, err := ParseITermColors("Deep Sea", []byte(iTermPresetXML(testITermColors(), "sRGB")))
+	if err != nil {
+		t.Fatalf("ParseITermColors failed: %v", err)
+	}
+
+	checks := map[string][2]string{
+		"background":           {th.Colors.Background, "#002b36"},
+		"foreground":           {th.Colors.Foreground, "#ffffff"},
+		"black":                {th.Colors.Black, "#0000ff"},
+		"red":                  {th.Colors.Red, "#ff10ef"},
+		"bright_white":         {th.Colors.BrightWhite, "#fff00f"},
+		"selection_background": {th.Colors.SelectionBackground, "#0073ff"},
+		"selection_foreground": {th.Colors.SelectionForeground, "#ff2b36"},
+		"cursor":               {th.Colors.Cursor, ""},
+	}
+	for slot, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %q, got %q", slot, c[1], c[0])
+		}
+	}
+	if th.Name != "Deep Sea" {
+		t.Errorf("Expected name Deep Sea, got %q", th.Name)
+	}
+}
+
+// TestITermColorSpaces verifies the conversion of each color space to sRGB
+func TestITermColorSpaces(t *testing.T) {
+	color := func(space string, r, g, b float64) map[string]any {
+		dict := map[string]any{"Red Component": r, "Green Component": g, "Blue Component": b}
+		if space != "" {
+			dict["Color Space"] = space
+		}
+		return dict
+	}
+
+	tests := []struct {
+		name  string
+		value map[string]any
+		want  string
+	}{
+		{"sRGB", color("sRGB", 0.5, 0.25, 0.75), "#8040bf"},
+		{"Display P3", color("P3", 0.5, 0.25, 0.75), "#893bc6"},
+		{"Display P3 red is clipped", color("P3", 1, 0, 0), "#ff0000"},
+		{"calibrated", color("Calibrated", 0.5, 0.5, 0.5), "#929292"},
+		{"no color space", color("", 0.5, 0.5, 0.5), "#929292"},
+	}
+	for _, tt := range tests {
+		got, err := iTermColor(tt.value)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	if _, err := iTermColor(color("CMYK", 0, 0, 0)); err == nil {
+		t.Error("Expected an error for an unknown color space")
+	}
+	if _, err := iTermColor(map[string]any{"Red Component": 1.0}); err == nil {
+		t.Error("Expected an error for missing components")
+	}
+}
+
+// TestImportITermPreferences verifies that the Custom Color Presets of a
+// binary com.googlecode.iterm2.plist are imported
+func TestImportITermPreferences(t *testing.T) {
+	themes, err := ImportITermColors(filepath.Join("testdata", "com.googlecode.iterm2.plist"))
+	if err == nil || !strings.Contains(err.Error(), "Broken") {
+		t.Errorf("Expected an error for the incomplete Broken preset, got %v", err)
+	}
+	if len(themes) != 1 {
+		t.Fatalf("Expected 1 theme, got %d", len(themes))
+	}
+
+	th := themes[0]
+	checks := map[string][2]string{
+		"name":                 {th.Name, "Harbor Night"},
+		"background":           {th.Colors.Background, "#282828"}, // Only stored as "Background Color (Dark)"
+		"bright_blue":          {th.Colors.BrightBlue, "#83a598"},
+		"cursor":               {th.Colors.Cursor, "#fe8019"},
+		"cursor_text":          {th.Colors.CursorText, "#282828"},
+		"selection_background": {th.Colors.SelectionBackground, "#504945"},
+		"selection_foreground": {th.Colors.SelectionForeground, "#fbf1c7"},
+	}
+	for slot, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %q, got %q", slot, c[1], c[0])
+		}
+	}
+}
+
+// TestDecodeBinaryPlist verifies the binary plist object types and that
+// truncated files fail instead of panicking
+func TestDecodeBinaryPlist(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "com.googlecode.iterm2.plist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := decodePlist(data)
+	if err != nil {
+		t.Fatalf("decodePlist failed: %v", err)
+	}
+
+	prefs := root.(map[string]any)
+	if got := prefs["Default Bookmark Guid"]; got != "Zakaranda ✓ profil" {
+		t.Errorf("Expected the UTF-16 string to decode, got %q", got)
+	}
+	if got, ok := prefs["NoSyncData"].([]byte); !ok || len(got) != 3 {
+		t.Errorf("Expected 3 bytes of data, got %v", prefs["NoSyncData"])
+	}
+	profile := prefs["New Bookmarks"].([]any)[0].(map[string]any)
+	if profile["Rows"] != int64(300000) || profile["Use Bold Font"] != true || profile["Transparency"] != 0.25 {
+		t.Errorf("Unexpected profile values: %v", profile)
+	}
+
+	for _, n := range []int{0, 8, 40, len(data) / 2, len(data) - 1} {
+		if _, err := decodePlist(data[:n]); err == nil {
+			t.Errorf("Expected an error for a plist truncated to %d bytes", n)
+		}
+	}
+}
+
+// TestImportITermColorsDirectory verifies that .itermcolors files are found
+// in a directory and named after the file
+func TestImportITermColorsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Deep_Sea.itermcolors":   iTermPresetXML(testITermColors(), "sRGB"),
+		"old/Legacy.itermcolors": iTermPresetXML(testITermColors(), ""),
+		"notes.txt":              "not a preset",
+	}
+	writeFiles(t, dir, files)
+
+	themes, err := ImportITermColors(dir)
+	if err != nil {
+		t.Fatalf("ImportITermColors failed: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, th := range themes {
+		names[th.Name] = true
+	}
+	if len(themes) != 2 || !names["Deep Sea"] || !names["Legacy"] {
+		t.Errorf("Expected Deep Sea and Legacy, got %v", names)
+	}
+}
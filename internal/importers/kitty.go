@@ -0,0 +1,136 @@
+package importers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// Kitty imports kitty color files (kitty.conf, or a theme from kitty-themes)
+var Kitty theme.Importer = kittyImporter{}
+
+type kittyImporter struct{}
+
+func (kittyImporter) Name() string { return "kitty" }
+
+func (kittyImporter) Detect(path string, data []byte) bool {
+	if hasExt(path, ".conf") && bytes.Contains(data, []byte("color0")) {
+		return true
+	}
+	// Themes without the .conf extension: "color0 #000000" lines
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && kittyColorKey(fields[0]) && strings.HasPrefix(fields[1], "#") {
+			return true
+		}
+	}
+	return false
+}
+
+func (kittyImporter) Parse(path string, data []byte) ([]theme.Theme, error) {
+	t, err := ParseKittyColors(FileThemeName(path), data)
+	if err != nil {
+		return nil, err
+	}
+	return []theme.Theme{t}, nil
+}
+
+// kittyColorKey reports whether key is one of color0–color15
+func kittyColorKey(key string) bool {
+	n, err := strconv.Atoi(strings.TrimPrefix(key, "color"))
+	return strings.HasPrefix(key, "color") && err == nil && n >= 0 && n < 16
+}
+
+// kittyPaletteKeys returns the palette slot each kitty color option fills
+func kittyPaletteKeys(p *theme.ColorPalette) map[string]*string {
+	return map[string]*string{
+		"background":           &p.Background,
+		"foreground":           &p.Foreground,
+		"color0":               &p.Black,
+		"color1":               &p.Red,
+		"color2":               &p.Green,
+		"color3":               &p.Yellow,
+		"color4":               &p.Blue,
+		"color5":               &p.Magenta,
+		"color6":               &p.Cyan,
+		"color7":               &p.White,
+		"color8":               &p.BrightBlack,
+		"color9":               &p.BrightRed,
+		"color10":              &p.BrightGreen,
+		"color11":              &p.BrightYellow,
+		"color12":              &p.BrightBlue,
+		"color13":              &p.BrightMagenta,
+		"color14":              &p.BrightCyan,
+		"color15":              &p.BrightWhite,
+		"cursor":               &p.Cursor,
+		"cursor_text_color":    &p.CursorText,
+		"selection_background": &p.SelectionBackground,
+		"selection_foreground": &p.SelectionForeground,
+	}
+}
+
+// kittyDefaults are kitty's colors for the options a config doesn't set
+var kittyDefaults = theme.ColorPalette{
+	Background: "#000000", Foreground: "#dddddd",
+	Black: "#000000", Red: "#cc0403", Green: "#19cb00", Yellow: "#cecb00",
+	Blue: "#0d73cc", Magenta: "#cb1ed1", Cyan: "#0dcdcd", White: "#dddddd",
+	BrightBlack: "#767676", BrightRed: "#f2201f", BrightGreen: "#23fd00", BrightYellow: "#fffd00",
+	BrightBlue: "#1a8fff", BrightMagenta: "#fd28ff", BrightCyan: "#14ffff", BrightWhite: "#ffffff",
+}
+
+// ParseKittyColors converts the color options of a kitty config into a theme
+// with the given name, using kitty's defaults for the colors it doesn't set.
+// The "## name:" and "## author:" comments of kitty-themes files take
+// precedence. Options set to "none" or "background" (kitty's ways of
+// following the cell colors) are left unset.
+func ParseKittyColors(name string, data []byte) (theme.Theme, error) {
+	t := theme.Theme{Name: name, Tags: []string{"kitty"}, Colors: kittyDefaults}
+	slots := kittyPaletteKeys(&t.Colors)
+	found := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if meta, ok := strings.CutPrefix(line, "## "); ok {
+			key, value, _ := strings.Cut(meta, ":")
+			switch strings.TrimSpace(strings.ToLower(key)) {
+			case "name":
+				t.Name = strings.TrimSpace(value)
+			case "author":
+				t.Author = strings.TrimSpace(value)
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		slot, ok := slots[fields[0]]
+		if !ok || len(fields) < 2 {
+			continue
+		}
+		value := fields[1]
+		switch strings.ToLower(value) {
+		case "none", "background", "foreground":
+			value = ""
+		}
+		*slot, found = value, true
+	}
+	if err := scanner.Err(); err != nil {
+		return theme.Theme{}, err
+	}
+	if !found {
+		return theme.Theme{}, fmt.Errorf("no color options found")
+	}
+
+	if err := t.Normalize(); err != nil {
+		return theme.Theme{}, err
+	}
+	return t, nil
+}
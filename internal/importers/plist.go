@@ -0,0 +1,308 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxPlistDepth bounds nesting, so reference cycles in binary plists fail
+// instead of recursing forever
+const maxPlistDepth = 64
+
+// decodePlist decodes an XML or binary property list. Dictionaries become
+// map[string]any, arrays []any, integers int64, reals float64 and data
+// []byte. Dates aren't needed by the importers and are left undecoded.
+func decodePlist(data []byte) (any, error) {
+	if bytes.HasPrefix(data, []byte("bplist00")) {
+		return decodeBinaryPlist(data)
+	}
+	return decodeXMLPlist(data)
+}
+
+// decodeXMLPlist decodes the XML property list format
+func decodeXMLPlist(data []byte) (any, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid plist: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local != "plist" {
+			return xmlPlistValue(d, start, 0)
+		}
+	}
+}
+
+// xmlPlistValue decodes the element opened by start
+func xmlPlistValue(d *xml.Decoder, start xml.StartElement, depth int) (any, error) {
+	if depth > maxPlistDepth {
+		return nil, fmt.Errorf("plist nested too deeply")
+	}
+
+	switch start.Name.Local {
+	case "dict":
+		dict := make(map[string]any)
+		key, haveKey := "", false
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				if tok.Name.Local == "key" {
+					if err := d.DecodeElement(&key, &tok); err != nil {
+						return nil, err
+					}
+					haveKey = true
+					continue
+				}
+				if !haveKey {
+					return nil, fmt.Errorf("plist dict value <%s> has no key", tok.Name.Local)
+				}
+				value, err := xmlPlistValue(d, tok, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				dict[key], haveKey = value, false
+			case xml.EndElement:
+				return dict, nil
+			}
+		}
+	case "array":
+		var array []any
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch tok := tok.(type) {
+			case xml.StartElement:
+				value, err := xmlPlistValue(d, tok, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, value)
+			case xml.EndElement:
+				return array, nil
+			}
+		}
+	case "true", "false":
+		return start.Name.Local == "true", d.Skip()
+	}
+
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	switch start.Name.Local {
+	case "string", "date":
+		return text, nil
+	case "real":
+		return strconv.ParseFloat(text, 64)
+	case "integer":
+		return strconv.ParseInt(text, 10, 64)
+	case "data":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	}
+	return nil, fmt.Errorf("unsupported plist element <%s>", start.Name.Local)
+}
+
+// binaryPlist is a binary property list ("bplist00") being decoded
+type binaryPlist struct {
+	data    []byte
+	offsets []uint64 // Object offsets, by object reference
+	refSize int
+}
+
+// decodeBinaryPlist decodes the binary property list format
+func decodeBinaryPlist(data []byte) (any, error) {
+	if len(data) < 8+32 {
+		return nil, fmt.Errorf("invalid binary plist: too short")
+	}
+	trailer := data[len(data)-32:]
+	offsetSize, refSize := int(trailer[6]), int(trailer[7])
+	count := binary.BigEndian.Uint64(trailer[8:])
+	top := binary.BigEndian.Uint64(trailer[16:])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:])
+
+	body := uint64(len(data) - 32)
+	if offsetSize < 1 || offsetSize > 8 || refSize < 1 || refSize > 8 ||
+		tableOffset > body || count > (body-tableOffset)/uint64(offsetSize) || top >= count {
+		return nil, fmt.Errorf("invalid binary plist trailer")
+	}
+
+	p := &binaryPlist{data: data[:body], offsets: make([]uint64, count), refSize: refSize}
+	for i := range p.offsets {
+		start := tableOffset + uint64(i*offsetSize)
+		p.offsets[i] = readUint(data[start : start+uint64(offsetSize)])
+	}
+	return p.object(top, 0)
+}
+
+// object decodes the object with the given reference
+func (p *binaryPlist) object(ref uint64, depth int) (any, error) {
+	if depth > maxPlistDepth {
+		return nil, fmt.Errorf("plist nested too deeply")
+	}
+	if ref >= uint64(len(p.offsets)) || p.offsets[ref] >= uint64(len(p.data)) {
+		return nil, fmt.Errorf("invalid binary plist object reference %d", ref)
+	}
+
+	off := p.offsets[ref]
+	marker := p.data[off]
+	kind, info := marker>>4, marker&0x0f
+	switch kind {
+	case 0x0:
+		switch info {
+		case 0x8:
+			return false, nil
+		case 0x9:
+			return true, nil
+		}
+		return nil, nil
+	case 0x1:
+		b, err := p.bytes(off+1, 1<<info)
+		if err != nil || len(b) > 8 {
+			return nil, fmt.Errorf("invalid binary plist integer")
+		}
+		return int64(readUint(b)), nil
+	case 0x2, 0x3:
+		b, err := p.bytes(off+1, 1<<info)
+		if err != nil {
+			return nil, err
+		}
+		switch len(b) {
+		case 4:
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+		case 8:
+			return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+		}
+		return nil, fmt.Errorf("invalid binary plist real")
+	case 0x4, 0x5, 0x6:
+		n, start, err := p.length(info, off+1)
+		if err != nil {
+			return nil, err
+		}
+		if kind == 0x6 {
+			n *= 2 // UTF-16 code units
+		}
+		b, err := p.bytes(start, n)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case 0x4:
+			return b, nil
+		case 0x5:
+			return string(b), nil
+		}
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(units)), nil
+	case 0x8:
+		b, err := p.bytes(off+1, uint64(info)+1)
+		if err != nil {
+			return nil, err
+		}
+		return readUint(b), nil
+	case 0xA, 0xC, 0xD:
+		n, start, err := p.length(info, off+1)
+		if err != nil {
+			return nil, err
+		}
+		refCount := n
+		if kind == 0xD {
+			refCount *= 2 // Key references, then value references
+		}
+		refs, err := p.bytes(start, refCount*uint64(p.refSize))
+		if err != nil {
+			return nil, err
+		}
+		ref := func(i uint64) uint64 {
+			return readUint(refs[i*uint64(p.refSize) : (i+1)*uint64(p.refSize)])
+		}
+
+		if kind != 0xD {
+			array := make([]any, 0, n)
+			for i := uint64(0); i < n; i++ {
+				value, err := p.object(ref(i), depth+1)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, value)
+			}
+			return array, nil
+		}
+
+		dict := make(map[string]any, n)
+		for i := uint64(0); i < n; i++ {
+			key, err := p.object(ref(i), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("binary plist dict key is not a string")
+			}
+			if dict[name], err = p.object(ref(n+i), depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	}
+	return nil, fmt.Errorf("unsupported binary plist object type 0x%x", kind)
+}
+
+// length returns the element count in info, or in the integer object that
+// follows the marker when info is 0xF, and where the elements start. Counts
+// larger than the plist are rejected, so callers can multiply them safely.
+func (p *binaryPlist) length(info byte, pos uint64) (uint64, uint64, error) {
+	if info != 0x0f {
+		return uint64(info), pos, nil
+	}
+	b, err := p.bytes(pos, 1)
+	if err != nil || b[0]>>4 != 0x1 {
+		return 0, 0, fmt.Errorf("invalid binary plist length")
+	}
+	size := uint64(1) << (b[0] & 0x0f)
+	if size > 8 {
+		return 0, 0, fmt.Errorf("invalid binary plist length")
+	}
+	b, err = p.bytes(pos+1, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	n := readUint(b)
+	if n > uint64(len(p.data)) {
+		return 0, 0, fmt.Errorf("binary plist object out of bounds")
+	}
+	return n, pos + 1 + size, nil
+}
+
+// bytes returns n bytes starting at pos, checking they're within the plist
+func (p *binaryPlist) bytes(pos, n uint64) ([]byte, error) {
+	if pos > uint64(len(p.data)) || n > uint64(len(p.data))-pos {
+		return nil, fmt.Errorf("binary plist object out of bounds")
+	}
+	return p.data[pos : pos+n], nil
+}
+
+// readUint reads a big-endian unsigned integer of up to 8 bytes
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
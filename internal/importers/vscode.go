@@ -0,0 +1,414 @@
+package importers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/integrations"
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// maxVSCodeIncludeDepth bounds the chain of theme files including each other
+const maxVSCodeIncludeDepth = 8
+
+// vscodeManifest is the part of an extension's package.json describing it
+// and the color themes it contributes
+type vscodeManifest struct {
+	Name        string `json:"name"`
+	Publisher   string `json:"publisher"`
+	DisplayName string `json:"displayName"`
+	Version     string `json:"version"`
+	Contributes struct {
+		Themes []struct {
+			ID      string `json:"id"`
+			Label   string `json:"label"`
+			UITheme string `json:"uiTheme"` // vs, vs-dark, hc-black or hc-light
+			Path    string `json:"path"`
+		} `json:"themes"`
+	} `json:"contributes"`
+}
+
+// vscodeExtension is an installed extension that contributes color themes
+type vscodeExtension struct {
+	dir      string
+	manifest vscodeManifest
+}
+
+// id returns the extension ID used to install it ("publisher.name")
+func (e vscodeExtension) id() string {
+	return e.manifest.Publisher + "." + e.manifest.Name
+}
+
+// VS Code's colors for the terminal and editor when a theme doesn't set them
+var (
+	vscodeDarkDefaults = map[string]string{
+		"editor.background": "#1e1e1e", "editor.foreground": "#d4d4d4",
+		"terminal.ansiBlack": "#000000", "terminal.ansiRed": "#cd3131", "terminal.ansiGreen": "#0dbc79", "terminal.ansiYellow": "#e5e510",
+		"terminal.ansiBlue": "#2472c8", "terminal.ansiMagenta": "#bc3fbc", "terminal.ansiCyan": "#11a8cd", "terminal.ansiWhite": "#e5e5e5",
+		"terminal.ansiBrightBlack": "#666666", "terminal.ansiBrightRed": "#f14c4c", "terminal.ansiBrightGreen": "#23d18b", "terminal.ansiBrightYellow": "#f5f543",
+		"terminal.ansiBrightBlue": "#3b8eea", "terminal.ansiBrightMagenta": "#d670d6", "terminal.ansiBrightCyan": "#29b8db", "terminal.ansiBrightWhite": "#e5e5e5",
+	}
+	vscodeLightDefaults = map[string]string{
+		"editor.background": "#ffffff", "editor.foreground": "#000000",
+		"terminal.ansiBlack": "#000000", "terminal.ansiRed": "#cd3131", "terminal.ansiGreen": "#00bc00", "terminal.ansiYellow": "#949800",
+		"terminal.ansiBlue": "#0451a5", "terminal.ansiMagenta": "#bc05bc", "terminal.ansiCyan": "#0598bc", "terminal.ansiWhite": "#555555",
+		"terminal.ansiBrightBlack": "#666666", "terminal.ansiBrightRed": "#cd3131", "terminal.ansiBrightGreen": "#14ce14", "terminal.ansiBrightYellow": "#b5ba00",
+		"terminal.ansiBrightBlue": "#0451a5", "terminal.ansiBrightMagenta": "#bc05bc", "terminal.ansiBrightCyan": "#0598bc", "terminal.ansiBrightWhite": "#a5a5a5",
+	}
+)
+
+// ImportVSCodeExtensions converts the color themes contributed by the VS Code
+// extensions in an extensions directory (e.g. ~/.vscode/extensions), or by a
+// single extension directory, into themes. Each theme records the extension
+// it came from, so applying it to VS Code uses the extension itself. Only the
+// newest installed version of an extension is read. Themes that fail to parse
+// are reported in the error; the others are still returned.
+func ImportVSCodeExtensions(path string) ([]theme.Theme, error) {
+	var extensions []vscodeExtension
+	if manifest, err := readVSCodeManifest(path); err == nil {
+		extensions = append(extensions, vscodeExtension{dir: path, manifest: manifest})
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	} else {
+		if extensions, err = findVSCodeExtensions(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var themes []theme.Theme
+	var errs []error
+	for _, ext := range extensions {
+		extThemes, err := ext.themes()
+		themes = append(themes, extThemes...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return themes, errors.Join(errs...)
+}
+
+// findVSCodeExtensions returns the newest version of each theme extension in
+// an extensions directory, sorted by ID
+func findVSCodeExtensions(dir string) ([]vscodeExtension, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	newest := make(map[string]vscodeExtension)
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		extDir := filepath.Join(dir, entry.Name())
+		manifest, err := readVSCodeManifest(extDir)
+		if err != nil || len(manifest.Contributes.Themes) == 0 {
+			continue // Not an extension, or not a theme extension
+		}
+		ext := vscodeExtension{dir: extDir, manifest: manifest}
+		id := strings.ToLower(ext.id())
+		if current, ok := newest[id]; !ok || compareVersions(manifest.Version, current.manifest.Version) > 0 {
+			newest[id] = ext
+		}
+	}
+
+	extensions := make([]vscodeExtension, 0, len(newest))
+	for _, ext := range newest {
+		extensions = append(extensions, ext)
+	}
+	sort.Slice(extensions, func(i, j int) bool {
+		return strings.ToLower(extensions[i].id()) < strings.ToLower(extensions[j].id())
+	})
+	return extensions, nil
+}
+
+// readVSCodeManifest reads an extension's package.json, resolving the
+// localized %key% placeholders from package.nls.json
+func readVSCodeManifest(dir string) (vscodeManifest, error) {
+	var manifest vscodeManifest
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return manifest, err
+	}
+	if err := unmarshalJSONC(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("%s: %w", filepath.Join(dir, "package.json"), err)
+	}
+
+	var nls map[string]any
+	if data, err := os.ReadFile(filepath.Join(dir, "package.nls.json")); err == nil {
+		_ = unmarshalJSONC(data, &nls) // Placeholders stay as they are without it
+	}
+	localize := func(s string) string {
+		if len(s) > 2 && strings.HasPrefix(s, "%") && strings.HasSuffix(s, "%") {
+			switch v := nls[s[1:len(s)-1]].(type) {
+			case string:
+				return v
+			case map[string]any: // {"message": ..., "comment": [...]}
+				if msg, ok := v["message"].(string); ok {
+					return msg
+				}
+			}
+		}
+		return s
+	}
+	manifest.DisplayName = localize(manifest.DisplayName)
+	for i := range manifest.Contributes.Themes {
+		manifest.Contributes.Themes[i].Label = localize(manifest.Contributes.Themes[i].Label)
+	}
+	return manifest, nil
+}
+
+// themes converts the color themes the extension contributes
+func (e vscodeExtension) themes() ([]theme.Theme, error) {
+	displayName := e.manifest.DisplayName
+	if displayName == "" {
+		displayName = e.manifest.Name
+	}
+
+	var themes []theme.Theme
+	var errs []error
+	for _, contrib := range e.manifest.Contributes.Themes {
+		// TextMate (.tmTheme) themes only color the editor, not the terminal
+		if !strings.EqualFold(filepath.Ext(contrib.Path), ".json") {
+			continue
+		}
+		file := filepath.Join(e.dir, filepath.FromSlash(contrib.Path))
+		colors, kind, err := readVSCodeThemeFile(file, 0)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+
+		// The setting names a theme by its ID, falling back to its label
+		settingName := contrib.ID
+		if settingName == "" {
+			settingName = contrib.Label
+		}
+		name := contrib.Label
+		if name == "" {
+			name = settingName
+		}
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
+
+		t := vscodeTheme(colors, vscodeAppearance(contrib.UITheme, kind))
+		t.Name = name
+		t.Author = e.manifest.Publisher
+		t.Description = fmt.Sprintf("From the %s VS Code extension", displayName)
+		t.Tags = []string{"vscode"}
+		t.Source = &theme.ThemeSource{App: "vscode", ExtensionID: e.id(), Theme: settingName}
+		if err := t.Normalize(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		themes = append(themes, t)
+	}
+	return themes, errors.Join(errs...)
+}
+
+// readVSCodeThemeFile returns the workbench colors and type ("dark", "light",
+// "hc", ...) of a JSONC color theme, merged over those of the theme it includes
+func readVSCodeThemeFile(path string, depth int) (map[string]string, string, error) {
+	if depth > maxVSCodeIncludeDepth {
+		return nil, "", fmt.Errorf("theme includes nested too deeply")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	var file struct {
+		Include string         `json:"include"`
+		Type    string         `json:"type"`
+		Colors  map[string]any `json:"colors"`
+	}
+	if err := unmarshalJSONC(data, &file); err != nil {
+		return nil, "", err
+	}
+
+	colors := make(map[string]string)
+	kind := file.Type
+	if file.Include != "" {
+		included, includedKind, err := readVSCodeThemeFile(filepath.Join(filepath.Dir(path), filepath.FromSlash(file.Include)), depth+1)
+		if err != nil {
+			return nil, "", err
+		}
+		colors = included
+		if kind == "" {
+			kind = includedKind
+		}
+	}
+	for key, value := range file.Colors {
+		if s, ok := value.(string); ok {
+			colors[key] = s
+		} else if value == nil {
+			delete(colors, key) // null resets a color to the default
+		}
+	}
+	return colors, kind, nil
+}
+
+// vscodeAppearance returns the appearance of a theme from its contributed
+// uiTheme, or the type in its file
+func vscodeAppearance(uiTheme, kind string) theme.Appearance {
+	switch strings.ToLower(uiTheme) {
+	case "vs", "hc-light":
+		return theme.AppearanceLight
+	case "vs-dark", "hc-black":
+		return theme.AppearanceDark
+	}
+	if strings.EqualFold(kind, "light") || strings.EqualFold(kind, "hcLight") {
+		return theme.AppearanceLight
+	}
+	return theme.AppearanceDark
+}
+
+// vscodeTheme maps workbench colors onto a palette. Terminal colors the theme
+// leaves unset are VS Code's defaults, as the terminal would show them, and
+// translucent colors are blended over the background.
+func vscodeTheme(colors map[string]string, appearance theme.Appearance) theme.Theme {
+	defaults := vscodeDarkDefaults
+	if appearance == theme.AppearanceLight {
+		defaults = vscodeLightDefaults
+	}
+
+	background := vscodeColor(colors, defaults["editor.background"], "editor.background", "terminal.background")
+	if background == "" {
+		background = defaults["editor.background"]
+	}
+	// color returns the first valid color of keys over the background, or
+	// the default of the first key
+	color := func(keys ...string) string {
+		if c := vscodeColor(colors, background, keys...); c != "" {
+			return c
+		}
+		return defaults[keys[0]]
+	}
+
+	return theme.Theme{
+		Appearance: appearance,
+		Colors: theme.ColorPalette{
+			Background:          background,
+			Foreground:          color("editor.foreground", "terminal.foreground"),
+			Black:               color("terminal.ansiBlack"),
+			Red:                 color("terminal.ansiRed"),
+			Green:               color("terminal.ansiGreen"),
+			Yellow:              color("terminal.ansiYellow"),
+			Blue:                color("terminal.ansiBlue"),
+			Magenta:             color("terminal.ansiMagenta"),
+			Cyan:                color("terminal.ansiCyan"),
+			White:               color("terminal.ansiWhite"),
+			BrightBlack:         color("terminal.ansiBrightBlack"),
+			BrightRed:           color("terminal.ansiBrightRed"),
+			BrightGreen:         color("terminal.ansiBrightGreen"),
+			BrightYellow:        color("terminal.ansiBrightYellow"),
+			BrightBlue:          color("terminal.ansiBrightBlue"),
+			BrightMagenta:       color("terminal.ansiBrightMagenta"),
+			BrightCyan:          color("terminal.ansiBrightCyan"),
+			BrightWhite:         color("terminal.ansiBrightWhite"),
+			Cursor:              color("terminalCursor.foreground", "editorCursor.foreground"),
+			CursorText:          color("terminalCursor.background"),
+			SelectionBackground: color("terminal.selectionBackground", "editor.selectionBackground"),
+			SelectionForeground: color("terminal.selectionForeground", "editor.selectionForeground"),
+		},
+	}
+}
+
+// vscodeColor returns the first of keys holding a valid color, blended over
+// under when translucent, or "" when none does
+func vscodeColor(colors map[string]string, under string, keys ...string) string {
+	for _, key := range keys {
+		c, err := theme.ParseColor(colors[key])
+		if err != nil {
+			continue // VS Code ignores invalid colors too
+		}
+		if c.A != 0xff {
+			base, err := theme.ParseColor(under)
+			if err != nil {
+				continue
+			}
+			blend := func(fg, bg uint8) uint8 {
+				return uint8((int(fg)*int(c.A) + int(bg)*(255-int(c.A)) + 127) / 255)
+			}
+			c = theme.Color{R: blend(c.R, base.R), G: blend(c.G, base.G), B: blend(c.B, base.B), A: 0xff}
+		}
+		return c.Hex()
+	}
+	return ""
+}
+
+// unmarshalJSONC decodes JSON with comments and trailing commas, as VS Code accepts
+func unmarshalJSONC(data []byte, v any) error {
+	return json.Unmarshal([]byte(stripTrailingCommas(integrations.StripJSONComments(string(data)))), v)
+}
+
+// stripTrailingCommas removes commas directly before a closing bracket or
+// brace, outside of strings
+func stripTrailingCommas(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case inString:
+			if escaped {
+				escaped = false
+			} else if ch == '\\' {
+				escaped = true
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == ',':
+			j := i + 1
+			for j < len(s) && strings.IndexByte(" \t\r\n", s[j]) >= 0 {
+				j++
+			}
+			if j < len(s) && (s[j] == ']' || s[j] == '}') {
+				continue
+			}
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// compareVersions compares dotted version numbers, ignoring pre-release and
+// build suffixes, and returns -1, 0 or 1
+func compareVersions(a, b string) int {
+	parts := func(v string) []int {
+		v, _, _ = strings.Cut(v, "-")
+		v, _, _ = strings.Cut(v, "+")
+		var nums []int
+		for _, field := range strings.Split(v, ".") {
+			n, _ := strconv.Atoi(field)
+			nums = append(nums, n)
+		}
+		return nums
+	}
+	pa, pb := parts(a), parts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+package importers
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+const harborPackageJSON = `{
+	"name": "harbor",
+	"publisher": "acme",
+	"displayName": "%displayName%",
+	"version": "1.10.0",
+	"contributes": {
+		"themes": [
+			{"id": "Harbor Night", "label": "%themes.night%", "uiTheme": "vs-dark", "path": "./themes/night.json"},
+			{"label": "Harbor Day", "uiTheme": "vs", "path": "./themes/day.jsonc.json"},
+			{"label": "Harbor Classic", "uiTheme": "vs-dark", "path": "./themes/classic.tmTheme"},
+		],
+	},
+}`
+
+const harborNightJSON = `// Harbor Night
+{
+	"include": "./base.json",
+	"type": "dark",
+	"colors": {
+		"editor.background": "#1d2021",
+		"terminal.ansiRed": "#fb4934", /* overrides the base */
+		"terminal.ansiBrightBlue": "not a color",
+		"terminalCursor.foreground": "#fe8019",
+		"editor.selectionBackground": "#ffffff40",
+		"url": "https://example.com/a//b",
+	},
+}`
+
+const harborBaseJSON = `{
+	"colors": {
+		"editor.foreground": "#ebdbb2",
+		"terminal.ansiRed": "#cc241d",
+		"terminal.ansiGreen": "#98971a"
+	}
+}`
+
+// TestImportVSCodeExtensions verifies the themes of an extensions directory:
+// localized labels, included theme files, translucent colors, VS Code's
+// default terminal colors and the recorded extension
+func TestImportVSCodeExtensions(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"acme.harbor-1.10.0/package.json":          harborPackageJSON,
+		"acme.harbor-1.10.0/package.nls.json":      `{"displayName": "Harbor Themes", "themes.night": {"message": "Harbor Night", "comment": ["theme label"]}}`,
+		"acme.harbor-1.10.0/themes/night.json":     harborNightJSON,
+		"acme.harbor-1.10.0/themes/base.json":      harborBaseJSON,
+		"acme.harbor-1.10.0/themes/day.jsonc.json": `{"colors": {"terminal.background": "#fbf1c7"}}`,
+		"acme.harbor-1.9.2/package.json":           strings.Replace(harborPackageJSON, "1.10.0", "1.9.2", 1),
+		"acme.harbor-1.9.2/themes/night.json":      `{"colors": {"editor.background": "#000000"}}`,
+		"acme.linter-2.0.0/package.json":           `{"name": "linter", "publisher": "acme", "version": "2.0.0"}`,
+		".obsolete":                                "{}",
+	})
+
+	themes, err := ImportVSCodeExtensions(dir)
+	if err != nil {
+		t.Fatalf("ImportVSCodeExtensions failed: %v", err)
+	}
+	if len(themes) != 2 {
+		t.Fatalf("Expected Harbor Night and Harbor Day, got %d themes", len(themes))
+	}
+
+	night := themes[0]
+	checks := map[string][2]string{
+		"name":                 {night.Name, "Harbor Night"},
+		"author":               {night.Author, "acme"},
+		"description":          {night.Description, "From the Harbor Themes VS Code extension"},
+		"background":           {night.Colors.Background, "#1d2021"}, // From the newest version
+		"foreground":           {night.Colors.Foreground, "#ebdbb2"}, // From the included file
+		"red":                  {night.Colors.Red, "#fb4934"},
+		"green":                {night.Colors.Green, "#98971a"},
+		"yellow":               {night.Colors.Yellow, "#e5e510"}, // VS Code's default
+		"bright_blue":          {night.Colors.BrightBlue, "#3b8eea"},
+		"cursor":               {night.Colors.Cursor, "#fe8019"},
+		"selection_background": {night.Colors.SelectionBackground, "#565859"},
+	}
+	for field, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %q, got %q", field, c[1], c[0])
+		}
+	}
+	want := theme.ThemeSource{App: "vscode", ExtensionID: "acme.harbor", Theme: "Harbor Night"}
+	if night.Source == nil || *night.Source != want {
+		t.Errorf("Expected source %+v, got %+v", want, night.Source)
+	}
+
+	day := themes[1]
+	if day.Colors.Background != "#fbf1c7" || day.Colors.Foreground != "#000000" || day.Colors.Yellow != "#949800" {
+		t.Errorf("Expected light defaults around the terminal background, got %+v", day.Colors)
+	}
+	if day.ResolvedAppearance() != theme.AppearanceLight || day.Source.Theme != "Harbor Day" {
+		t.Errorf("Expected a light theme set by its label, got %s and %+v", day.ResolvedAppearance(), day.Source)
+	}
+
+	// A single extension directory
+	themes, err = ImportVSCodeExtensions(filepath.Join(dir, "acme.harbor-1.9.2"))
+	if err == nil || len(themes) != 1 || themes[0].Colors.Background != "#000000" {
+		t.Errorf("Expected the older night theme and the missing day theme reported, got %d themes and %v", len(themes), err)
+	}
+}
+
+// TestImportVSCodeIncludeCycle verifies that theme files including each other fail
+func TestImportVSCodeIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"package.json": `{"name": "loop", "publisher": "acme", "contributes": {"themes": [{"label": "Loop", "path": "a.json"}]}}`,
+		"a.json":       `{"include": "b.json"}`,
+		"b.json":       `{"include": "a.json"}`,
+	})
+	if _, err := ImportVSCodeExtensions(dir); err == nil || !strings.Contains(err.Error(), "nested too deeply") {
+		t.Errorf("Expected an include cycle error, got %v", err)
+	}
+}
+
+// TestCompareVersions verifies numeric comparison of extension versions
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.2", 1},
+		{"1.0", "1.0.0", 0},
+		{"2.0.0-beta", "2.0.1", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
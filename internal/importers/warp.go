@@ -0,0 +1,158 @@
+package importers
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+	"gopkg.in/yaml.v3"
+)
+
+// Warp imports Warp theme files, including community themes with a
+// background image or gradient
+var Warp theme.Importer = warpImporter{}
+
+type warpImporter struct{}
+
+// warpThemeFile is Warp's theme schema. Background and accent are a color or
+// a gradient ({top, bottom} or {left, right}).
+type warpThemeFile struct {
+	Name            string `yaml:"name"`
+	Accent          any    `yaml:"accent"`
+	Cursor          string `yaml:"cursor"`
+	Background      any    `yaml:"background"`
+	Foreground      string `yaml:"foreground"`
+	Details         string `yaml:"details"` // "darker" or "lighter"
+	BackgroundImage *struct {
+		Path    string `yaml:"path"`
+		Opacity int    `yaml:"opacity"`
+	} `yaml:"background_image"`
+	TerminalColors struct {
+		Normal map[string]string `yaml:"normal"`
+		Bright map[string]string `yaml:"bright"`
+	} `yaml:"terminal_colors"`
+}
+
+// WarpThemesPath returns the directory Warp loads custom themes from, for the given home directory
+func WarpThemesPath(home string) string {
+	return filepath.Join(home, ".warp", "themes")
+}
+
+func (warpImporter) Name() string { return "warp" }
+
+func (warpImporter) Detect(path string, data []byte) bool {
+	return hasExt(path, ".yaml", ".yml") && bytes.Contains(data, []byte("terminal_colors"))
+}
+
+func (warpImporter) Parse(path string, data []byte) ([]theme.Theme, error) {
+	t, err := ParseWarpTheme(FileThemeName(path), data)
+	if err != nil {
+		return nil, err
+	}
+	return []theme.Theme{t}, nil
+}
+
+// ImportWarp reads a Warp theme file or, recursively, the theme files in a
+// directory such as ~/.warp/themes
+func ImportWarp(path string) ([]theme.Theme, error) {
+	return Import(Warp, path)
+}
+
+// ParseWarpTheme converts a Warp theme into a theme, named by the file's name
+// key or else the given name. The accent becomes the palette's accent and
+// details its appearance. A gradient counts as the color halfway along it,
+// which is what most of the text sits on. Background images aren't part of
+// the palette; themes with one are tagged "background-image", and their
+// background is the color Warp shows under the image.
+func ParseWarpTheme(name string, data []byte) (theme.Theme, error) {
+	var file warpThemeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return theme.Theme{}, err
+	}
+	if file.Name != "" {
+		name = file.Name
+	}
+
+	t := theme.Theme{Name: name, Tags: []string{"warp"}}
+	background, gradient, err := warpColor(file.Background)
+	if err != nil {
+		return theme.Theme{}, fmt.Errorf("background: %w", err)
+	}
+	if gradient {
+		t.Tags = append(t.Tags, "gradient")
+	}
+	accent, _, err := warpColor(file.Accent)
+	if err != nil {
+		return theme.Theme{}, fmt.Errorf("accent: %w", err)
+	}
+	if file.BackgroundImage != nil && file.BackgroundImage.Path != "" {
+		t.Tags = append(t.Tags, "background-image")
+	}
+
+	switch strings.ToLower(file.Details) {
+	case "lighter":
+		t.Appearance = theme.AppearanceLight
+	case "darker":
+		t.Appearance = theme.AppearanceDark
+	}
+
+	normal, bright := file.TerminalColors.Normal, file.TerminalColors.Bright
+	t.Colors = theme.ColorPalette{
+		Background:    background,
+		Foreground:    file.Foreground,
+		Black:         normal["black"],
+		Red:           normal["red"],
+		Green:         normal["green"],
+		Yellow:        normal["yellow"],
+		Blue:          normal["blue"],
+		Magenta:       normal["magenta"],
+		Cyan:          normal["cyan"],
+		White:         normal["white"],
+		BrightBlack:   bright["black"],
+		BrightRed:     bright["red"],
+		BrightGreen:   bright["green"],
+		BrightYellow:  bright["yellow"],
+		BrightBlue:    bright["blue"],
+		BrightMagenta: bright["magenta"],
+		BrightCyan:    bright["cyan"],
+		BrightWhite:   bright["white"],
+		Cursor:        file.Cursor,
+		Accent:        accent,
+	}
+	t.Source = &theme.ThemeSource{App: "warp", Theme: name}
+	if err := t.Normalize(); err != nil {
+		return theme.Theme{}, err
+	}
+	return t, nil
+}
+
+// warpColor returns a Warp color, or the middle of a gradient, and whether it
+// was a gradient
+func warpColor(value any) (string, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, false, nil
+	case map[string]any:
+		from, to := v["top"], v["bottom"]
+		if from == nil && to == nil {
+			from, to = v["left"], v["right"]
+		}
+		start, _ := from.(string)
+		end, _ := to.(string)
+		a, err := theme.ParseColor(start)
+		if err != nil {
+			return "", true, fmt.Errorf("gradient start: %w", err)
+		}
+		b, err := theme.ParseColor(end)
+		if err != nil {
+			return "", true, fmt.Errorf("gradient end: %w", err)
+		}
+		mid := func(x, y uint8) uint8 { return uint8((int(x) + int(y) + 1) / 2) }
+		return theme.Color{R: mid(a.R, b.R), G: mid(a.G, b.G), B: mid(a.B, b.B), A: 0xff}.Hex(), true, nil
+	}
+	return "", false, fmt.Errorf("unsupported value %v", value)
+}
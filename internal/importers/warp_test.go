@@ -0,0 +1,109 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+const warpGradientTheme = `name: Harbor Dusk
+accent:
+  left: '#fe8019'
+  right: '#fabd2f'
+background:
+  top: '#1d2021'
+  bottom: '#3c3836'
+foreground: '#ebdbb2'
+details: darker
+background_image:
+  path: harbor.jpg
+  opacity: 40
+terminal_colors:
+  normal:
+    black: '#282828'
+    red: '#cc241d'
+    green: '#98971a'
+    yellow: '#d79921'
+    blue: '#458588'
+    magenta: '#b16286'
+    cyan: '#689d6a'
+    white: '#a89984'
+  bright:
+    black: '#928374'
+    red: '#fb4934'
+    green: '#b8bb26'
+    yellow: '#fabd2f'
+    blue: '#83a598'
+    magenta: '#d3869b'
+    cyan: '#8ec07c'
+    white: '#ebdbb2'
+`
+
+// TestParseWarpTheme verifies gradients, the background image tag and the
+// accent, appearance and source metadata
+func TestParseWarpTheme(t *testing.T) {
+	th, err := ParseWarpTheme("harbor_dusk", []byte(warpGradientTheme))
+	if err != nil {
+		t.Fatalf("ParseWarpTheme failed: %v", err)
+	}
+
+	checks := map[string][2]string{
+		"name":       {th.Name, "Harbor Dusk"},
+		"background": {th.Colors.Background, "#2d2c2c"}, // Halfway down the gradient
+		"accent":     {th.Colors.Accent, "#fc9f24"},
+		"bright_red": {th.Colors.BrightRed, "#fb4934"},
+		"cursor":     {th.Colors.Cursor, ""},
+		"tags":       {strings.Join(th.Tags, ","), "warp,gradient,background-image"},
+	}
+	for field, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("Expected %s %q, got %q", field, c[1], c[0])
+		}
+	}
+	if th.Appearance != theme.AppearanceDark {
+		t.Errorf("Expected a dark theme, got %s", th.Appearance)
+	}
+	if th.Source == nil || th.Source.App != "warp" || th.Source.Theme != "Harbor Dusk" {
+		t.Errorf("Unexpected source: %+v", th.Source)
+	}
+
+	light := strings.NewReplacer("details: darker", "details: lighter", "name: Harbor Dusk\n", "").Replace(warpGradientTheme)
+	th, err = ParseWarpTheme("harbor_dawn", []byte(light))
+	if err != nil || th.Name != "harbor_dawn" || th.Appearance != theme.AppearanceLight {
+		t.Errorf("Expected a light theme named after the file, got %q %s (%v)", th.Name, th.Appearance, err)
+	}
+
+	broken := strings.Replace(warpGradientTheme, "bottom: '#3c3836'", "bottom: nope", 1)
+	if _, err := ParseWarpTheme("broken", []byte(broken)); err == nil || !strings.Contains(err.Error(), "background") {
+		t.Errorf("Expected an invalid gradient to be reported, got %v", err)
+	}
+}
+
+// TestImportWarp verifies that every theme file below a themes directory is
+// read and other YAML files are skipped
+func TestImportWarp(t *testing.T) {
+	dir := t.TempDir()
+	solid := strings.NewReplacer("name: Harbor Dusk", "name: Harbor Night",
+		"background:\n  top: '#1d2021'\n  bottom: '#3c3836'", "background: '#1d2021'").Replace(warpGradientTheme)
+	writeFiles(t, dir, map[string]string{
+		"standard/harbor_dusk.yaml": warpGradientTheme,
+		"harbor_night.yml":          solid,
+		"harbor_night.yaml.backup":  solid,
+		"settings.yaml":             "font_size: 13\n",
+		"base16/base16_harbor.yaml": strings.Replace(solid, "Harbor Night", "Base16 Harbor", 1),
+	})
+
+	themes, err := ImportWarp(dir)
+	if err != nil {
+		t.Fatalf("ImportWarp failed: %v", err)
+	}
+	if len(themes) != 3 {
+		t.Errorf("Expected 3 themes, got %d", len(themes))
+	}
+	for _, th := range themes {
+		if th.Name == "Harbor Night" && th.Colors.Background != "#1d2021" {
+			t.Errorf("Expected a solid background, got %q", th.Colors.Background)
+		}
+	}
+}
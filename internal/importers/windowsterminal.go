@@ -0,0 +1,146 @@
+package importers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// WindowsTerminal imports Windows Terminal color schemes: the schemes of a
+// settings.json, an array of schemes or a single scheme
+var WindowsTerminal theme.Importer = windowsTerminalImporter{}
+
+type windowsTerminalImporter struct{}
+
+// windowsTerminalScheme is an entry of the schemes list in settings.json
+type windowsTerminalScheme struct {
+	Name                string `json:"name"`
+	Background          string `json:"background"`
+	Foreground          string `json:"foreground"`
+	CursorColor         string `json:"cursorColor"`
+	SelectionBackground string `json:"selectionBackground"`
+	Black               string `json:"black"`
+	Red                 string `json:"red"`
+	Green               string `json:"green"`
+	Yellow              string `json:"yellow"`
+	Blue                string `json:"blue"`
+	Purple              string `json:"purple"`
+	Cyan                string `json:"cyan"`
+	White               string `json:"white"`
+	BrightBlack         string `json:"brightBlack"`
+	BrightRed           string `json:"brightRed"`
+	BrightGreen         string `json:"brightGreen"`
+	BrightYellow        string `json:"brightYellow"`
+	BrightBlue          string `json:"brightBlue"`
+	BrightPurple        string `json:"brightPurple"`
+	BrightCyan          string `json:"brightCyan"`
+	BrightWhite         string `json:"brightWhite"`
+}
+
+func (windowsTerminalImporter) Name() string { return "windows-terminal" }
+
+func (windowsTerminalImporter) Detect(path string, data []byte) bool {
+	if !hasExt(path, ".json", ".jsonc") {
+		return false
+	}
+	schemes, err := windowsTerminalSchemes(data)
+	return err == nil && len(schemes) > 0
+}
+
+func (windowsTerminalImporter) Parse(path string, data []byte) ([]theme.Theme, error) {
+	schemes, err := windowsTerminalSchemes(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemes) == 0 {
+		return nil, fmt.Errorf("no Windows Terminal color schemes found")
+	}
+
+	var themes []theme.Theme
+	var errs []error
+	for _, scheme := range schemes {
+		t, err := scheme.theme()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", scheme.Name, err))
+			continue
+		}
+		themes = append(themes, t)
+	}
+	return themes, errors.Join(errs...)
+}
+
+// windowsTerminalSchemes decodes the schemes of a settings.json (which may
+// have comments), of an array or of a single scheme object
+func windowsTerminalSchemes(data []byte) ([]windowsTerminalScheme, error) {
+	var raw json.RawMessage
+	if err := unmarshalJSONC(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var schemes []windowsTerminalScheme
+	if err := json.Unmarshal(raw, &schemes); err == nil {
+		return schemeList(schemes), nil
+	}
+	var settings struct {
+		Schemes []windowsTerminalScheme `json:"schemes"`
+	}
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return nil, err
+	}
+	if len(settings.Schemes) > 0 {
+		return schemeList(settings.Schemes), nil
+	}
+	var scheme windowsTerminalScheme
+	if err := json.Unmarshal(raw, &scheme); err != nil {
+		return nil, err
+	}
+	return schemeList([]windowsTerminalScheme{scheme}), nil
+}
+
+// schemeList drops objects that aren't color schemes, which Windows Terminal
+// schemes always name and give a purple
+func schemeList(schemes []windowsTerminalScheme) []windowsTerminalScheme {
+	var list []windowsTerminalScheme
+	for _, scheme := range schemes {
+		if scheme.Name != "" && scheme.Purple != "" {
+			list = append(list, scheme)
+		}
+	}
+	return list
+}
+
+// theme converts the scheme; Windows Terminal calls magenta purple
+func (s windowsTerminalScheme) theme() (theme.Theme, error) {
+	t := theme.Theme{
+		Name: s.Name,
+		Tags: []string{"windows-terminal"},
+		Colors: theme.ColorPalette{
+			Background:          s.Background,
+			Foreground:          s.Foreground,
+			Black:               s.Black,
+			Red:                 s.Red,
+			Green:               s.Green,
+			Yellow:              s.Yellow,
+			Blue:                s.Blue,
+			Magenta:             s.Purple,
+			Cyan:                s.Cyan,
+			White:               s.White,
+			BrightBlack:         s.BrightBlack,
+			BrightRed:           s.BrightRed,
+			BrightGreen:         s.BrightGreen,
+			BrightYellow:        s.BrightYellow,
+			BrightBlue:          s.BrightBlue,
+			BrightMagenta:       s.BrightPurple,
+			BrightCyan:          s.BrightCyan,
+			BrightWhite:         s.BrightWhite,
+			Cursor:              s.CursorColor,
+			SelectionBackground: s.SelectionBackground,
+		},
+	}
+	if err := t.Normalize(); err != nil {
+		return theme.Theme{}, err
+	}
+	return t, nil
+}
@@ -0,0 +1,141 @@
+package importers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// Xresources imports the terminal colors of X resource files (.Xresources, .Xdefaults)
+var Xresources theme.Importer = xresourcesImporter{}
+
+type xresourcesImporter struct{}
+
+// xresourcesColorLine matches resources such as "*.color0:", "URxvt*background:"
+var xresourcesColorLine = regexp.MustCompile(`(?m)^\s*[\w.*-]*[.*](color\d+|background|foreground)\s*:`)
+
+func (xresourcesImporter) Name() string { return "xresources" }
+
+func (xresourcesImporter) Detect(path string, data []byte) bool {
+	base := strings.ToLower(filepath.Base(path))
+	if strings.Contains(base, "xresources") || strings.Contains(base, "xdefaults") {
+		return true
+	}
+	return xresourcesColorLine.Match(data)
+}
+
+func (xresourcesImporter) Parse(path string, data []byte) ([]theme.Theme, error) {
+	t, err := ParseXresources(FileThemeName(path), data)
+	if err != nil {
+		return nil, err
+	}
+	return []theme.Theme{t}, nil
+}
+
+// xresourcesPaletteKeys returns the palette slot each color resource fills
+func xresourcesPaletteKeys(p *theme.ColorPalette) map[string]*string {
+	keys := map[string]*string{
+		"background":         &p.Background,
+		"foreground":         &p.Foreground,
+		"cursorColor":        &p.Cursor,
+		"highlightColor":     &p.SelectionBackground,
+		"highlightTextColor": &p.SelectionForeground,
+	}
+	for i, slot := range []*string{
+		&p.Black, &p.Red, &p.Green, &p.Yellow, &p.Blue, &p.Magenta, &p.Cyan, &p.White,
+		&p.BrightBlack, &p.BrightRed, &p.BrightGreen, &p.BrightYellow,
+		&p.BrightBlue, &p.BrightMagenta, &p.BrightCyan, &p.BrightWhite,
+	} {
+		keys["color"+strconv.Itoa(i)] = slot
+	}
+	return keys
+}
+
+// ParseXresources converts the color resources of an X resource file into a
+// theme with the given name. Values may name a #define, as base16-xresources
+// files do, and use the X11 "rgb:rr/gg/bb" notation. Resources are matched by
+// their last component, so "*.color0", "*color0" and "URxvt*color0" all fill
+// black; later lines win.
+func ParseXresources(name string, data []byte) (theme.Theme, error) {
+	t := theme.Theme{Name: name, Tags: []string{"xresources"}}
+	slots := xresourcesPaletteKeys(&t.Colors)
+	defines := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if directive, ok := strings.CutPrefix(line, "#"); ok {
+			// #define NAME VALUE; other preprocessor directives are ignored
+			fields := strings.Fields(directive)
+			if len(fields) >= 3 && fields[0] == "define" {
+				defines[fields[1]] = strings.Join(fields[2:], " ")
+			}
+			continue
+		}
+
+		resource, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		resource = strings.TrimSpace(resource)
+		key := resource[strings.LastIndexAny(resource, ".*")+1:]
+		slot, ok := slots[key]
+		if !ok {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		// Defines may refer to other defines; the bound stops cycles
+		for i := 0; i < 8; i++ {
+			expanded, ok := defines[value]
+			if !ok {
+				break
+			}
+			value = expanded
+		}
+		color, err := xColor(value)
+		if err != nil {
+			return theme.Theme{}, fmt.Errorf("%s: %w", resource, err)
+		}
+		*slot = color
+	}
+	if err := scanner.Err(); err != nil {
+		return theme.Theme{}, err
+	}
+
+	if err := t.Normalize(); err != nil {
+		return theme.Theme{}, err
+	}
+	return t, nil
+}
+
+// xColor converts the X11 "rgb:r/g/b" notation, with 1–4 hex digits per
+// component, to hex; other values are returned for theme.ParseColor
+func xColor(value string) (string, error) {
+	spec, ok := strings.CutPrefix(strings.ToLower(value), "rgb:")
+	if !ok {
+		return value, nil
+	}
+	parts := strings.Split(spec, "/")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid color %q", value)
+	}
+	var rgb [3]float64
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 16, 16)
+		if err != nil || len(part) > 4 {
+			return "", fmt.Errorf("invalid color %q", value)
+		}
+		rgb[i] = float64(n) / float64(uint64(1)<<(4*len(part))-1)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", channel(rgb[0]), channel(rgb[1]), channel(rgb[2])), nil
+}
@@ -6,32 +6,26 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"zakaranda/internal/theme"
 
 	"github.com/BurntSushi/toml"
+	"github.com/dahromy/zakaranda/internal/theme"
 	"gopkg.in/yaml.v3"
 )
 
 type AlacrittyIntegration struct {
+	sideEffects
 	configPath string
 	themesPath string
 }
 
-// Map theme names to official Alacritty theme repository file names
-var alacrittyThemeMap = map[string]string{
-	"Nord":                 "nord.toml",
-	"Catppuccin Latte":     "catppuccin_latte.toml",
-	"Catppuccin Frappe":    "catppuccin_frappe.toml",
-	"Catppuccin Macchiato": "catppuccin_macchiato.toml",
-	"Catppuccin Mocha":     "catppuccin_mocha.toml",
-	"Rose Pine":            "rose_pine.toml",
-	"Rose Pine Moon":       "rose_pine_moon.toml",
-	"Rose Pine Dawn":       "rose_pine_dawn.toml",
+func NewAlacrittyIntegration() *AlacrittyIntegration {
+	home, _ := os.UserHomeDir()
+	return newAlacrittyIntegration(home)
 }
 
-func NewAlacrittyIntegration() *AlacrittyIntegration {
-	home, err := os.UserHomeDir()
-	if err != nil {
+// newAlacrittyIntegration returns the integration with its configuration below home
+func newAlacrittyIntegration(home string) *AlacrittyIntegration {
+	if home == "" {
 		// Fallback to empty string, will be caught by IsInstalled
 		return &AlacrittyIntegration{configPath: "", themesPath: ""}
 	}
@@ -58,6 +52,12 @@ func (a *AlacrittyIntegration) ConfigPath() string {
 	return a.configPath
 }
 
+// CommunityThemesPath returns the themes directory of the cloned
+// alacritty-theme repository, which exists once a theme has been applied
+func (a *AlacrittyIntegration) CommunityThemesPath() string {
+	return filepath.Join(a.themesPath, "alacritty", "themes")
+}
+
 func (a *AlacrittyIntegration) IsInstalled() bool {
 	// Check if config directory exists
 	_, err := os.Stat(filepath.Dir(a.configPath))
@@ -65,37 +65,23 @@ func (a *AlacrittyIntegration) IsInstalled() bool {
 }
 
 func (a *AlacrittyIntegration) Apply(t theme.Theme) error {
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+
 	// Ensure theme repository is cloned
 	if err := a.ensureThemeRepo(); err != nil {
 		return fmt.Errorf("failed to setup theme repository: %w", err)
 	}
 
-	// Check if official theme exists
-	officialTheme, hasOfficial := alacrittyThemeMap[t.Name]
-	officialThemePath := filepath.Join(a.themesPath, "alacritty", "themes", officialTheme)
+	// Check if official theme exists (themes extending a built-in without changes use its file)
+	officialTheme, hasOfficial := alacrittyThemeMap[t.OfficialName()]
+	officialThemePath := filepath.Join(a.CommunityThemesPath(), officialTheme)
 
-	// Read existing config
-	var config map[string]any
-	data, err := os.ReadFile(a.configPath)
+	config, data, err := a.readConfig()
 	if err != nil {
-		if os.IsNotExist(err) {
-			config = make(map[string]any)
-		} else {
-			return fmt.Errorf("failed to read config: %w", err)
-		}
-	} else {
-		// Determine file format based on extension
-		isTOML := strings.HasSuffix(strings.ToLower(a.configPath), ".toml")
-
-		if isTOML {
-			if err := toml.Unmarshal(data, &config); err != nil {
-				return fmt.Errorf("failed to parse TOML config: %w", err)
-			}
-		} else {
-			if err := yaml.Unmarshal(data, &config); err != nil {
-				return fmt.Errorf("failed to parse YAML config: %w", err)
-			}
-		}
+		return err
 	}
 
 	// Create backup
@@ -135,22 +121,60 @@ func (a *AlacrittyIntegration) Apply(t theme.Theme) error {
 		}
 	}
 
-	// Write updated config in the same format
-	var newData []byte
+	return a.writeConfig(config)
+}
 
-	if isTOML {
-		// For TOML, we need to encode properly
+// ApplyPalette writes the theme's colors into the config, replacing any
+// official theme import
+func (a *AlacrittyIntegration) ApplyPalette(t theme.Theme) error {
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+	config, _, err := a.readConfig()
+	if err != nil {
+		return err
+	}
+	config["colors"] = a.generateAlacrittyColors(t)
+	if general, ok := config["general"].(map[string]any); ok {
+		delete(general, "import")
+	}
+	return a.writeConfig(config)
+}
+
+// readConfig parses the TOML or YAML config and returns it with the file's
+// contents, or an empty config when there is no file
+func (a *AlacrittyIntegration) readConfig() (map[string]any, []byte, error) {
+	config := make(map[string]any)
+	data, err := os.ReadFile(a.configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if strings.HasSuffix(strings.ToLower(a.configPath), ".toml") {
+		if err := toml.Unmarshal(data, &config); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse TOML config: %w", err)
+		}
+	} else if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+	return config, data, nil
+}
+
+// writeConfig writes the config in the format of its file
+func (a *AlacrittyIntegration) writeConfig(config map[string]any) error {
+	var newData []byte
+	if strings.HasSuffix(strings.ToLower(a.configPath), ".toml") {
 		buf := new(strings.Builder)
-		encoder := toml.NewEncoder(buf)
-		if err := encoder.Encode(config); err != nil {
+		if err := toml.NewEncoder(buf).Encode(config); err != nil {
 			return fmt.Errorf("failed to marshal TOML config: %w", err)
 		}
 		newData = []byte(buf.String())
 	} else {
-		// For YAML, use manual colors (import not supported in YAML)
-		colors := a.generateAlacrittyColors(t)
-		config["colors"] = colors
-
+		var err error
 		newData, err = yaml.Marshal(config)
 		if err != nil {
 			return fmt.Errorf("failed to marshal YAML config: %w", err)
@@ -160,7 +184,6 @@ func (a *AlacrittyIntegration) Apply(t theme.Theme) error {
 	if err := os.WriteFile(a.configPath, newData, 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
-
 	return nil
 }
 
@@ -173,7 +196,11 @@ func (a *AlacrittyIntegration) ensureThemeRepo() error {
 		return nil
 	}
 
-	// Repository doesn't exist, clone it
+	// Repository doesn't exist, clone it (official themes fall back to
+	// the palette without it)
+	if !a.allowed("downloading the alacritty-theme repository") {
+		return nil
+	}
 	return a.cloneThemeRepo()
 }
 
@@ -0,0 +1,124 @@
+package integrations
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dataFS holds the official app themes of each built-in family
+// (data/apps/<family>.yaml) and the files they refer to. Adding a family
+// means adding a file there rather than editing the integrations.
+//
+//go:embed data
+var dataFS embed.FS
+
+// appThemes lists the official app themes matching one built-in theme
+type appThemes struct {
+	VSCode    *VSCodeThemeExtension `yaml:"vscode"`
+	Zed       *ZedExtension         `yaml:"zed"`
+	Alacritty string                `yaml:"alacritty"` // File in the alacritty-theme repository
+	Starship  *starshipTheme        `yaml:"starship"`
+	Wallpaper string                `yaml:"wallpaper"` // File in assets/wallpapers
+}
+
+// starshipTheme is an official Starship configuration with its palette
+type starshipTheme struct {
+	Config  string `yaml:"config"`  // Template in data/starship; palette.toml when empty
+	Palette string `yaml:"palette"` // Palette the template defines or selects
+}
+
+// Official app themes by theme name, loaded from dataFS
+var (
+	vscodeThemeExtensions = make(map[string]VSCodeThemeExtension)
+	zedThemeExtensions    = make(map[string]ZedExtension)
+	alacrittyThemeMap     = make(map[string]string)
+	starshipThemes        = make(map[string]starshipTheme)
+	wallpaperThemeMap     = make(map[string]string)
+
+	// Extensions providing icon themes, by icon theme ID
+	iconThemeExtensions map[string]string
+
+	starshipTemplates *template.Template
+)
+
+func init() {
+	if err := loadAppData(dataFS); err != nil {
+		panic(err) // Embedded files are checked by the tests
+	}
+}
+
+// loadAppData fills the app theme maps from the data directory of fsys
+func loadAppData(fsys fs.FS) error {
+	paths, err := fs.Glob(fsys, "data/apps/*.yaml")
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		var themes map[string]appThemes
+		if err := decodeYAMLFile(fsys, p, &themes); err != nil {
+			return err
+		}
+		for name, apps := range themes {
+			if seen[name] {
+				return fmt.Errorf("%s: app themes for %q are defined twice", path.Base(p), name)
+			}
+			seen[name] = true
+			addAppThemes(name, apps)
+		}
+	}
+
+	if err := decodeYAMLFile(fsys, "data/vscode-icons.yaml", &iconThemeExtensions); err != nil {
+		return err
+	}
+
+	starshipTemplates, err = template.ParseFS(fsys, "data/starship/*.toml")
+	if err != nil {
+		return err
+	}
+	for name, starship := range starshipThemes {
+		if starship.Config != "" && starshipTemplates.Lookup(starship.Config) == nil {
+			return fmt.Errorf("%s: unknown Starship config %q", name, starship.Config)
+		}
+	}
+	return nil
+}
+
+// addAppThemes registers the official app themes of one theme
+func addAppThemes(name string, apps appThemes) {
+	if apps.VSCode != nil {
+		vscodeThemeExtensions[name] = *apps.VSCode
+	}
+	if apps.Zed != nil {
+		zedThemeExtensions[name] = *apps.Zed
+	}
+	if apps.Alacritty != "" {
+		alacrittyThemeMap[name] = apps.Alacritty
+	}
+	if apps.Starship != nil {
+		starshipThemes[name] = *apps.Starship
+	}
+	if apps.Wallpaper != "" {
+		wallpaperThemeMap[name] = apps.Wallpaper
+	}
+}
+
+// decodeYAMLFile decodes a YAML file of fsys into v, rejecting unknown keys
+func decodeYAMLFile(fsys fs.FS, name string, v any) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", path.Base(name), err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package integrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// TestBuiltInAppThemes verifies every built-in theme has a Starship palette
+// and a wallpaper in the assets, and every app theme belongs to a built-in theme
+func TestBuiltInAppThemes(t *testing.T) {
+	builtIn := make(map[string]bool)
+	for _, th := range theme.GetBuiltInThemes() {
+		builtIn[th.Name] = true
+
+		if _, ok := starshipThemes[th.Name]; !ok {
+			t.Errorf("%s: missing Starship palette", th.Name)
+		}
+		if file, ok := wallpaperThemeMap[th.Name]; !ok {
+			t.Errorf("%s: missing wallpaper", th.Name)
+		} else if _, err := os.Stat(filepath.Join("..", "..", "assets", "wallpapers", file)); err != nil {
+			t.Errorf("%s: wallpaper not in assets: %v", th.Name, err)
+		}
+	}
+
+	for _, names := range [][]string{
+		mapKeys(vscodeThemeExtensions), mapKeys(zedThemeExtensions), mapKeys(alacrittyThemeMap),
+		mapKeys(starshipThemes), mapKeys(wallpaperThemeMap),
+	} {
+		for _, name := range names {
+			if !builtIn[name] {
+				t.Errorf("App themes defined for unknown theme %q", name)
+			}
+		}
+	}
+}
+
+// mapKeys returns the keys of m
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// TestStarshipRenderConfig verifies official configs select their palette and
+// other themes get a config generated from their colors
+func TestStarshipRenderConfig(t *testing.T) {
+	s := newStarshipIntegration(t.TempDir())
+	themes := theme.GetBuiltInThemes()
+
+	config, err := s.renderConfig(themes[2]) // Catppuccin Frappe
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(config, "palette = 'catppuccin_frappe'") {
+		t.Error("Expected the Catppuccin config to select the Frappe palette")
+	}
+
+	custom := themes[0]
+	custom.Name = "Custom"
+	config, err = s.renderConfig(custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(config, `background = "#2e3440"`) || !strings.Contains(config, `"Custom" palette`) {
+		t.Errorf("Expected a config generated from the palette, got:\n%s", config)
+	}
+	// Powerline separators and module icons, as in the official configs
+	for _, glyph := range []string{"[\ue0b6](blue)", "[\ue0b0](bg:cyan fg:blue)", "symbol = \"\uf418\"", "[[ \uf43a $time"} {
+		if !strings.Contains(config, glyph) {
+			t.Errorf("Expected %q in the generated config", glyph)
+		}
+	}
+
+	gruvbox, _ := theme.DefaultRegistry().Lookup("Gruvbox Light")
+	config, err = s.renderConfig(gruvbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(config, "[palettes.gruvbox_light]") || !strings.Contains(config, `background = "#fbf1c7"`) {
+		t.Errorf("Expected a generated config with a Gruvbox Light palette, got:\n%s", config)
+	}
+}
@@ -0,0 +1,123 @@
+package integrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// backupSuffix is appended to a file's path when an integration backs it up
+const backupSuffix = ".backup"
+
+// BackupIntegration is implemented by integrations that back up the files
+// they overwrite, so a previous configuration can be restored
+type BackupIntegration interface {
+	Integration
+
+	// Backups returns the paths of the existing backup files
+	Backups() []string
+}
+
+// RestoreBackup copies a backup file over the file it was made from
+func RestoreBackup(backupPath string) error {
+	if !strings.HasSuffix(backupPath, backupSuffix) {
+		return fmt.Errorf("%s is not a backup file", backupPath)
+	}
+	data, err := os.ReadFile(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if err := os.WriteFile(strings.TrimSuffix(backupPath, backupSuffix), data, 0644); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	return nil
+}
+
+// backupFile copies the file at path to its backup, unless it is missing or empty
+func backupFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := os.WriteFile(path+backupSuffix, data, 0644); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	return nil
+}
+
+// existingBackups returns the backup of each path that exists
+func existingBackups(paths ...string) []string {
+	var backups []string
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path + backupSuffix); err == nil {
+			backups = append(backups, path+backupSuffix)
+		}
+	}
+	return backups
+}
+
+// backupsIn returns every backup file in dir
+func backupsIn(dir string) []string {
+	if dir == "" {
+		return nil
+	}
+	backups, _ := filepath.Glob(filepath.Join(dir, "*"+backupSuffix))
+	return backups
+}
+
+// Backups returns the backup of the VS Code settings file, if any
+func (v *VSCodeIntegration) Backups() []string {
+	return existingBackups(v.configPath)
+}
+
+// Backup backs up the VS Code settings file, which ApplyPalette overwrites
+func (v *VSCodeIntegration) Backup() error {
+	return backupFile(v.configPath)
+}
+
+// Backups returns the backup of the Alacritty configuration file, if any
+func (a *AlacrittyIntegration) Backups() []string {
+	return existingBackups(a.configPath)
+}
+
+// Backup backs up the Alacritty configuration file, which ApplyPalette overwrites
+func (a *AlacrittyIntegration) Backup() error {
+	return backupFile(a.configPath)
+}
+
+// Backups returns the backup of the Starship configuration file, if any
+func (s *StarshipIntegration) Backups() []string {
+	return existingBackups(s.configPath)
+}
+
+// Backups returns the backup of the Zed settings file, if any
+func (z *ZedIntegration) Backups() []string {
+	return existingBackups(z.configPath)
+}
+
+// Backups returns the backups of the generated iTerm2 presets
+func (i *ITerm2Integration) Backups() []string {
+	return backupsIn(i.themesPath)
+}
+
+// Backups returns the backups of the generated Warp themes
+func (w *WarpIntegration) Backups() []string {
+	return backupsIn(w.themesPath)
+}
+
+// Backup does nothing: ApplyPalette writes a preset of each palette's own
+func (i *ITerm2Integration) Backup() error {
+	return nil
+}
+
+// Backup does nothing: ApplyPalette writes a theme file of each palette's own
+func (w *WarpIntegration) Backup() error {
+	return nil
+}
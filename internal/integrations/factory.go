@@ -1,15 +1,38 @@
 package integrations
 
+import "os"
+
 // GetAllIntegrations returns all available integrations
 func GetAllIntegrations() []Integration {
+	home, _ := os.UserHomeDir()
+	return GetIntegrationsForHome(home)
+}
+
+// GetIntegrationsForHome returns all integrations with their configuration
+// below home instead of the user's home directory
+func GetIntegrationsForHome(home string) []Integration {
 	return []Integration{
-		NewVSCodeIntegration(),
-		NewAlacrittyIntegration(),
-		NewWarpIntegration(),
-		NewITerm2Integration(),
-		NewStarshipIntegration(),
-		NewZedIntegration(),
-		NewWallpaperIntegration(),
+		newVSCodeIntegration(home),
+		newAlacrittyIntegration(home),
+		newWarpIntegration(home),
+		newITerm2Integration(home),
+		newStarshipIntegration(home),
+		newZedIntegration(home),
+		newWallpaperIntegration(home),
 		NewSlackIntegration(),
 	}
 }
+
+// GetSandboxedIntegrations returns all integrations with their configuration
+// below home, skipping every change outside it: the desktop picture, VS Code
+// extensions, the clipboard, opening Slack and downloading theme repositories.
+// The skipped steps are reported as notices.
+func GetSandboxedIntegrations(home string) []Integration {
+	apps := GetIntegrationsForHome(home)
+	for _, app := range apps {
+		if s, ok := app.(interface{ sandbox() }); ok {
+			s.sandbox()
+		}
+	}
+	return apps
+}
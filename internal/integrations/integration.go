@@ -1,6 +1,10 @@
 package integrations
 
-import "zakaranda/internal/theme"
+import (
+	"fmt"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
 
 // Integration defines the interface that all application integrations must implement
 type Integration interface {
@@ -16,3 +20,88 @@ type Integration interface {
 	// ConfigPath returns the path to the application's configuration file
 	ConfigPath() string
 }
+
+// AppearanceIntegration is implemented by integrations whose application can
+// switch between a light and a dark theme following the system appearance
+type AppearanceIntegration interface {
+	Integration
+
+	// ApplyPair applies both themes, letting the application pick by appearance
+	ApplyPair(pair theme.Pair) error
+}
+
+// PaletteIntegration is implemented by integrations that can write any
+// palette as the application's colors. Unlike Apply, ApplyPalette never uses
+// an official theme and makes no backup, so it suits palettes that change
+// often, such as the steps of a transition.
+type PaletteIntegration interface {
+	Integration
+
+	// ApplyPalette writes the theme's palette as the application's colors
+	ApplyPalette(theme theme.Theme) error
+
+	// Backup backs up the files ApplyPalette overwrites, once before a
+	// series of palettes, so Restore returns to the configuration before it
+	Backup() error
+}
+
+// NoticeIntegration is implemented by integrations with more to tell after
+// applying than an error, such as manual steps or a step that failed or was
+// skipped without failing the apply
+type NoticeIntegration interface {
+	Integration
+
+	// Notices returns the notices of the applies since the last call
+	Notices() []string
+}
+
+// prepareTheme validates the theme's palette and returns a copy with every
+// color in canonical hex form, so malformed colors fail before any file is written
+func prepareTheme(t theme.Theme) (theme.Theme, error) {
+	if err := t.Normalize(); err != nil {
+		return t, fmt.Errorf("invalid colors in theme %q: %w", t.Name, err)
+	}
+	return t, nil
+}
+
+// colorOr returns color, or fallback when the optional palette slot is unset
+func colorOr(color, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	return color
+}
+
+// sideEffects is embedded by integrations that change the system outside
+// their home directory, such as the desktop picture or installed extensions.
+// It collects their notices and, when sandboxed, skips those changes.
+type sideEffects struct {
+	sandboxed bool
+	notices   []string
+}
+
+// Notices returns the notices of the applies since the last call
+func (s *sideEffects) Notices() []string {
+	notices := s.notices
+	s.notices = nil
+	return notices
+}
+
+// note adds a notice
+func (s *sideEffects) note(format string, args ...interface{}) {
+	s.notices = append(s.notices, fmt.Sprintf(format, args...))
+}
+
+// sandbox keeps the integration to the files below its home directory
+func (s *sideEffects) sandbox() {
+	s.sandboxed = true
+}
+
+// allowed reports whether the step, which changes the system outside the
+// home directory, may run, noting it as skipped when sandboxed
+func (s *sideEffects) allowed(step string) bool {
+	if s.sandboxed {
+		s.note("Skipped %s (sandboxed)", step)
+	}
+	return !s.sandboxed
+}
@@ -6,20 +6,27 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"zakaranda/internal/theme"
+
+	"github.com/dahromy/zakaranda/internal/theme"
 )
 
 type ITerm2Integration struct {
+	home       string
 	themesPath string
 }
 
 func NewITerm2Integration() *ITerm2Integration {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	home, _ := os.UserHomeDir()
+	return newITerm2Integration(home)
+}
+
+// newITerm2Integration returns the integration with its configuration below home
+func newITerm2Integration(home string) *ITerm2Integration {
+	if home == "" {
 		return &ITerm2Integration{themesPath: ""}
 	}
 	themesPath := filepath.Join(home, ".config", "theme-manager", "iterm2")
-	return &ITerm2Integration{themesPath: themesPath}
+	return &ITerm2Integration{home: home, themesPath: themesPath}
 }
 
 func (i *ITerm2Integration) Name() string {
@@ -38,7 +45,7 @@ func (i *ITerm2Integration) IsInstalled() bool {
 	}
 
 	// Also check user's Applications folder
-	if home, err := os.UserHomeDir(); err == nil {
+	if home := i.home; home != "" {
 		possiblePaths = append(possiblePaths,
 			filepath.Join(home, "Applications", "iTerm.app"),
 			filepath.Join(home, "Applications", "iTerm2.app"),
@@ -56,18 +63,41 @@ func (i *ITerm2Integration) IsInstalled() bool {
 }
 
 func (i *ITerm2Integration) Apply(t theme.Theme) error {
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+
+	return i.writeAndImport(t, true)
+}
+
+// ApplyPalette writes and imports the theme's color preset
+func (i *ITerm2Integration) ApplyPalette(t theme.Theme) error {
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+	return i.writeAndImport(t, false)
+}
+
+// writeAndImport writes the theme's color preset, backing up the preset it
+// replaces when backup is set, and imports it into iTerm2's preferences
+func (i *ITerm2Integration) writeAndImport(t theme.Theme, backup bool) error {
 	// Create themes directory if it doesn't exist
 	if err := os.MkdirAll(i.themesPath, 0755); err != nil {
 		return fmt.Errorf("failed to create themes directory: %w", err)
 	}
 
 	// Generate iTerm2 color preset
-	preset := i.generateITerm2Preset(t)
+	preset, err := i.generateITerm2Preset(t)
+	if err != nil {
+		return fmt.Errorf("failed to generate preset: %w", err)
+	}
 	presetFileName := fmt.Sprintf("%s.itermcolors", theme.SanitizeFileName(t.Name))
 	presetPath := filepath.Join(i.themesPath, presetFileName)
 
 	// Create backup if file exists
-	if existingData, err := os.ReadFile(presetPath); err == nil && len(existingData) > 0 {
+	if existingData, err := os.ReadFile(presetPath); backup && err == nil && len(existingData) > 0 {
 		backupPath := presetPath + ".backup"
 		if err := os.WriteFile(backupPath, existingData, 0644); err != nil {
 			return fmt.Errorf("failed to create backup: %w", err)
@@ -86,87 +116,76 @@ func (i *ITerm2Integration) Apply(t theme.Theme) error {
 	return nil
 }
 
-func (i *ITerm2Integration) generateITerm2Preset(t theme.Theme) string {
+// iTermColorEntry is one color key of an iTerm2 color preset
+type iTermColorEntry struct {
+	key   string
+	color string
+	alpha float64
+}
+
+// iTermColorEntries maps a theme onto the keys of an iTerm2 color preset
+func iTermColorEntries(t theme.Theme) []iTermColorEntry {
+	return []iTermColorEntry{
+		{"Ansi 0 Color", t.Colors.Black, 1.0},
+		{"Ansi 1 Color", t.Colors.Red, 1.0},
+		{"Ansi 2 Color", t.Colors.Green, 1.0},
+		{"Ansi 3 Color", t.Colors.Yellow, 1.0},
+		{"Ansi 4 Color", t.Colors.Blue, 1.0},
+		{"Ansi 5 Color", t.Colors.Magenta, 1.0},
+		{"Ansi 6 Color", t.Colors.Cyan, 1.0},
+		{"Ansi 7 Color", t.Colors.White, 1.0},
+		{"Ansi 8 Color", t.Colors.BrightBlack, 1.0},
+		{"Ansi 9 Color", t.Colors.BrightRed, 1.0},
+		{"Ansi 10 Color", t.Colors.BrightGreen, 1.0},
+		{"Ansi 11 Color", t.Colors.BrightYellow, 1.0},
+		{"Ansi 12 Color", t.Colors.BrightBlue, 1.0},
+		{"Ansi 13 Color", t.Colors.BrightMagenta, 1.0},
+		{"Ansi 14 Color", t.Colors.BrightCyan, 1.0},
+		{"Ansi 15 Color", t.Colors.BrightWhite, 1.0},
+		{"Background Color", t.Colors.Background, 1.0},
+		{"Badge Color", t.Colors.Black, 0.5}, // Semi-transparent
+		{"Bold Color", t.Colors.BrightWhite, 1.0},
+		{"Cursor Color", colorOr(t.Colors.Cursor, t.Colors.Foreground), 1.0},
+		{"Cursor Guide Color", t.Colors.BrightBlack, 1.0}, // Subtle
+		{"Cursor Text Color", colorOr(t.Colors.CursorText, t.Colors.Background), 1.0},
+		{"Foreground Color", t.Colors.Foreground, 1.0},
+		{"Link Color", t.Colors.BrightCyan, 1.0}, // Bright cyan for visibility
+		{"Selected Text Color", colorOr(t.Colors.SelectionForeground, t.Colors.BrightBlack), 1.0},
+		{"Selection Color", colorOr(t.Colors.SelectionBackground, t.Colors.Foreground), 1.0},
+	}
+}
+
+func (i *ITerm2Integration) generateITerm2Preset(t theme.Theme) (string, error) {
 	// iTerm2 uses XML plist format for color schemes
 	// Format follows the official iTerm2 Color Schemes specification
-	template := `<?xml version="1.0" encoding="UTF-8"?>
+	var preset strings.Builder
+	preset.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
 <!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
 <plist version="1.0">
-<dict>
-	<key>Ansi 0 Color</key>%s
-	<key>Ansi 1 Color</key>%s
-	<key>Ansi 2 Color</key>%s
-	<key>Ansi 3 Color</key>%s
-	<key>Ansi 4 Color</key>%s
-	<key>Ansi 5 Color</key>%s
-	<key>Ansi 6 Color</key>%s
-	<key>Ansi 7 Color</key>%s
-	<key>Ansi 8 Color</key>%s
-	<key>Ansi 9 Color</key>%s
-	<key>Ansi 10 Color</key>%s
-	<key>Ansi 11 Color</key>%s
-	<key>Ansi 12 Color</key>%s
-	<key>Ansi 13 Color</key>%s
-	<key>Ansi 14 Color</key>%s
-	<key>Ansi 15 Color</key>%s
-	<key>Background Color</key>%s
-	<key>Badge Color</key>%s
-	<key>Bold Color</key>%s
-	<key>Cursor Color</key>%s
-	<key>Cursor Guide Color</key>%s
-	<key>Cursor Text Color</key>%s
-	<key>Foreground Color</key>%s
-	<key>Link Color</key>%s
-	<key>Selected Text Color</key>%s
-	<key>Selection Color</key>%s
-</dict>
-</plist>`
-
-	return fmt.Sprintf(template,
-		i.hexToITermColor(t.Colors.Black),               // Ansi 0
-		i.hexToITermColor(t.Colors.Red),                 // Ansi 1
-		i.hexToITermColor(t.Colors.Green),               // Ansi 2
-		i.hexToITermColor(t.Colors.Yellow),              // Ansi 3
-		i.hexToITermColor(t.Colors.Blue),                // Ansi 4
-		i.hexToITermColor(t.Colors.Magenta),             // Ansi 5
-		i.hexToITermColor(t.Colors.Cyan),                // Ansi 6
-		i.hexToITermColor(t.Colors.White),               // Ansi 7
-		i.hexToITermColor(t.Colors.BrightBlack),         // Ansi 8
-		i.hexToITermColor(t.Colors.BrightRed),           // Ansi 9
-		i.hexToITermColor(t.Colors.BrightGreen),         // Ansi 10
-		i.hexToITermColor(t.Colors.BrightYellow),        // Ansi 11
-		i.hexToITermColor(t.Colors.BrightBlue),          // Ansi 12
-		i.hexToITermColor(t.Colors.BrightMagenta),       // Ansi 13
-		i.hexToITermColor(t.Colors.BrightCyan),          // Ansi 14
-		i.hexToITermColor(t.Colors.BrightWhite),         // Ansi 15
-		i.hexToITermColor(t.Colors.Background),          // Background
-		i.hexToITermColorWithAlpha(t.Colors.Black, 0.5), // Badge (semi-transparent)
-		i.hexToITermColor(t.Colors.BrightWhite),         // Bold
-		i.hexToITermColor(t.Colors.Foreground),          // Cursor
-		i.hexToITermColor(t.Colors.BrightBlack),         // Cursor Guide (subtle)
-		i.hexToITermColor(t.Colors.Background),          // Cursor Text
-		i.hexToITermColor(t.Colors.Foreground),          // Foreground
-		i.hexToITermColor(t.Colors.BrightCyan),          // Link (bright cyan for visibility)
-		i.hexToITermColor(t.Colors.BrightBlack),         // Selected Text
-		i.hexToITermColor(t.Colors.Foreground),          // Selection
-	)
-}
+<dict>`)
 
-func (i *ITerm2Integration) hexToITermColor(hex string) string {
-	return i.hexToITermColorWithAlpha(hex, 1.0)
-}
+	for _, entry := range iTermColorEntries(t) {
+		color, err := i.hexToITermColorWithAlpha(entry.color, entry.alpha)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", entry.key, err)
+		}
+		preset.WriteString(fmt.Sprintf("\n\t<key>%s</key>%s", entry.key, color))
+	}
 
-func (i *ITerm2Integration) hexToITermColorWithAlpha(hex string, alpha float64) string {
-	// Remove # if present
-	hex = strings.TrimPrefix(hex, "#")
+	preset.WriteString("\n</dict>\n</plist>")
+	return preset.String(), nil
+}
 
-	// Parse hex to RGB
-	r, g, b := i.hexToRGB(hex)
+func (i *ITerm2Integration) hexToITermColorWithAlpha(hex string, alpha float64) (string, error) {
+	c, err := theme.ParseColor(hex)
+	if err != nil {
+		return "", err
+	}
 
 	// Convert to iTerm2 format (0.0 - 1.0)
-	rf := float64(r) / 255.0
-	gf := float64(g) / 255.0
-	bf := float64(b) / 255.0
+	rf := float64(c.R) / 255.0
+	gf := float64(c.G) / 255.0
+	bf := float64(c.B) / 255.0
 
 	return fmt.Sprintf(`
 	<dict>
@@ -180,13 +199,7 @@ func (i *ITerm2Integration) hexToITermColorWithAlpha(hex string, alpha float64)
 		<real>%.6f</real>
 		<key>Red Component</key>
 		<real>%.6f</real>
-	</dict>`, alpha, bf, gf, rf)
-}
-
-func (i *ITerm2Integration) hexToRGB(hex string) (uint8, uint8, uint8) {
-	var r, g, b uint8
-	fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b)
-	return r, g, b
+	</dict>`, alpha, bf, gf, rf), nil
 }
 
 func (i *ITerm2Integration) importTheme(presetPath string) error {
@@ -194,12 +207,11 @@ func (i *ITerm2Integration) importTheme(presetPath string) error {
 	// This method is based on the official iTerm2-Color-Schemes import script
 	// Reference: https://github.com/mbadolato/iTerm2-Color-Schemes/blob/master/tools/import-scheme.sh
 
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+	if i.home == "" {
+		return fmt.Errorf("failed to get home directory")
 	}
 
-	plistPath := filepath.Join(home, "Library", "Preferences", "com.googlecode.iterm2.plist")
+	plistPath := filepath.Join(i.home, "Library", "Preferences", "com.googlecode.iterm2.plist")
 
 	// Check if iTerm2 preferences file exists
 	if _, err := os.Stat(plistPath); os.IsNotExist(err) {
@@ -5,12 +5,14 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
-	"zakaranda/internal/theme"
 
 	"github.com/atotto/clipboard"
+	"github.com/dahromy/zakaranda/internal/theme"
 )
 
-type SlackIntegration struct{}
+type SlackIntegration struct {
+	sideEffects
+}
 
 func NewSlackIntegration() *SlackIntegration {
 	return &SlackIntegration{}
@@ -46,22 +48,25 @@ func (s *SlackIntegration) IsInstalled() bool {
 }
 
 func (s *SlackIntegration) Apply(t theme.Theme) error {
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+
 	// Generate Slack theme string (4 colors)
 	themeString := s.generateSlackTheme(t.Colors)
+	s.note("Theme colors: %s", themeString)
+	if !s.allowed("copying the colors and opening Slack") {
+		return nil
+	}
 
 	// Copy to clipboard
 	if err := clipboard.WriteAll(themeString); err != nil {
 		return fmt.Errorf("failed to copy theme to clipboard: %w\n\nTheme colors: %s", err, themeString)
 	}
 
-	// Display success message with instructions
-	fmt.Println("\n✓ Slack theme copied to clipboard!")
-	fmt.Println("\n📋 Theme colors:", themeString)
-	fmt.Println("\n🎨 To apply in Slack:")
-	fmt.Println("1. Open Slack")
-	fmt.Println("2. Go to Preferences → Appearance → Custom theme")
-	fmt.Println("3. Paste the theme colors (Cmd+V / Ctrl+V)")
-	fmt.Println("4. The theme will be applied automatically")
+	// Instructions for the manual part
+	s.note("Copied to the clipboard: in Slack, paste them in Preferences → Appearance → Custom theme")
 
 	// Try to open Slack preferences (optional, may not work on all systems)
 	s.tryOpenSlackPreferences()
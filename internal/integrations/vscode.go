@@ -8,20 +8,25 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
-	"zakaranda/internal/theme"
+
+	"github.com/dahromy/zakaranda/internal/theme"
 )
 
 type VSCodeIntegration struct {
+	sideEffects
+	home       string
 	configPath string
 	variant    VSCodeVariant
+	variantSet bool // SetVariant was called
 }
 
 // VSCodeVariant represents different VS Code variants
 type VSCodeVariant struct {
-	Name       string
-	ConfigDir  string
-	CLICommand string
-	AppPath    string
+	Name          string
+	ConfigDir     string
+	CLICommand    string
+	AppPath       string
+	ExtensionsDir string // Where the variant installs extensions
 }
 
 var (
@@ -32,71 +37,10 @@ var (
 
 // VSCodeThemeExtension represents a VS Code theme extension
 type VSCodeThemeExtension struct {
-	ExtensionID      string
-	ThemeName        string
-	IconTheme        string
-	ProductIconTheme string
-}
-
-// Map theme names to VS Code extensions
-var vscodeThemeExtensions = map[string]VSCodeThemeExtension{
-	"Nord": {
-		ExtensionID:      "arcticicestudio.nord-visual-studio-code",
-		ThemeName:        "Nord",
-		IconTheme:        "charmed-light", // Requires: charmed-icons.charmed-icons
-		ProductIconTheme: "fluent-icons",  // Requires: miguelsolorio.fluent-icons
-	},
-	"Catppuccin Latte": {
-		ExtensionID:      "catppuccin.catppuccin-vsc",
-		ThemeName:        "Catppuccin Latte",
-		IconTheme:        "catppuccin-latte",
-		ProductIconTheme: "catppuccin-latte",
-	},
-	"Catppuccin Frappe": {
-		ExtensionID:      "catppuccin.catppuccin-vsc",
-		ThemeName:        "Catppuccin Frappé",
-		IconTheme:        "catppuccin-frappe",
-		ProductIconTheme: "catppuccin-frappe",
-	},
-	"Catppuccin Macchiato": {
-		ExtensionID:      "catppuccin.catppuccin-vsc",
-		ThemeName:        "Catppuccin Macchiato",
-		IconTheme:        "catppuccin-macchiato",
-		ProductIconTheme: "catppuccin-macchiato",
-	},
-	"Catppuccin Mocha": {
-		ExtensionID:      "catppuccin.catppuccin-vsc",
-		ThemeName:        "Catppuccin Mocha",
-		IconTheme:        "catppuccin-mocha",
-		ProductIconTheme: "catppuccin-mocha",
-	},
-	"Rose Pine": {
-		ExtensionID:      "mvllow.rose-pine",
-		ThemeName:        "Rosé Pine",
-		IconTheme:        "rose-pine-icons",
-		ProductIconTheme: "fluent-icons",
-	},
-	"Rose Pine Moon": {
-		ExtensionID:      "mvllow.rose-pine",
-		ThemeName:        "Rosé Pine Moon",
-		IconTheme:        "rose-pine-moon-icons",
-		ProductIconTheme: "fluent-icons",
-	},
-	"Rose Pine Dawn": {
-		ExtensionID:      "mvllow.rose-pine",
-		ThemeName:        "Rosé Pine Dawn",
-		IconTheme:        "rose-pine-dawn-icons",
-		ProductIconTheme: "fluent-icons",
-	},
-}
-
-// Additional extensions needed for icon themes
-var iconThemeExtensions = map[string]string{
-	"charmed-light":       "charmed-icons.charmed-icons",
-	"material-icon-theme": "pkief.material-icon-theme",
-	"fluent-icons":        "miguelsolorio.fluent-icons",
-	"catppuccin-mocha":    "catppuccin.catppuccin-vsc", // Included in main theme
-	"rose-pine-icons":     "mvllow.rose-pine",          // Included in main theme
+	ExtensionID      string `yaml:"extension_id"` // Empty for themes built into VS Code
+	ThemeName        string `yaml:"theme_name"`
+	IconTheme        string `yaml:"icon_theme"`
+	ProductIconTheme string `yaml:"product_icon_theme"`
 }
 
 // GetVSCodeVariants returns all available VS Code variants on the system
@@ -108,51 +52,81 @@ func GetVSCodeVariants() []VSCodeVariant {
 			vscodeVariantsCache = nil
 			return
 		}
+		vscodeVariantsCache = findVSCodeVariants(home)
+	})
 
-		variants := []VSCodeVariant{
-			{
-				Name:       "VS Code",
-				ConfigDir:  filepath.Join(home, "Library", "Application Support", "Code"),
-				CLICommand: "code",
-				AppPath:    "/Applications/Visual Studio Code.app",
-			},
-			{
-				Name:       "VS Code Insiders",
-				ConfigDir:  filepath.Join(home, "Library", "Application Support", "Code - Insiders"),
-				CLICommand: "code-insiders",
-				AppPath:    "/Applications/Visual Studio Code - Insiders.app",
-			},
-			{
-				Name:       "Cursor",
-				ConfigDir:  filepath.Join(home, "Library", "Application Support", "Cursor"),
-				CLICommand: "cursor",
-				AppPath:    "/Applications/Cursor.app",
-			},
-		}
+	return vscodeVariantsCache
+}
 
-		// Filter to only installed variants
-		// Pre-allocate slice with maximum possible size to avoid reallocations
-		installed := make([]VSCodeVariant, 0, len(variants))
-		for _, variant := range variants {
-			// Check if config directory exists OR app is installed
-			// Combine checks to reduce system calls
-			_, configErr := os.Stat(variant.ConfigDir)
-			_, appErr := os.Stat(variant.AppPath)
-
-			if configErr == nil || appErr == nil {
-				installed = append(installed, variant)
-			}
+// findVSCodeVariants returns the variants installed as an app or with a
+// configuration directory below home
+func findVSCodeVariants(home string) []VSCodeVariant {
+	if home == "" {
+		return nil
+	}
+
+	variants := []VSCodeVariant{
+		{
+			Name:          "VS Code",
+			ConfigDir:     filepath.Join(home, "Library", "Application Support", "Code"),
+			CLICommand:    "code",
+			AppPath:       "/Applications/Visual Studio Code.app",
+			ExtensionsDir: filepath.Join(home, ".vscode", "extensions"),
+		},
+		{
+			Name:          "VS Code Insiders",
+			ConfigDir:     filepath.Join(home, "Library", "Application Support", "Code - Insiders"),
+			CLICommand:    "code-insiders",
+			AppPath:       "/Applications/Visual Studio Code - Insiders.app",
+			ExtensionsDir: filepath.Join(home, ".vscode-insiders", "extensions"),
+		},
+		{
+			Name:          "Cursor",
+			ConfigDir:     filepath.Join(home, "Library", "Application Support", "Cursor"),
+			CLICommand:    "cursor",
+			AppPath:       "/Applications/Cursor.app",
+			ExtensionsDir: filepath.Join(home, ".cursor", "extensions"),
+		},
+	}
+
+	// Filter to only installed variants
+	// Pre-allocate slice with maximum possible size to avoid reallocations
+	installed := make([]VSCodeVariant, 0, len(variants))
+	for _, variant := range variants {
+		if variant.IsInstalled() {
+			installed = append(installed, variant)
 		}
+	}
 
-		vscodeVariantsCache = installed
-	})
+	return installed
+}
 
-	return vscodeVariantsCache
+// VSCodeExtensionDirs returns the existing extension directories of the
+// installed variants and of VS Code itself, which may be installed without
+// the app bundle (e.g. on Linux)
+func VSCodeExtensionDirs(home string) []string {
+	candidates := []string{filepath.Join(home, ".vscode", "extensions")}
+	for _, variant := range findVSCodeVariants(home) {
+		candidates = append(candidates, variant.ExtensionsDir)
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, dir := range candidates {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
 }
 
-// stripJSONComments removes single-line (//) and multi-line (/* */) comments from JSON
+// StripJSONComments removes single-line (//) and multi-line (/* */) comments from JSON
 // while preserving strings that might contain // or /* */
-func stripJSONComments(jsonStr string) string {
+func StripJSONComments(jsonStr string) string {
 	var result strings.Builder
 	// Pre-allocate buffer size to avoid reallocations
 	result.Grow(len(jsonStr))
@@ -225,27 +199,38 @@ func stripJSONComments(jsonStr string) string {
 }
 
 func NewVSCodeIntegration() *VSCodeIntegration {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	home, _ := os.UserHomeDir()
+	return newVSCodeIntegration(home)
+}
+
+// newVSCodeIntegration returns the integration with its configuration below home
+func newVSCodeIntegration(home string) *VSCodeIntegration {
+	if home == "" {
 		return &VSCodeIntegration{configPath: ""}
 	}
 
 	// Default to standard VS Code
 	defaultVariant := VSCodeVariant{
-		Name:       "VS Code",
-		ConfigDir:  filepath.Join(home, "Library", "Application Support", "Code"),
-		CLICommand: "code",
-		AppPath:    "/Applications/Visual Studio Code.app",
+		Name:          "VS Code",
+		ConfigDir:     filepath.Join(home, "Library", "Application Support", "Code"),
+		CLICommand:    "code",
+		AppPath:       "/Applications/Visual Studio Code.app",
+		ExtensionsDir: filepath.Join(home, ".vscode", "extensions"),
 	}
 
 	configPath := filepath.Join(defaultVariant.ConfigDir, "User", "settings.json")
 	return &VSCodeIntegration{
+		home:       home,
 		configPath: configPath,
 		variant:    defaultVariant,
 	}
 }
 
+// Name returns the name of the selected variant ("VS Code" unless SetVariant was called)
 func (v *VSCodeIntegration) Name() string {
+	if v.variant.Name != "" {
+		return v.variant.Name
+	}
 	return "VS Code"
 }
 
@@ -255,60 +240,69 @@ func (v *VSCodeIntegration) ConfigPath() string {
 }
 
 func (v *VSCodeIntegration) IsInstalled() bool {
+	if v.variantSet {
+		return v.variant.IsInstalled()
+	}
 	// Check if any VS Code variant is installed
-	variants := GetVSCodeVariants()
-	return len(variants) > 0
+	return len(v.Variants()) > 0
+}
+
+// Variants returns the installed VS Code variants configured below the integration's home
+func (v *VSCodeIntegration) Variants() []VSCodeVariant {
+	if home, err := os.UserHomeDir(); err == nil && home == v.home {
+		return GetVSCodeVariants()
+	}
+	return findVSCodeVariants(v.home)
 }
 
 // SetVariant updates the VS Code variant and config path
 func (v *VSCodeIntegration) SetVariant(variant VSCodeVariant) {
 	v.variant = variant
+	v.variantSet = true
 	v.configPath = filepath.Join(variant.ConfigDir, "User", "settings.json")
 }
 
+// WithVariant returns a copy of the integration that applies themes to variant
+func (v *VSCodeIntegration) WithVariant(variant VSCodeVariant) *VSCodeIntegration {
+	cloned := *v
+	cloned.SetVariant(variant)
+	return &cloned
+}
+
+// IsInstalled reports whether the variant's app or configuration directory exists
+func (variant VSCodeVariant) IsInstalled() bool {
+	_, configErr := os.Stat(variant.ConfigDir)
+	_, appErr := os.Stat(variant.AppPath)
+	return configErr == nil || appErr == nil
+}
+
 func (v *VSCodeIntegration) Apply(t theme.Theme) error {
-	// Check if theme has official VS Code extension
-	themeExt, hasExtension := vscodeThemeExtensions[t.Name]
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+
+	// Check if theme has official VS Code extension (themes extending a
+	// built-in only use it when none of the colors were overridden)
+	themeExt, hasExtension := vscodeExtensionFor(t)
 
 	// Install extensions if available
 	if hasExtension {
-		if err := v.installExtensions(themeExt); err != nil {
-			// Don't fail if extension installation fails, just log and continue
-			fmt.Printf("Warning: Failed to install extensions: %v\n", err)
-		}
+		v.ensureExtensions(themeExt)
 	}
 
-	// Read existing settings
-	var settings map[string]interface{}
-
-	data, err := os.ReadFile(v.configPath)
+	settings, err := v.readSettings()
 	if err != nil {
-		if os.IsNotExist(err) {
-			settings = make(map[string]interface{})
-		} else {
-			return fmt.Errorf("failed to read settings: %w", err)
-		}
-	} else {
-		// Strip comments from JSON (VS Code allows comments in settings.json)
-		cleanedData := stripJSONComments(string(data))
-		if err := json.Unmarshal([]byte(cleanedData), &settings); err != nil {
-			return fmt.Errorf("failed to parse settings: %w", err)
-		}
+		return err
 	}
-
-	// Create backup
-	if len(data) > 0 {
-		backupPath := v.configPath + ".backup"
-		if err := os.WriteFile(backupPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to create backup: %w", err)
-		}
+	if err := v.clearPalette(settings); err != nil {
+		return err
 	}
 
 	// If official extension exists, set theme preferences
 	if hasExtension {
 		settings["workbench.colorTheme"] = themeExt.ThemeName
-		settings["workbench.iconTheme"] = themeExt.IconTheme
-		settings["workbench.productIconTheme"] = themeExt.ProductIconTheme
+		setIconThemes(settings, themeExt)
 		// Don't clear color customizations - user may have custom overrides
 		// Only remove if it's empty or doesn't exist
 		if existingCustomizations, ok := settings["workbench.colorCustomizations"].(map[string]interface{}); ok {
@@ -318,35 +312,302 @@ func (v *VSCodeIntegration) Apply(t theme.Theme) error {
 			// Otherwise keep user's custom color overrides
 		}
 	} else {
-		// Fallback to custom color customizations
-		themeColors := v.generateVSCodeColors(t)
-		terminalColors := v.generateTerminalColors(t)
+		// Fallback to custom color customizations, recorded so the next
+		// theme replaces them
+		customizations, ok := settings["workbench.colorCustomizations"].(map[string]interface{})
+		if !ok {
+			customizations = make(map[string]interface{})
+		}
+		record := vscodePalette{Colors: make(map[string]interface{})}
+		for k, color := range v.generatePaletteColors(t) {
+			// User customizations override theme colors
+			if _, ok := customizations[k]; ok {
+				continue
+			}
+			customizations[k] = color
+			record.Colors[k] = color
+		}
+		settings["workbench.colorCustomizations"] = customizations
+		return v.writeSettingsWithPalette(settings, record)
+	}
+
+	return v.writeSettingsClearingPalette(settings)
+}
 
-		// Pre-allocate map with total capacity to avoid reallocations
-		colors := make(map[string]interface{}, len(themeColors)+len(terminalColors))
+// ApplyPalette sets the theme's colors as color customizations scoped to the
+// active color theme, replacing the previous palette's, and records them so
+// the next Apply or ApplyPair removes them again. The user's customizations
+// of the same keys in that scope are put back then; others are left alone.
+func (v *VSCodeIntegration) ApplyPalette(t theme.Theme) error {
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
 
-		// Add theme colors
-		for k, v := range themeColors {
-			colors[k] = v
+	settings, _, err := v.loadSettings()
+	if err != nil {
+		return err
+	}
+	if err := v.clearPalette(settings); err != nil {
+		return err
+	}
+	customizations, ok := settings["workbench.colorCustomizations"].(map[string]interface{})
+	if !ok {
+		customizations = make(map[string]interface{})
+	}
+
+	activeTheme, _ := settings["workbench.colorTheme"].(string)
+	if activeTheme == "" {
+		activeTheme = vscodeDefaultDarkTheme // VS Code's own default
+	}
+	record := vscodePalette{
+		Scope:    "[" + activeTheme + "]",
+		Colors:   v.generatePaletteColors(t),
+		Replaced: make(map[string]interface{}),
+	}
+	scoped, ok := customizations[record.Scope].(map[string]interface{})
+	if !ok {
+		scoped = make(map[string]interface{})
+	}
+	for k, color := range record.Colors {
+		if existing, ok := scoped[k]; ok {
+			record.Replaced[k] = existing
 		}
+		scoped[k] = color
+	}
+	customizations[record.Scope] = scoped
+	settings["workbench.colorCustomizations"] = customizations
+
+	return v.writeSettingsWithPalette(settings, record)
+}
+
+// vscodePalette records the colors Apply or ApplyPalette generated from a
+// palette and the user's colors they replaced
+type vscodePalette struct {
+	Scope    string                 `json:"scope"` // The "[theme]" key of the customizations, or empty for the top level
+	Colors   map[string]interface{} `json:"colors"`
+	Replaced map[string]interface{} `json:"replaced,omitempty"`
+}
+
+// palettePath returns the file recording the last palette, next to settings.json
+func (v *VSCodeIntegration) palettePath() string {
+	return v.configPath + ".palette"
+}
+
+// clearPalette removes the colors recorded by the last palette from the
+// settings and puts back the ones they replaced. Colors changed since are the
+// user's and kept.
+func (v *VSCodeIntegration) clearPalette(settings map[string]interface{}) error {
+	data, err := os.ReadFile(v.palettePath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read palette record: %w", err)
+	}
+	var record vscodePalette
+	if err := json.Unmarshal(data, &record); err != nil {
+		return fmt.Errorf("failed to parse palette record %s: %w", v.palettePath(), err)
+	}
 
-		// Add terminal colors
-		for k, v := range terminalColors {
-			colors[k] = v
+	customizations, _ := settings["workbench.colorCustomizations"].(map[string]interface{})
+	scoped := customizations
+	if record.Scope != "" {
+		scoped, _ = customizations[record.Scope].(map[string]interface{})
+	}
+	if scoped == nil {
+		return nil
+	}
+	for k, color := range record.Colors {
+		if scoped[k] != color {
+			continue
 		}
+		if replaced, ok := record.Replaced[k]; ok {
+			scoped[k] = replaced
+		} else {
+			delete(scoped, k)
+		}
+	}
+	if len(scoped) == 0 && record.Scope != "" {
+		delete(customizations, record.Scope)
+	}
+	if len(customizations) == 0 {
+		delete(settings, "workbench.colorCustomizations")
+	}
+	return nil
+}
 
-		// Preserve existing customizations and merge with theme colors
-		// User customizations override theme colors
-		if existingCustomizations, ok := settings["workbench.colorCustomizations"].(map[string]interface{}); ok {
-			for k, v := range existingCustomizations {
+// writeSettingsWithPalette writes the settings and records the palette
+// colors they now hold
+func (v *VSCodeIntegration) writeSettingsWithPalette(settings map[string]interface{}, record vscodePalette) error {
+	if err := v.writeSettings(settings); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal palette record: %w", err)
+	}
+	if err := os.WriteFile(v.palettePath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to record palette: %w", err)
+	}
+	return nil
+}
+
+// writeSettingsClearingPalette writes the settings, which no longer hold the
+// last palette's colors, and removes the palette record
+func (v *VSCodeIntegration) writeSettingsClearingPalette(settings map[string]interface{}) error {
+	if err := v.writeSettings(settings); err != nil {
+		return err
+	}
+	if err := os.Remove(v.palettePath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove palette record: %w", err)
+	}
+	return nil
+}
+
+// Built-in VS Code themes used as the base for palette customizations in a pair
+const (
+	vscodeDefaultLightTheme = "Default Light Modern"
+	vscodeDefaultDarkTheme  = "Default Dark Modern"
+)
+
+// ApplyPair sets separate light and dark themes and lets VS Code switch
+// between them following the system appearance (window.autoDetectColorScheme)
+func (v *VSCodeIntegration) ApplyPair(pair theme.Pair) error {
+	light, err := prepareTheme(pair.Light)
+	if err != nil {
+		return err
+	}
+	dark, err := prepareTheme(pair.Dark)
+	if err != nil {
+		return err
+	}
+
+	settings, err := v.readSettings()
+	if err != nil {
+		return err
+	}
+	if err := v.clearPalette(settings); err != nil {
+		return err
+	}
+
+	lightName := v.pairColorTheme(light, vscodeDefaultLightTheme, settings)
+	darkName := v.pairColorTheme(dark, vscodeDefaultDarkTheme, settings)
+
+	settings["window.autoDetectColorScheme"] = true
+	settings["workbench.preferredLightColorTheme"] = lightName
+	settings["workbench.preferredDarkColorTheme"] = darkName
+	// Used when auto detection is off or unsupported
+	settings["workbench.colorTheme"] = darkName
+
+	// Icon themes can't follow the appearance; prefer the dark theme's
+	if themeExt, ok := vscodeExtensionFor(dark); ok && themeExt.IconTheme != "" {
+		setIconThemes(settings, themeExt)
+	} else if themeExt, ok := vscodeExtensionFor(light); ok {
+		setIconThemes(settings, themeExt)
+	}
+
+	return v.writeSettingsClearingPalette(settings)
+}
+
+// pairColorTheme returns the color theme name for one side of a pair.
+// Themes with an official extension use it; other themes use the given
+// built-in theme with the palette as customizations scoped to that theme,
+// replacing the previous palette's.
+func (v *VSCodeIntegration) pairColorTheme(t theme.Theme, fallback string, settings map[string]interface{}) string {
+	if themeExt, ok := vscodeExtensionFor(t); ok {
+		v.ensureExtensions(themeExt)
+		return themeExt.ThemeName
+	}
+
+	customizations, ok := settings["workbench.colorCustomizations"].(map[string]interface{})
+	if !ok {
+		customizations = make(map[string]interface{})
+	}
+
+	// Keep user overrides already scoped to this theme, except of the keys
+	// the palette sets, which an earlier pair may have written
+	colors := v.generatePaletteColors(t)
+	if existing, ok := customizations["["+fallback+"]"].(map[string]interface{}); ok {
+		for k, v := range existing {
+			if _, generated := colors[k]; !generated {
 				colors[k] = v
 			}
 		}
+	}
+	customizations["["+fallback+"]"] = colors
+	settings["workbench.colorCustomizations"] = customizations
+
+	return fallback
+}
+
+// vscodeExtensionFor returns the extension providing a theme: the official
+// one of a built-in theme or the one an imported theme was converted from
+func vscodeExtensionFor(t theme.Theme) (VSCodeThemeExtension, bool) {
+	if themeExt, ok := vscodeThemeExtensions[t.OfficialName()]; ok {
+		return themeExt, true
+	}
+	if t.Source != nil && t.Source.App == "vscode" && len(t.Overrides) == 0 {
+		return VSCodeThemeExtension{ExtensionID: t.Source.ExtensionID, ThemeName: t.Source.Theme}, true
+	}
+	return VSCodeThemeExtension{}, false
+}
+
+// setIconThemes sets the extension's icon themes. Imported themes have none,
+// so the user's icon themes are kept.
+func setIconThemes(settings map[string]interface{}, themeExt VSCodeThemeExtension) {
+	if themeExt.IconTheme != "" {
+		settings["workbench.iconTheme"] = themeExt.IconTheme
+	}
+	if themeExt.ProductIconTheme != "" {
+		settings["workbench.productIconTheme"] = themeExt.ProductIconTheme
+	}
+}
+
+// readSettings reads settings.json (which may contain comments) and backs it up
+func (v *VSCodeIntegration) readSettings() (map[string]interface{}, error) {
+	settings, data, err := v.loadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create backup
+	if len(data) > 0 {
+		backupPath := v.configPath + ".backup"
+		if err := os.WriteFile(backupPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to create backup: %w", err)
+		}
+	}
 
-		settings["workbench.colorCustomizations"] = colors
+	return settings, nil
+}
+
+// loadSettings parses settings.json and returns it with the file's contents
+func (v *VSCodeIntegration) loadSettings() (map[string]interface{}, []byte, error) {
+	var settings map[string]interface{}
+
+	data, err := os.ReadFile(v.configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]interface{}), nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	// Strip comments from JSON (VS Code allows comments in settings.json)
+	cleanedData := StripJSONComments(string(data))
+	if err := json.Unmarshal([]byte(cleanedData), &settings); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	if settings == nil {
+		settings = make(map[string]interface{})
 	}
 
-	// Write updated settings
+	return settings, data, nil
+}
+
+// writeSettings writes the updated settings.json
+func (v *VSCodeIntegration) writeSettings(settings map[string]interface{}) error {
 	newData, err := json.MarshalIndent(settings, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal settings: %w", err)
@@ -359,6 +620,21 @@ func (v *VSCodeIntegration) Apply(t theme.Theme) error {
 	return nil
 }
 
+// ensureExtensions installs the extension's theme and icon themes unless
+// sandboxed. Failures are noted rather than failing the apply, so the
+// settings still name the theme for when it is installed by hand.
+func (v *VSCodeIntegration) ensureExtensions(themeExt VSCodeThemeExtension) {
+	if themeExt.ExtensionID == "" && themeExt.IconTheme == "" && themeExt.ProductIconTheme == "" {
+		return // Built into VS Code
+	}
+	if !v.allowed("installing " + themeExt.ThemeName + "'s extensions") {
+		return
+	}
+	if err := v.installExtensions(themeExt); err != nil {
+		v.note("Couldn't install extensions: %v", err)
+	}
+}
+
 func (v *VSCodeIntegration) installExtensions(themeExt VSCodeThemeExtension) error {
 	// Try to find VS Code CLI first
 	codeCmd := v.findVSCodeCLI()
@@ -372,8 +648,11 @@ func (v *VSCodeIntegration) installExtensions(themeExt VSCodeThemeExtension) err
 		return fmt.Errorf("failed to get installed extensions: %w", err)
 	}
 
-	// Collect all extensions to install
-	extensionsToInstall := []string{themeExt.ExtensionID}
+	// Collect all extensions to install (themes built into VS Code have no extension)
+	var extensionsToInstall []string
+	if themeExt.ExtensionID != "" {
+		extensionsToInstall = append(extensionsToInstall, themeExt.ExtensionID)
+	}
 
 	// Add icon theme extension if needed
 	if iconExt, exists := iconThemeExtensions[themeExt.IconTheme]; exists {
@@ -429,12 +708,9 @@ func (v *VSCodeIntegration) getInstalledExtensions(codeCmd string) (map[string]b
 func (v *VSCodeIntegration) installExtensionWithCache(codeCmd, extensionID string, installedExts map[string]bool) error {
 	// Check if extension is already installed using the cached map
 	if installedExts[strings.ToLower(extensionID)] {
-		fmt.Printf("Extension %s is already installed\n", extensionID)
 		return nil
 	}
 
-	fmt.Printf("Installing VS Code extension: %s...\n", extensionID)
-
 	// Install the extension
 	cmd := exec.Command(codeCmd, "--install-extension", extensionID, "--force")
 	output, err := cmd.CombinedOutput()
@@ -442,7 +718,7 @@ func (v *VSCodeIntegration) installExtensionWithCache(codeCmd, extensionID strin
 		return fmt.Errorf("failed to install extension: %w\nOutput: %s", err, string(output))
 	}
 
-	fmt.Printf("Successfully installed %s\n", extensionID)
+	v.note("Installed extension %s", extensionID)
 	return nil
 }
 
@@ -483,6 +759,27 @@ func (v *VSCodeIntegration) findVSCodeCLI() string {
 	return ""
 }
 
+// generatePaletteColors returns workbench and terminal colors generated from the palette
+func (v *VSCodeIntegration) generatePaletteColors(t theme.Theme) map[string]interface{} {
+	themeColors := v.generateVSCodeColors(t)
+	terminalColors := v.generateTerminalColors(t)
+
+	// Pre-allocate map with total capacity to avoid reallocations
+	colors := make(map[string]interface{}, len(themeColors)+len(terminalColors))
+
+	// Add theme colors
+	for k, v := range themeColors {
+		colors[k] = v
+	}
+
+	// Add terminal colors
+	for k, v := range terminalColors {
+		colors[k] = v
+	}
+
+	return colors
+}
+
 func (v *VSCodeIntegration) generateVSCodeColors(t theme.Theme) map[string]string {
 	return map[string]string{
 		// Editor
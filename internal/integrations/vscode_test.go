@@ -1,8 +1,13 @@
 package integrations
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
 )
 
 // TestGetVSCodeVariantsCaching verifies that VS Code variants are cached
@@ -88,6 +93,250 @@ func BenchmarkStripJSONComments(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		stripJSONComments(testJSON)
+		StripJSONComments(testJSON)
+	}
+}
+
+// TestVSCodeApplyPair verifies that a pair enables automatic switching and
+// scopes palette customizations to the built-in light and dark themes
+func TestVSCodeApplyPair(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "settings.json")
+	existing := `{
+  // user settings
+  "editor.fontSize": 14,
+  "workbench.colorCustomizations": {"[Default Dark Modern]": {"editor.background": "#000000", "statusBar.border": "#ff0000"}}
+}`
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	palette := theme.ColorPalette{
+		Background: "#fafafa", Foreground: "#202020",
+		Black: "#000000", Red: "#cc0000", Green: "#00cc00", Yellow: "#cccc00",
+		Blue: "#0000cc", Magenta: "#cc00cc", Cyan: "#00cccc", White: "#cccccc",
+		BrightBlack: "#555555", BrightRed: "#ff0000", BrightGreen: "#00ff00", BrightYellow: "#ffff00",
+		BrightBlue: "#0000ff", BrightMagenta: "#ff00ff", BrightCyan: "#00ffff", BrightWhite: "#ffffff",
+	}
+	light := theme.Theme{Name: "Paper", Colors: palette}
+	palette.Background, palette.Foreground = "#101010", "#e0e0e0"
+	dark := theme.Theme{Name: "Ink", Colors: palette}
+
+	pair, err := theme.NewPair(dark, light)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&VSCodeIntegration{configPath: configPath}).ApplyPair(pair); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+
+	if settings["window.autoDetectColorScheme"] != true {
+		t.Error("Expected window.autoDetectColorScheme to be enabled")
+	}
+	if settings["workbench.preferredLightColorTheme"] != "Default Light Modern" {
+		t.Errorf("Unexpected light theme: %v", settings["workbench.preferredLightColorTheme"])
+	}
+	if settings["workbench.preferredDarkColorTheme"] != "Default Dark Modern" {
+		t.Errorf("Unexpected dark theme: %v", settings["workbench.preferredDarkColorTheme"])
+	}
+	if settings["editor.fontSize"] != float64(14) {
+		t.Error("Expected unrelated settings to be preserved")
+	}
+
+	customizations, _ := settings["workbench.colorCustomizations"].(map[string]interface{})
+	darkColors, _ := customizations["[Default Dark Modern]"].(map[string]interface{})
+	lightColors, _ := customizations["[Default Light Modern]"].(map[string]interface{})
+	if darkColors["editor.background"] != "#101010" {
+		t.Errorf("Expected dark palette background, got %v", darkColors["editor.background"])
+	}
+	if darkColors["statusBar.border"] != "#ff0000" {
+		t.Errorf("Expected user override to be kept, got %v", darkColors["statusBar.border"])
+	}
+	if lightColors["editor.background"] != "#fafafa" {
+		t.Errorf("Expected light palette background, got %v", lightColors["editor.background"])
+	}
+}
+
+// TestVSCodeApplyPairTwice verifies that a second custom pair replaces the
+// colors of the first
+func TestVSCodeApplyPairTwice(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "settings.json")
+	vscode := &VSCodeIntegration{configPath: configPath}
+	for _, backgrounds := range [][2]string{{"#fafafa", "#101010"}, {"#f0e0d0", "#201008"}} {
+		light, dark := theme.GetBuiltInThemes()[0], theme.GetBuiltInThemes()[0]
+		light.Name, light.Base, light.Colors.Background = "Paper "+backgrounds[0], "", backgrounds[0]
+		dark.Name, dark.Base, dark.Colors.Background = "Ink "+backgrounds[1], "", backgrounds[1]
+		if err := vscode.ApplyPair(theme.Pair{Light: light, Dark: dark}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	customizations, _ := settings["workbench.colorCustomizations"].(map[string]interface{})
+	lightColors, _ := customizations["[Default Light Modern]"].(map[string]interface{})
+	darkColors, _ := customizations["[Default Dark Modern]"].(map[string]interface{})
+	if lightColors["editor.background"] != "#f0e0d0" || darkColors["editor.background"] != "#201008" {
+		t.Errorf("Expected the second pair's backgrounds, got %v and %v", lightColors["editor.background"], darkColors["editor.background"])
+	}
+}
+
+// TestVSCodeApplyTwice verifies that a second theme without an extension
+// replaces the colors of the first, that the user's customizations win over
+// both and that applying a theme with an extension removes the colors
+func TestVSCodeApplyTwice(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "settings.json")
+	existing := `{"workbench.colorCustomizations": {"editor.foreground": "#ffffff", "statusBar.border": "#ff0000"}}`
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	readCustomizations := func() map[string]interface{} {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var settings map[string]interface{}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			t.Fatal(err)
+		}
+		customizations, _ := settings["workbench.colorCustomizations"].(map[string]interface{})
+		return customizations
+	}
+
+	vscode := &VSCodeIntegration{configPath: configPath}
+	vscode.sandbox() // Don't install extensions
+	for _, background := range []string{"#101010", "#202020"} {
+		custom := theme.GetBuiltInThemes()[0]
+		custom.Name, custom.Base, custom.Colors.Background = "Dusk "+background, "", background
+		if err := vscode.Apply(custom); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	customizations := readCustomizations()
+	if customizations["editor.background"] != "#202020" {
+		t.Errorf("Expected the second theme's background, got %v", customizations["editor.background"])
+	}
+	if customizations["editor.foreground"] != "#ffffff" || customizations["statusBar.border"] != "#ff0000" {
+		t.Errorf("Expected the user's customizations to be kept, got %v", customizations)
+	}
+
+	dracula, _ := theme.DefaultRegistry().Lookup("Dracula")
+	if err := vscode.Apply(dracula); err != nil {
+		t.Fatal(err)
+	}
+	if customizations := readCustomizations(); len(customizations) != 2 {
+		t.Errorf("Expected only the user's customizations after applying Dracula, got %v", customizations)
+	}
+}
+
+// TestVSCodeApplyImportedTheme verifies that a theme imported from an
+// extension selects that extension's theme instead of customizing colors,
+// keeping the user's icon theme
+func TestVSCodeApplyImportedTheme(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(configPath, []byte(`{"workbench.iconTheme": "material-icon-theme"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := theme.GetBuiltInThemes()[0]
+	imported.Name = "Harbor Night"
+	imported.Source = &theme.ThemeSource{App: "vscode", ExtensionID: "acme.harbor", Theme: "Harbor Night"}
+	if err := (&VSCodeIntegration{configPath: configPath}).Apply(imported); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings["workbench.colorTheme"] != "Harbor Night" {
+		t.Errorf("Expected the extension's theme, got %v", settings["workbench.colorTheme"])
+	}
+	if settings["workbench.iconTheme"] != "material-icon-theme" {
+		t.Errorf("Expected the icon theme to be kept, got %v", settings["workbench.iconTheme"])
+	}
+	if _, ok := settings["workbench.colorCustomizations"]; ok {
+		t.Error("Expected no color customizations")
+	}
+}
+
+// TestVSCodeApplyPalette verifies that each palette replaces the previous
+// one's colors, scoped to the active theme and leaving the user's
+// customizations alone, and that applying a theme afterwards removes them
+func TestVSCodeApplyPalette(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "settings.json")
+	existing := `{"workbench.colorTheme": "Dracula", "workbench.colorCustomizations": {
+  "editor.background": "#000000",
+  "[Dracula]": {"editor.background": "#111111", "statusBar.border": "#ff0000"}
+}}`
+	if err := os.WriteFile(configPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	readCustomizations := func() map[string]interface{} {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var settings map[string]interface{}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			t.Fatal(err)
+		}
+		customizations, _ := settings["workbench.colorCustomizations"].(map[string]interface{})
+		return customizations
+	}
+
+	vscode := &VSCodeIntegration{configPath: configPath}
+	vscode.sandbox() // Don't install extensions
+	for _, background := range []string{"#101010", "#202020"} {
+		palette := theme.GetBuiltInThemes()[0]
+		palette.Name = "Dusk"
+		palette.Colors.Background = background
+		if err := vscode.ApplyPalette(palette); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	customizations := readCustomizations()
+	scoped, _ := customizations["[Dracula]"].(map[string]interface{})
+	if scoped["editor.background"] != "#202020" {
+		t.Errorf("Expected the last palette's background, got %v", scoped["editor.background"])
+	}
+	if scoped["statusBar.border"] != "#ff0000" || customizations["editor.background"] != "#000000" {
+		t.Errorf("Expected the user's customizations to be kept, got %v", customizations)
+	}
+	if _, err := os.Stat(configPath + ".backup"); err == nil {
+		t.Error("Expected no backup")
+	}
+
+	dracula, _ := theme.DefaultRegistry().Lookup("Dracula")
+	if err := vscode.Apply(dracula); err != nil {
+		t.Fatal(err)
+	}
+	customizations = readCustomizations()
+	scoped, _ = customizations["[Dracula]"].(map[string]interface{})
+	if len(scoped) != 2 || scoped["editor.background"] != "#111111" || customizations["editor.background"] != "#000000" {
+		t.Errorf("Expected only the user's customizations after applying Dracula, got %v", customizations)
+	}
+	if _, err := os.Stat(vscode.palettePath()); err == nil {
+		t.Error("Expected the palette record to be removed")
 	}
 }
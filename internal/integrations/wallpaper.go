@@ -2,22 +2,34 @@ package integrations
 
 import (
 	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
-	"zakaranda/internal/theme"
+
+	"github.com/dahromy/zakaranda/internal/theme"
 )
 
+// defaultWallpaper is used for themes without a wallpaper of their own
+const defaultWallpaper = "catppuccin-rosepine.jpg"
+
 type WallpaperIntegration struct {
+	sideEffects
 	wallpaperPath string
 	assetsPath    string
 }
 
 func NewWallpaperIntegration() *WallpaperIntegration {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	home, _ := os.UserHomeDir()
+	return newWallpaperIntegration(home)
+}
+
+// newWallpaperIntegration returns the integration with its configuration below home
+func newWallpaperIntegration(home string) *WallpaperIntegration {
+	if home == "" {
 		return &WallpaperIntegration{wallpaperPath: "", assetsPath: ""}
 	}
 	wallpaperPath := filepath.Join(home, ".config", "zakaranda", "wallpapers")
@@ -71,21 +83,52 @@ func (w *WallpaperIntegration) IsInstalled() bool {
 }
 
 func (w *WallpaperIntegration) Apply(t theme.Theme) error {
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+
 	// Create wallpapers directory if it doesn't exist
 	if err := os.MkdirAll(w.wallpaperPath, 0755); err != nil {
 		return fmt.Errorf("failed to create wallpapers directory: %w", err)
 	}
 
-	// Map theme name to wallpaper file
-	sourceWallpaper := w.getWallpaperForTheme(t.Name)
-	if sourceWallpaper == "" {
-		return fmt.Errorf("no wallpaper found for theme: %s", t.Name)
+	// A theme's own image, e.g. the one it was generated from, comes first
+	if _, err := os.Stat(t.Wallpaper); t.Wallpaper != "" && err == nil {
+		destWallpaper := filepath.Join(w.wallpaperPath, theme.SanitizeFileName(t.Name)+filepath.Ext(t.Wallpaper))
+		if err := w.copyFile(t.Wallpaper, destWallpaper); err != nil {
+			return fmt.Errorf("failed to copy wallpaper: %w", err)
+		}
+		if err := w.setWallpaper(destWallpaper); err != nil {
+			return fmt.Errorf("failed to set wallpaper: %w", err)
+		}
+		return nil
 	}
 
-	// Copy wallpaper to user's config directory
-	destWallpaper := filepath.Join(w.wallpaperPath, filepath.Base(sourceWallpaper))
-	if err := w.copyFile(sourceWallpaper, destWallpaper); err != nil {
-		return fmt.Errorf("failed to copy wallpaper: %w", err)
+	// Map theme name to wallpaper file (themes extending a built-in share its wallpaper)
+	wallpaperTheme := t.Name
+	if t.Base != "" {
+		wallpaperTheme = t.Base
+	}
+	wallpaperFile, hasWallpaper := wallpaperThemeMap[wallpaperTheme]
+	if !hasWallpaper {
+		// Default to catppuccin-rosepine for unknown themes
+		wallpaperFile = defaultWallpaper
+	}
+	destWallpaper := filepath.Join(w.wallpaperPath, wallpaperFile)
+
+	if sourceWallpaper := w.getWallpaperForTheme(wallpaperFile); sourceWallpaper != "" {
+		// Copy wallpaper to user's config directory
+		if err := w.copyFile(sourceWallpaper, destWallpaper); err != nil {
+			return fmt.Errorf("failed to copy wallpaper: %w", err)
+		}
+	} else if hasWallpaper {
+		// Families without artwork get a wallpaper drawn from the palette
+		if err := generateWallpaper(t.Colors, destWallpaper); err != nil {
+			return fmt.Errorf("failed to generate wallpaper: %w", err)
+		}
+	} else {
+		return fmt.Errorf("no wallpaper found for theme: %s", t.Name)
 	}
 
 	// Set as desktop wallpaper using AppleScript
@@ -96,23 +139,9 @@ func (w *WallpaperIntegration) Apply(t theme.Theme) error {
 	return nil
 }
 
-// getWallpaperForTheme maps theme names to wallpaper files
-func (w *WallpaperIntegration) getWallpaperForTheme(themeName string) string {
-	// Normalize theme name for comparison
-	normalizedName := strings.ToLower(themeName)
-
-	// Map theme names to wallpaper files
-	var wallpaperFile string
-	if strings.Contains(normalizedName, "nord") {
-		wallpaperFile = "nord.png"
-	} else if strings.Contains(normalizedName, "catppuccin") || strings.Contains(normalizedName, "rose pine") {
-		wallpaperFile = "catppuccin-rosepine.jpg"
-	} else {
-		// Default to catppuccin-rosepine for unknown themes
-		wallpaperFile = "catppuccin-rosepine.jpg"
-	}
-
-	// Try to find the wallpaper in assets directory
+// getWallpaperForTheme returns the path of a wallpaper file in the assets
+// directory, or "" if it isn't there
+func (w *WallpaperIntegration) getWallpaperForTheme(wallpaperFile string) string {
 	wallpaperPath := filepath.Join(w.assetsPath, wallpaperFile)
 	if _, err := os.Stat(wallpaperPath); err == nil {
 		return wallpaperPath
@@ -122,6 +151,61 @@ func (w *WallpaperIntegration) getWallpaperForTheme(themeName string) string {
 	return ""
 }
 
+// Size of generated wallpapers
+const (
+	wallpaperWidth  = 2560
+	wallpaperHeight = 1600
+)
+
+// generateWallpaper draws a PNG wallpaper from the palette: the background
+// darkening towards the bottom, crossed by a band of the accent colors
+func generateWallpaper(colors theme.ColorPalette, path string) error {
+	var parsed []theme.Color
+	for _, value := range []string{
+		colors.Background, colors.Black,
+		colors.Red, colors.Yellow, colors.Green, colors.Cyan, colors.Blue, colors.Magenta,
+	} {
+		c, err := theme.ParseColor(value)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, c)
+	}
+	background, shade, accents := parsed[0], parsed[1], parsed[2:]
+
+	img := image.NewNRGBA(image.Rect(0, 0, wallpaperWidth, wallpaperHeight))
+	stripeHeight := wallpaperHeight / 48
+	bandTop := wallpaperHeight * 2 / 3
+	for y := 0; y < wallpaperHeight; y++ {
+		row := mixColors(background, shade, float64(y)/wallpaperHeight)
+		if stripe := (y - bandTop) / stripeHeight; y >= bandTop && stripe < len(accents) {
+			row = accents[stripe]
+		}
+		pixel := color.NRGBA{R: row.R, G: row.G, B: row.B, A: 255}
+		for x := 0; x < wallpaperWidth; x++ {
+			img.SetNRGBA(x, y, pixel)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// mixColors returns the color a fraction t of the way from a to b
+func mixColors(a, b theme.Color, t float64) theme.Color {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return theme.Color{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 255}
+}
+
 // copyFile copies a file from src to dst
 func (w *WallpaperIntegration) copyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
@@ -141,6 +225,10 @@ func (w *WallpaperIntegration) copyFile(src, dst string) error {
 }
 
 func (w *WallpaperIntegration) setWallpaper(imagePath string) error {
+	if !w.allowed("setting the desktop picture") {
+		return nil
+	}
+
 	// Use osascript to set wallpaper
 	script := fmt.Sprintf(`
 tell application "System Events"
@@ -0,0 +1,40 @@
+package integrations
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// TestGenerateWallpaper verifies generated wallpapers use the theme's
+// background and accent colors
+func TestGenerateWallpaper(t *testing.T) {
+	dracula, _ := theme.DefaultRegistry().Lookup("Dracula")
+	path := filepath.Join(t.TempDir(), "dracula.png")
+	if err := generateWallpaper(dracula.Colors, path); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := img.Bounds().Dx(); got != wallpaperWidth {
+		t.Errorf("Expected width %d, got %d", wallpaperWidth, got)
+	}
+	if r, g, b, _ := img.At(0, 0).RGBA(); r>>8 != 0x28 || g>>8 != 0x2a || b>>8 != 0x36 {
+		t.Errorf("Expected the background at the top, got %02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+	if r, g, b, _ := img.At(0, wallpaperHeight*2/3).RGBA(); r>>8 != 0xff || g>>8 != 0x55 || b>>8 != 0x55 {
+		t.Errorf("Expected the red stripe at the top of the band, got %02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+}
@@ -2,10 +2,12 @@ package integrations
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"zakaranda/internal/theme"
+	"strings"
 
+	"github.com/dahromy/zakaranda/internal/theme"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,18 +19,25 @@ type WarpVariant struct {
 }
 
 type WarpIntegration struct {
+	home       string
 	configPath string
 	themesPath string
 }
 
 func NewWarpIntegration() *WarpIntegration {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	home, _ := os.UserHomeDir()
+	return newWarpIntegration(home)
+}
+
+// newWarpIntegration returns the integration with its configuration below home
+func newWarpIntegration(home string) *WarpIntegration {
+	if home == "" {
 		return &WarpIntegration{configPath: "", themesPath: ""}
 	}
 	// Both Warp (Default) and Warp Preview share the same themes directory
 	themesPath := filepath.Join(home, ".warp", "themes")
 	return &WarpIntegration{
+		home:       home,
 		configPath: themesPath,
 		themesPath: themesPath,
 	}
@@ -66,8 +75,8 @@ func (w *WarpIntegration) IsInstalled() bool {
 // GetVariants returns all installed Warp variants
 func (w *WarpIntegration) GetVariants() []WarpVariant {
 	var variants []WarpVariant
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
+	homeDir := w.home
+	if homeDir == "" {
 		return variants
 	}
 
@@ -120,20 +129,43 @@ func (w *WarpIntegration) GetVariants() []WarpVariant {
 }
 
 func (w *WarpIntegration) Apply(t theme.Theme) error {
-	// Use the shared themes directory (both Warp variants use ~/.warp/themes)
-	themesPath := w.themesPath
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+
+	// A theme imported from Warp is still installed, with its background
+	// image or gradient, which the generated file would lose
+	if t.Source != nil && t.Source.App == "warp" && len(t.Overrides) == 0 && w.hasTheme(t.Source.Theme) {
+		return nil
+	}
+
+	return w.writeTheme(t, true)
+}
+
+// ApplyPalette writes the theme file, even for a theme imported from Warp
+func (w *WarpIntegration) ApplyPalette(t theme.Theme) error {
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+	return w.writeTheme(t, false)
+}
 
-	// Create themes directory if it doesn't exist
-	if err := os.MkdirAll(themesPath, 0755); err != nil {
+// writeTheme writes the theme to its file in the themes directory, backing
+// up the file it replaces when backup is set
+func (w *WarpIntegration) writeTheme(t theme.Theme, backup bool) error {
+	// Create the shared themes directory (both Warp variants use ~/.warp/themes)
+	if err := os.MkdirAll(w.themesPath, 0755); err != nil {
 		return fmt.Errorf("failed to create themes directory: %w", err)
 	}
 
 	// Write theme file
 	themeFileName := fmt.Sprintf("%s.yaml", theme.SanitizeFileName(t.Name))
-	themePath := filepath.Join(themesPath, themeFileName)
+	themePath := filepath.Join(w.themesPath, themeFileName)
 
 	// Create backup if file exists
-	if existingData, err := os.ReadFile(themePath); err == nil && len(existingData) > 0 {
+	if existingData, err := os.ReadFile(themePath); backup && err == nil && len(existingData) > 0 {
 		backupPath := themePath + ".backup"
 		if err := os.WriteFile(backupPath, existingData, 0644); err != nil {
 			return fmt.Errorf("failed to create backup: %w", err)
@@ -155,16 +187,38 @@ func (w *WarpIntegration) Apply(t theme.Theme) error {
 	return nil
 }
 
+// hasTheme reports whether a theme file in the themes directory has the given name
+func (w *WarpIntegration) hasTheme(name string) bool {
+	found := false
+	_ = filepath.WalkDir(w.themesPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || found || !strings.HasSuffix(path, ".yaml") && !strings.HasSuffix(path, ".yml") {
+			return nil
+		}
+		var file struct {
+			Name string `yaml:"name"`
+		}
+		if data, err := os.ReadFile(path); err == nil && yaml.Unmarshal(data, &file) == nil && file.Name == name {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
 func (w *WarpIntegration) generateWarpTheme(t theme.Theme) map[string]interface{} {
-	// Determine if theme is light or dark based on background color
-	details := w.determineThemeDetails(t.Colors.Background)
+	// Warp calls light themes "lighter" and dark themes "darker"
+	details := "darker"
+	if t.IsLight() {
+		details = "lighter"
+	}
 
 	// According to official spec, the structure should be:
 	// name, accent, cursor (optional), background, foreground, details, terminal_colors
 	return map[string]interface{}{
 		"name":       t.Name,
-		"accent":     t.Colors.Blue,
-		"cursor":     t.Colors.Green, // Use green as cursor color for visibility
+		"accent":     colorOr(t.Colors.Accent, t.Colors.Blue),
+		"cursor":     colorOr(t.Colors.Cursor, t.Colors.Green), // Green stands out when the theme has no cursor color
 		"background": t.Colors.Background,
 		"foreground": t.Colors.Foreground,
 		"details":    details,
@@ -192,55 +246,3 @@ func (w *WarpIntegration) generateWarpTheme(t theme.Theme) map[string]interface{
 		},
 	}
 }
-
-// determineThemeDetails determines if a theme is "darker" or "lighter" based on background color
-func (w *WarpIntegration) determineThemeDetails(bgColor string) string {
-	// Remove # if present
-	hex := bgColor
-	if len(hex) > 0 && hex[0] == '#' {
-		hex = hex[1:]
-	}
-
-	// Parse hex to RGB
-	var r, g, b uint8
-	if len(hex) == 6 {
-		fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b)
-	}
-
-	// Calculate relative luminance using the formula from WCAG
-	// https://www.w3.org/TR/WCAG20/#relativeluminancedef
-	rLinear := float64(r) / 255.0
-	gLinear := float64(g) / 255.0
-	bLinear := float64(b) / 255.0
-
-	// Apply gamma correction
-	if rLinear <= 0.03928 {
-		rLinear = rLinear / 12.92
-	} else {
-		rLinear = ((rLinear + 0.055) / 1.055)
-		rLinear = rLinear * rLinear // Simplified power of 2.4
-	}
-
-	if gLinear <= 0.03928 {
-		gLinear = gLinear / 12.92
-	} else {
-		gLinear = ((gLinear + 0.055) / 1.055)
-		gLinear = gLinear * gLinear
-	}
-
-	if bLinear <= 0.03928 {
-		bLinear = bLinear / 12.92
-	} else {
-		bLinear = ((bLinear + 0.055) / 1.055)
-		bLinear = bLinear * bLinear
-	}
-
-	// Calculate luminance
-	luminance := 0.2126*rLinear + 0.7152*gLinear + 0.0722*bLinear
-
-	// If luminance is less than 0.5, it's a dark theme
-	if luminance < 0.5 {
-		return "darker"
-	}
-	return "lighter"
-}
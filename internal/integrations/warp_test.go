@@ -0,0 +1,50 @@
+package integrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+	"gopkg.in/yaml.v3"
+)
+
+// TestWarpApplyImportedTheme verifies that the accent and cursor colors are
+// written when set and that a theme imported from Warp leaves its original
+// file alone
+func TestWarpApplyImportedTheme(t *testing.T) {
+	home := t.TempDir()
+	w := newWarpIntegration(home)
+
+	th, _ := theme.DefaultRegistry().Lookup("Dracula")
+	th.Name = "Harbor Dusk"
+	th.Colors.Accent = "#fc9f24"
+	if err := w.Apply(th); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(home, ".warp", "themes", "Harbor_Dusk.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written map[string]any
+	if err := yaml.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if written["accent"] != "#fc9f24" || written["cursor"] != th.Colors.Green {
+		t.Errorf("Expected the accent and the green cursor, got %v and %v", written["accent"], written["cursor"])
+	}
+
+	// The file now stands in for a Warp theme with a gradient the palette lacks
+	original := []byte("name: Harbor Dusk\nbackground:\n  top: '#1d2021'\n  bottom: '#3c3836'\n")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	th.Source = &theme.ThemeSource{App: "warp", Theme: "Harbor Dusk"}
+	if err := w.Apply(th); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(original) {
+		t.Errorf("Expected the original Warp theme to be kept, got:\n%s", data)
+	}
+}
@@ -1,6 +1,6 @@
 package integrations
 
-import "zakaranda/internal/theme"
+import "github.com/dahromy/zakaranda/internal/theme"
 
 import (
 	"encoding/json"
@@ -17,58 +17,18 @@ type ZedIntegration struct {
 
 // ZedExtension represents a Zed theme extension
 type ZedExtension struct {
-	ExtensionID string
-	ThemeName   string // Theme name to set in settings.json
-	IsLight     bool   // Whether this is a light theme
-}
-
-// Map of our theme names to Zed extension information
-var zedThemeExtensions = map[string]ZedExtension{
-	"Nord": {
-		ExtensionID: "nord",
-		ThemeName:   "Nord Dark",
-		IsLight:     false,
-	},
-	"Catppuccin Latte": {
-		ExtensionID: "catppuccin",
-		ThemeName:   "Catppuccin Latte",
-		IsLight:     true,
-	},
-	"Catppuccin Frappe": {
-		ExtensionID: "catppuccin",
-		ThemeName:   "Catppuccin Frappé",
-		IsLight:     false,
-	},
-	"Catppuccin Macchiato": {
-		ExtensionID: "catppuccin",
-		ThemeName:   "Catppuccin Macchiato",
-		IsLight:     false,
-	},
-	"Catppuccin Mocha": {
-		ExtensionID: "catppuccin",
-		ThemeName:   "Catppuccin Mocha",
-		IsLight:     false,
-	},
-	"Rose Pine": {
-		ExtensionID: "rose-pine-theme",
-		ThemeName:   "Rosé Pine",
-		IsLight:     false,
-	},
-	"Rose Pine Moon": {
-		ExtensionID: "rose-pine-theme",
-		ThemeName:   "Rosé Pine Moon",
-		IsLight:     false,
-	},
-	"Rose Pine Dawn": {
-		ExtensionID: "rose-pine-theme",
-		ThemeName:   "Rosé Pine Dawn",
-		IsLight:     true,
-	},
+	ExtensionID string `yaml:"extension_id"` // Empty for themes built into Zed
+	ThemeName   string `yaml:"theme_name"`   // Theme name to set in settings.json
 }
 
 func NewZedIntegration() *ZedIntegration {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	home, _ := os.UserHomeDir()
+	return newZedIntegration(home)
+}
+
+// newZedIntegration returns the integration with its configuration below home
+func newZedIntegration(home string) *ZedIntegration {
+	if home == "" {
 		return &ZedIntegration{configPath: "", extensionsPath: ""}
 	}
 
@@ -122,18 +82,68 @@ func (z *ZedIntegration) GetExtensionURL(extensionID string) string {
 }
 
 func (z *ZedIntegration) Apply(t theme.Theme) error {
-	// Check if theme has official Zed extension
-	themeExt, hasExtension := zedThemeExtensions[t.Name]
-	if !hasExtension {
-		return fmt.Errorf("no Zed extension available for theme: %s", t.Name)
+	t, err := prepareTheme(t)
+	if err != nil {
+		return err
+	}
+
+	themeName, err := z.resolveThemeName(t)
+	if err != nil {
+		return err
+	}
+
+	// Set both light and dark to the same theme
+	// Use ApplyPair to set different themes for light/dark mode
+	return z.writeThemeSettings(themeName, themeName)
+}
+
+// ApplyPair sets separate light and dark themes that Zed switches between
+// following the system appearance
+func (z *ZedIntegration) ApplyPair(pair theme.Pair) error {
+	light, err := prepareTheme(pair.Light)
+	if err != nil {
+		return err
+	}
+	dark, err := prepareTheme(pair.Dark)
+	if err != nil {
+		return err
+	}
+
+	lightName, err := z.resolveThemeName(light)
+	if err != nil {
+		return err
+	}
+	darkName, err := z.resolveThemeName(dark)
+	if err != nil {
+		return err
+	}
+
+	return z.writeThemeSettings(lightName, darkName)
+}
+
+// resolveThemeName returns the Zed theme name to use for t, generating a
+// theme file when there is no official extension
+func (z *ZedIntegration) resolveThemeName(t theme.Theme) (string, error) {
+	// Check if theme has official Zed extension (themes extending a built-in
+	// only use it when none of the colors were overridden)
+	if themeExt, hasExtension := zedThemeExtensions[t.OfficialName()]; hasExtension {
+		// Check if extension is installed (themes built into Zed have no extension)
+		if themeExt.ExtensionID != "" && !z.IsExtensionInstalled(themeExt.ExtensionID) {
+			extensionURL := z.GetExtensionURL(themeExt.ExtensionID)
+			return "", fmt.Errorf("extension not installed\n\nPlease install the %s extension first:\n%s\n\nAfter installation, press Enter to continue", themeExt.ExtensionID, extensionURL)
+		}
+		return themeExt.ThemeName, nil
 	}
 
-	// Check if extension is installed
-	if !z.IsExtensionInstalled(themeExt.ExtensionID) {
-		extensionURL := z.GetExtensionURL(themeExt.ExtensionID)
-		return fmt.Errorf("extension not installed\n\nPlease install the %s extension first:\n%s\n\nAfter installation, press Enter to continue", themeExt.ExtensionID, extensionURL)
+	// Fallback to a generated theme file built from the palette
+	if err := z.writeGeneratedTheme(t); err != nil {
+		return "", err
 	}
+	return t.Name, nil
+}
 
+// writeThemeSettings updates the theme entry of settings.json, following the system appearance
+func (z *ZedIntegration) writeThemeSettings(lightName, darkName string) error {
 	// Read existing settings
 	var settings map[string]interface{}
 	data, err := os.ReadFile(z.configPath)
@@ -160,17 +170,12 @@ func (z *ZedIntegration) Apply(t theme.Theme) error {
 	}
 
 	// Update theme configuration
-	themeConfig := map[string]interface{}{
-		"mode": "system",
+	settings["theme"] = map[string]interface{}{
+		"mode":  "system",
+		"light": lightName,
+		"dark":  darkName,
 	}
 
-	// Set both light and dark to the same theme
-	// User can manually adjust if they want different themes for light/dark mode
-	themeConfig["light"] = themeExt.ThemeName
-	themeConfig["dark"] = themeExt.ThemeName
-
-	settings["theme"] = themeConfig
-
 	// Write updated settings
 	newData, err := json.MarshalIndent(settings, "", "  ")
 	if err != nil {
@@ -184,6 +189,115 @@ func (z *ZedIntegration) Apply(t theme.Theme) error {
 	return nil
 }
 
+// themesPath returns the directory Zed loads user theme files from
+func (z *ZedIntegration) themesPath() string {
+	return filepath.Join(filepath.Dir(z.configPath), "themes")
+}
+
+// writeGeneratedTheme writes a Zed theme family file generated from the palette
+func (z *ZedIntegration) writeGeneratedTheme(t theme.Theme) error {
+	themesPath := z.themesPath()
+	if err := os.MkdirAll(themesPath, 0755); err != nil {
+		return fmt.Errorf("failed to create themes directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(z.generateZedTheme(t), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal theme: %w", err)
+	}
+
+	themePath := filepath.Join(themesPath, theme.SanitizeFileName(t.Name)+".json")
+	if err := os.WriteFile(themePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write theme file: %w", err)
+	}
+
+	return nil
+}
+
+// generateZedTheme maps the palette onto Zed's theme schema
+func (z *ZedIntegration) generateZedTheme(t theme.Theme) map[string]interface{} {
+	style := map[string]interface{}{
+		"background":                    t.Colors.Background,
+		"editor.background":             t.Colors.Background,
+		"editor.foreground":             t.Colors.Foreground,
+		"editor.gutter.background":      t.Colors.Background,
+		"editor.line_number":            t.Colors.BrightBlack,
+		"editor.active_line.background": withAlpha(t.Colors.Black, 0x40),
+		"text":                          t.Colors.Foreground,
+		"text.muted":                    t.Colors.BrightBlack,
+		"border":                        t.Colors.Black,
+		"panel.background":              t.Colors.Background,
+		"status_bar.background":         t.Colors.Background,
+		"title_bar.background":          t.Colors.Background,
+		"tab_bar.background":            t.Colors.Background,
+		"tab.active_background":         t.Colors.Background,
+		"tab.inactive_background":       t.Colors.Background,
+		"elevated_surface.background":   t.Colors.Background,
+		"surface.background":            t.Colors.Background,
+		"error":                         t.Colors.Red,
+		"warning":                       t.Colors.Yellow,
+		"success":                       t.Colors.Green,
+		"info":                          t.Colors.Blue,
+		"terminal.background":           t.Colors.Background,
+		"terminal.foreground":           t.Colors.Foreground,
+		"terminal.ansi.black":           t.Colors.Black,
+		"terminal.ansi.red":             t.Colors.Red,
+		"terminal.ansi.green":           t.Colors.Green,
+		"terminal.ansi.yellow":          t.Colors.Yellow,
+		"terminal.ansi.blue":            t.Colors.Blue,
+		"terminal.ansi.magenta":         t.Colors.Magenta,
+		"terminal.ansi.cyan":            t.Colors.Cyan,
+		"terminal.ansi.white":           t.Colors.White,
+		"terminal.ansi.bright_black":    t.Colors.BrightBlack,
+		"terminal.ansi.bright_red":      t.Colors.BrightRed,
+		"terminal.ansi.bright_green":    t.Colors.BrightGreen,
+		"terminal.ansi.bright_yellow":   t.Colors.BrightYellow,
+		"terminal.ansi.bright_blue":     t.Colors.BrightBlue,
+		"terminal.ansi.bright_magenta":  t.Colors.BrightMagenta,
+		"terminal.ansi.bright_cyan":     t.Colors.BrightCyan,
+		"terminal.ansi.bright_white":    t.Colors.BrightWhite,
+		"players": []map[string]string{
+			{
+				"cursor":     t.Colors.Blue,
+				"selection":  withAlpha(t.Colors.BrightBlack, 0x60),
+				"background": t.Colors.Blue,
+			},
+		},
+		"syntax": map[string]map[string]string{
+			"keyword":  {"color": t.Colors.Magenta},
+			"function": {"color": t.Colors.Blue},
+			"string":   {"color": t.Colors.Green},
+			"number":   {"color": t.Colors.Yellow},
+			"type":     {"color": t.Colors.Cyan},
+			"comment":  {"color": t.Colors.BrightBlack},
+			"constant": {"color": t.Colors.Yellow},
+		},
+	}
+
+	return map[string]interface{}{
+		"$schema": "https://zed.dev/schema/themes/v0.2.0.json",
+		"name":    t.Name,
+		"author":  "Zakaranda",
+		"themes": []map[string]interface{}{
+			{
+				"name":       t.Name,
+				"appearance": string(t.ResolvedAppearance()),
+				"style":      style,
+			},
+		},
+	}
+}
+
+// withAlpha returns the color with its alpha channel replaced
+func withAlpha(hex string, alpha uint8) string {
+	c, err := theme.ParseColor(hex)
+	if err != nil {
+		return hex
+	}
+	c.A = alpha
+	return c.Hex()
+}
+
 // stripJSONComments removes comments from JSONC (JSON with Comments)
 // This is a character-by-character parser that preserves strings
 func (z *ZedIntegration) stripJSONComments(jsonc string) string {
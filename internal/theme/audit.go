@@ -0,0 +1,149 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WCAG 2 contrast levels for normal text
+const (
+	ContrastAA  = 4.5
+	ContrastAAA = 7.0
+)
+
+// ContrastCheck is the contrast of text in one palette color on another
+type ContrastCheck struct {
+	Label      string // Name of the text color, e.g. "bright red"
+	Text       string // Palette key of the text color, the one FixContrast adjusts
+	Background string // Palette key of the color behind it
+	Ratio      float64
+	adjust     string // Key FixContrast changes, when not Text
+}
+
+func (c ContrastCheck) String() string {
+	return fmt.Sprintf("%s on %s", c.Label, strings.ReplaceAll(c.Background, "_", " "))
+}
+
+// Level returns "AAA", "AA" or "fail"
+func (c ContrastCheck) Level() string {
+	switch {
+	case c.Ratio >= ContrastAAA:
+		return "AAA"
+	case c.Ratio >= ContrastAA:
+		return "AA"
+	}
+	return "fail"
+}
+
+// AuditReport lists the contrast of a theme's text colors
+type AuditReport struct {
+	Theme  string
+	Checks []ContrastCheck
+}
+
+// Failing returns the checks below the target ratio
+func (r AuditReport) Failing(target float64) []ContrastCheck {
+	var failing []ContrastCheck
+	for _, c := range r.Checks {
+		if c.Ratio < target {
+			failing = append(failing, c)
+		}
+	}
+	return failing
+}
+
+// ansiKeys are the palette keys of the 16 ANSI colors, comment color last
+var ansiKeys = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright_red", "bright_green", "bright_yellow", "bright_blue", "bright_magenta", "bright_cyan", "bright_white",
+	"bright_black",
+}
+
+// Audit measures the WCAG contrast of the foreground and every ANSI color on
+// the background, and of selected text on the selection when the theme sets a
+// selection color. Bright black, which editors and prompts use for comments,
+// is labeled as the comment color. Invalid colors are skipped.
+func Audit(t Theme) AuditReport {
+	colors := paletteColors(t.Colors)
+	report := AuditReport{Theme: t.Name}
+	check := func(label, text, background, adjust string) {
+		fg, ok := colors[text]
+		bg, ok2 := colors[background]
+		if !ok || !ok2 {
+			return
+		}
+		report.Checks = append(report.Checks, ContrastCheck{
+			Label: label, Text: text, Background: background, Ratio: ContrastRatio(fg, bg), adjust: adjust,
+		})
+	}
+
+	check("foreground", "foreground", "background", "")
+	for _, key := range ansiKeys {
+		label := strings.ReplaceAll(key, "_", " ")
+		if key == "bright_black" {
+			label = "comment (bright black)"
+		}
+		check(label, key, "background", "")
+	}
+	if t.Colors.SelectionBackground != "" {
+		text := "selection_foreground"
+		if t.Colors.SelectionForeground == "" {
+			text = "foreground"
+		}
+		// The selection text is usually the foreground, so the selection moves instead
+		check("selected text", text, "selection_background", "selection_background")
+	}
+	return report
+}
+
+// FixContrast returns the theme with every color failing the target ratio
+// moved in OKLCH lightness until it passes, keeping its hue, and the palette
+// keys it changed. Text colors move away from their background; for selected
+// text the selection color moves instead. Colors that can't reach the target
+// end at the lightness extreme.
+func FixContrast(t Theme, target float64) (Theme, []string) {
+	var changed []string
+	for _, c := range Audit(t).Failing(target) {
+		adjust, against := c.Text, c.Background
+		if c.adjust != "" {
+			adjust, against = c.adjust, c.Text
+		}
+		colors := paletteColors(t.Colors)
+		fixed := colors[adjust].OKLCH().WithContrast(colors[against], target)
+		fixed.A = colors[adjust].A
+		for _, slot := range t.Colors.slots() {
+			if slot.key == adjust {
+				*slot.value = fixed.Hex()
+			}
+		}
+		changed = append(changed, adjust)
+	}
+	return t, changed
+}
+
+// ParseContrastTarget parses "AA", "AAA" or a ratio such as "5" or "5:1"
+func ParseContrastTarget(s string) (float64, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "AA":
+		return ContrastAA, nil
+	case "AAA":
+		return ContrastAAA, nil
+	}
+	ratio, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), ":1"), 64)
+	if err != nil || ratio < 1 || ratio > 21 {
+		return 0, fmt.Errorf("invalid contrast target %q (use AA, AAA or a ratio from 1 to 21)", s)
+	}
+	return ratio, nil
+}
+
+// paletteColors parses the palette's colors by key, skipping empty and invalid ones
+func paletteColors(p ColorPalette) map[string]Color {
+	colors := make(map[string]Color)
+	for _, slot := range p.slots() {
+		if c, err := ParseColor(*slot.value); err == nil && *slot.value != "" {
+			colors[slot.key] = c
+		}
+	}
+	return colors
+}
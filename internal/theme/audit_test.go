@@ -0,0 +1,77 @@
+package theme
+
+import "testing"
+
+// TestAudit verifies the checks and levels of a theme's contrast report
+func TestAudit(t *testing.T) {
+	dawn, ok := DefaultRegistry().Lookup("Rose Pine Dawn")
+	if !ok {
+		t.Fatal("Rose Pine Dawn not found")
+	}
+	report := Audit(dawn)
+
+	if len(report.Checks) != 17 {
+		t.Fatalf("Expected 17 checks without selection colors, got %d", len(report.Checks))
+	}
+	checks := make(map[string]ContrastCheck)
+	for _, c := range report.Checks {
+		checks[c.Text] = c
+	}
+	if c := checks["black"]; c.Level() != "fail" || c.Ratio > 1.2 {
+		t.Errorf("Expected black on the background to fail, got %.2f (%s)", c.Ratio, c.Level())
+	}
+	if c := checks["foreground"]; c.Level() != "AA" || c.String() != "foreground on background" {
+		t.Errorf("Unexpected foreground check %s: %.2f (%s)", c, c.Ratio, c.Level())
+	}
+	if c := checks["bright_black"]; c.Label != "comment (bright black)" {
+		t.Errorf("Expected bright black labeled as the comment color, got %q", c.Label)
+	}
+
+	dawn.Colors.SelectionBackground = "#dfdad9"
+	selection := Audit(dawn).Checks[17]
+	if selection.Text != "foreground" || selection.Background != "selection_background" {
+		t.Errorf("Expected the foreground checked on the selection, got %s", selection)
+	}
+}
+
+// TestFixContrast verifies failing colors reach the target and keep their hue
+func TestFixContrast(t *testing.T) {
+	dawn, _ := DefaultRegistry().Lookup("Rose Pine Dawn")
+	dawn.Colors.SelectionBackground = "#57527980" // Translucent foreground: unreadable selected text
+
+	fixed, changed := FixContrast(dawn, ContrastAA)
+	if failing := Audit(fixed).Failing(ContrastAA); len(failing) != 0 {
+		t.Errorf("Expected every check to pass, still failing: %v", failing)
+	}
+	if len(changed) != 13 {
+		t.Errorf("Expected 13 adjusted colors, got %v", changed)
+	}
+	if fixed.Colors.Foreground != dawn.Colors.Foreground || fixed.Colors.Blue != dawn.Colors.Blue {
+		t.Error("Expected passing colors to stay unchanged")
+	}
+	if dawn.Colors.Black != "#f2e9e1" {
+		t.Error("Expected the original theme to stay unchanged")
+	}
+
+	before, after := MustParseColor(dawn.Colors.Red).OKLCH(), MustParseColor(fixed.Colors.Red).OKLCH()
+	if HueDistance(before.H, after.H) > 3 || after.L >= before.L {
+		t.Errorf("Expected red darkened at the same hue, got %s from %s", fixed.Colors.Red, dawn.Colors.Red)
+	}
+	if selection := MustParseColor(fixed.Colors.SelectionBackground); selection.A != 0x80 {
+		t.Errorf("Expected the selection to keep its alpha, got %s", fixed.Colors.SelectionBackground)
+	}
+}
+
+// TestParseContrastTarget verifies WCAG levels and ratios are accepted
+func TestParseContrastTarget(t *testing.T) {
+	for input, want := range map[string]float64{"AA": 4.5, "aaa": 7, "5": 5, "3:1": 3} {
+		if got, err := ParseContrastTarget(input); err != nil || got != want {
+			t.Errorf("%q: expected %v, got %v (%v)", input, want, got, err)
+		}
+	}
+	for _, input := range []string{"A", "0.5", "22"} {
+		if _, err := ParseContrastTarget(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+)
+
+// Blend returns the theme t of the way from a to b, with every palette color
+// interpolated in OKLab so the steps in between look like an even mix instead
+// of the muddy grays of RGB mixing. t is clamped to [0, 1]. A slot set in only
+// one of the themes takes the value of the nearer one. The blend is named
+// "<a> to <b>" whatever t is, so applying successive steps updates the same
+// theme in apps.
+func Blend(a, b Theme, t float64) Theme {
+	t = math.Max(0, math.Min(1, t))
+	nearest := a.Colors
+	if t >= 0.5 {
+		nearest = b.Colors
+	}
+
+	colors := a.Colors
+	from, to := paletteColors(a.Colors), paletteColors(b.Colors)
+	nearestSlots := nearest.slots()
+	for i, slot := range colors.slots() {
+		x, ok := from[slot.key]
+		y, ok2 := to[slot.key]
+		if !ok || !ok2 {
+			*slot.value = *nearestSlots[i].value
+			continue
+		}
+		*slot.value = x.Mix(y, t).Hex()
+	}
+
+	return Theme{
+		Name:        a.Name + " to " + b.Name,
+		Description: fmt.Sprintf("%.0f%% of the way from %s to %s", t*100, a.Name, b.Name),
+		Colors:      colors,
+		Tags:        []string{"blended"},
+	}
+}
+
+// Mix returns the color t of the way to other in OKLab, with alpha
+// interpolated linearly
+func (c Color) Mix(other Color, t float64) Color {
+	switch t {
+	case 0:
+		return c
+	case 1:
+		return other
+	}
+	p, q := c.OKLab(), other.OKLab()
+	mixed := OKLab{
+		L: p.L + (q.L-p.L)*t,
+		A: p.A + (q.A-p.A)*t,
+		B: p.B + (q.B-p.B)*t,
+	}.Color()
+	mixed.A = uint8(math.Round(float64(c.A) + (float64(other.A)-float64(c.A))*t))
+	return mixed
+}
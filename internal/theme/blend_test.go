@@ -0,0 +1,54 @@
+package theme
+
+import "testing"
+
+// TestBlend verifies the endpoints, the midpoint and the name of a blend
+func TestBlend(t *testing.T) {
+	dawn, _ := DefaultRegistry().Lookup("Rose Pine Dawn")
+	moon, _ := DefaultRegistry().Lookup("Rose Pine Moon")
+
+	if start := Blend(dawn, moon, -1); start.Colors != dawn.Colors {
+		t.Errorf("Expected the start of the blend to be Rose Pine Dawn, got %+v", start.Colors)
+	}
+	if end := Blend(dawn, moon, 1); end.Colors != moon.Colors {
+		t.Errorf("Expected the end of the blend to be Rose Pine Moon, got %+v", end.Colors)
+	}
+
+	mid := Blend(dawn, moon, 0.5)
+	if mid.Name != "Rose Pine Dawn to Rose Pine Moon" || Blend(dawn, moon, 0.25).Name != mid.Name {
+		t.Errorf("Expected every step named after both themes, got %q", mid.Name)
+	}
+	bg, _ := ParseColor(mid.Colors.Background)
+	from, _ := ParseColor(dawn.Colors.Background)
+	to, _ := ParseColor(moon.Colors.Background)
+	if l := bg.OKLab().L; l < from.OKLab().L*0.45+to.OKLab().L*0.55 || l > from.OKLab().L*0.55+to.OKLab().L*0.45 {
+		t.Errorf("Expected the midpoint background halfway in OKLab lightness, got %.3f", l)
+	}
+}
+
+// TestBlendOptionalSlots verifies a slot set in one theme only comes from the nearer theme
+func TestBlendOptionalSlots(t *testing.T) {
+	dawn, _ := DefaultRegistry().Lookup("Rose Pine Dawn")
+	moon, _ := DefaultRegistry().Lookup("Rose Pine Moon")
+	moon.Colors.SelectionBackground = "#44415a"
+
+	if got := Blend(dawn, moon, 0.4).Colors.SelectionBackground; got != "" {
+		t.Errorf("Expected no selection color before the midpoint, got %q", got)
+	}
+	if got := Blend(dawn, moon, 0.6).Colors.SelectionBackground; got != "#44415a" {
+		t.Errorf("Expected Rose Pine Moon's selection color after the midpoint, got %q", got)
+	}
+}
+
+// TestMix verifies alpha is interpolated along with the color
+func TestMix(t *testing.T) {
+	black := Color{A: 0x00}
+	white := Color{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	gray := black.Mix(white, 0.5)
+	if gray.R != gray.G || gray.G != gray.B || gray.R < 0x60 || gray.R > 0x66 {
+		t.Errorf("Expected the OKLab mid gray, got %s", gray.Hex())
+	}
+	if gray.A != 0x80 {
+		t.Errorf("Expected alpha 0x80, got %#x", gray.A)
+	}
+}
@@ -0,0 +1,72 @@
+package theme
+
+import "math"
+
+// minBrightDistance is the OKLab distance below which a bright color counts
+// as a duplicate of its normal color
+const minBrightDistance = 0.02
+
+// Lightness and chroma added to a normal color to synthesize its bright one
+const (
+	brightLift   = 0.08
+	brightChroma = 1.1
+)
+
+// brightPairs are the palette keys of each normal color and its bright one
+var brightPairs = [][2]string{
+	{"black", "bright_black"}, {"red", "bright_red"}, {"green", "bright_green"}, {"yellow", "bright_yellow"},
+	{"blue", "bright_blue"}, {"magenta", "bright_magenta"}, {"cyan", "bright_cyan"}, {"white", "bright_white"},
+}
+
+// SynthesizeBrights returns the palette with every bright color that
+// duplicates its normal color, or nearly does, replaced by a lighter and
+// slightly more saturated version of the normal color, and the keys it
+// replaced. Bold text and ls output then stay distinct from normal text.
+// Normal colors already too light to brighten keep their duplicate.
+func SynthesizeBrights(p ColorPalette) (ColorPalette, []string) {
+	colors := paletteColors(p)
+	var changed []string
+	for _, pair := range brightPairs {
+		normal, ok := colors[pair[0]]
+		bright, ok2 := colors[pair[1]]
+		if !ok || !ok2 || normal.OKLab().DistanceTo(bright.OKLab()) >= minBrightDistance {
+			continue
+		}
+		lch := normal.OKLCH()
+		lch.L = math.Min(1, lch.L+brightLift)
+		lch.C *= brightChroma
+		synthesized := lch.Color()
+		synthesized.A = bright.A
+		if synthesized.OKLab().DistanceTo(normal.OKLab()) < minBrightDistance {
+			continue
+		}
+		for _, slot := range p.slots() {
+			if slot.key == pair[1] {
+				*slot.value = synthesized.Hex()
+			}
+		}
+		changed = append(changed, pair[1])
+	}
+	return p, changed
+}
+
+// BrightSynthesis reports whether the theme asks for synthesized brights
+func (t Theme) BrightSynthesis() bool {
+	return t.SynthesizeBrights != nil && *t.SynthesizeBrights
+}
+
+// WithSynthesizedBrights returns the theme with SynthesizeBrights applied.
+// The replaced colors count as overrides, so apps use the palette instead of
+// an official theme whose brights are still duplicates.
+func (t Theme) WithSynthesizedBrights() Theme {
+	colors, changed := SynthesizeBrights(t.Colors)
+	if len(changed) == 0 {
+		return t
+	}
+	t.Colors = colors
+	if t.Base == "" {
+		t.Base = t.Name
+	}
+	t.Overrides = mergeTags(t.Overrides, changed)
+	return t
+}
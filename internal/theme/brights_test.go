@@ -0,0 +1,81 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSynthesizeBrights verifies duplicated brights become lighter versions
+// of their normal colors while distinct ones are kept
+func TestSynthesizeBrights(t *testing.T) {
+	nord, _ := DefaultRegistry().Lookup("Nord")
+	colors, changed := SynthesizeBrights(nord.Colors)
+
+	if strings.Join(changed, " ") != "bright_red bright_green bright_yellow bright_blue bright_magenta bright_white" {
+		t.Errorf("Unexpected replaced colors %v", changed)
+	}
+	if colors.BrightBlack != nord.Colors.BrightBlack || colors.BrightCyan != nord.Colors.BrightCyan {
+		t.Error("Expected distinct brights to be kept")
+	}
+	red, _ := ParseColor(nord.Colors.Red)
+	bright, _ := ParseColor(colors.BrightRed)
+	if bright.OKLCH().L <= red.OKLCH().L || HueDistance(bright.OKLCH().H, red.OKLCH().H) > 3 {
+		t.Errorf("Expected a lighter red of the same hue, got %s from %s", colors.BrightRed, nord.Colors.Red)
+	}
+
+	nord.Colors.White, nord.Colors.BrightWhite = "#ffffff", "#ffffff"
+	if colors, _ := SynthesizeBrights(nord.Colors); colors.BrightWhite != "#ffffff" {
+		t.Errorf("Expected white to stay when it can't get brighter, got %s", colors.BrightWhite)
+	}
+}
+
+// TestWithSynthesizedBrights verifies synthesized themes leave official app themes
+func TestWithSynthesizedBrights(t *testing.T) {
+	nord, _ := DefaultRegistry().Lookup("Nord")
+	synthesized := nord.WithSynthesizedBrights()
+	if synthesized.OfficialName() != "" || len(synthesized.Overrides) != 6 {
+		t.Errorf("Expected no official theme and 6 overrides, got %q and %v", synthesized.OfficialName(), synthesized.Overrides)
+	}
+
+	gruvbox, _ := DefaultRegistry().Lookup("Gruvbox Dark")
+	if unchanged := gruvbox.WithSynthesizedBrights(); unchanged.OfficialName() != "Gruvbox Dark" {
+		t.Errorf("Expected Gruvbox Dark to keep its official theme, got %q", unchanged.OfficialName())
+	}
+
+	grid := NewThemePreview(nord).RenderColorGrid()
+	if !strings.Contains(grid, "Bright synthesis (6 replaced)") {
+		t.Error("Expected the color grid to compare the brights before and after")
+	}
+	if strings.Contains(NewThemePreview(gruvbox).RenderColorGrid(), "Bright synthesis") {
+		t.Error("Expected no comparison when the brights are distinct")
+	}
+}
+
+// TestSynthesizeBrightsSetting verifies the setting is loaded and inherited through extends
+func TestSynthesizeBrightsSetting(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"bright-nord.yaml": "name: Bright Nord\nextends: Nord\nsynthesize_brights: true\n",
+		"brighter.yaml":    "name: Brighter Nord\nextends: Bright Nord\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewBuiltInRegistry()
+	if err := NewThemeLoader(dir).LoadInto(r); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Bright Nord", "Brighter Nord"} {
+		if th, ok := r.Lookup(name); !ok || !th.BrightSynthesis() {
+			t.Errorf("Expected %s to ask for synthesized brights", name)
+		}
+	}
+	if nord, _ := r.Lookup("Nord"); nord.BrightSynthesis() {
+		t.Error("Expected built-in themes not to ask for synthesized brights")
+	}
+}
@@ -0,0 +1,64 @@
+package theme
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+)
+
+// builtInFS holds one family file per built-in family, in the theme file
+// schema. Families are listed in file name order.
+//
+//go:embed builtin/*.yaml
+var builtInFS embed.FS
+
+var (
+	builtInFamilies     []BaseTheme
+	builtInFamiliesErr  error
+	builtInFamiliesOnce sync.Once
+)
+
+// GetBuiltInBaseThemes returns all built-in base themes with their variants.
+// Every call returns new families, so callers may modify them.
+func GetBuiltInBaseThemes() []BaseTheme {
+	builtInFamiliesOnce.Do(func() {
+		builtInFamilies, builtInFamiliesErr = loadBuiltInFamilies(builtInFS)
+	})
+	if builtInFamiliesErr != nil {
+		panic(builtInFamiliesErr) // Embedded files are checked by the tests
+	}
+
+	families := make([]BaseTheme, len(builtInFamilies))
+	for i, family := range builtInFamilies {
+		families[i] = family.clone()
+	}
+	return families
+}
+
+// loadBuiltInFamilies parses the family files in the builtin directory of fsys
+func loadBuiltInFamilies(fsys fs.FS) ([]BaseTheme, error) {
+	paths, err := fs.Glob(fsys, "builtin/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	var set customThemeSet
+	for _, path := range paths {
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parseThemeFile(path, data)
+		if err != nil {
+			return nil, err
+		}
+		if len(file.Variants) == 0 {
+			return nil, fmt.Errorf("%s: built-in family has no variants", path)
+		}
+		set.addFile(file)
+	}
+	families, errs := set.resolve(nil)
+	return families, errors.Join(errs...)
+}
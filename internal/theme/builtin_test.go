@@ -0,0 +1,42 @@
+package theme
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestBuiltInFamilyFiles verifies the embedded family files pass lint and
+// load in file name order
+func TestBuiltInFamilyFiles(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("builtin", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		issues, err := LintThemeFile(path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+		if len(issues) > 0 {
+			t.Errorf("%s: expected no issues, got %v", path, issues)
+		}
+	}
+
+	families := GetBuiltInBaseThemes()
+	if len(families) != len(paths) {
+		t.Fatalf("Expected %d families, got %d", len(paths), len(families))
+	}
+	want := []string{"Nord", "Catppuccin", "Rose Pine", "Gruvbox", "Tokyo Night", "Dracula", "Solarized", "Everforest", "Kanagawa", "One Dark"}
+	for i, name := range want {
+		if families[i].Name != name {
+			t.Errorf("Expected family %d to be %s, got %s", i, name, families[i].Name)
+		}
+	}
+	for _, family := range families {
+		for _, variant := range family.Variants {
+			if variant.Appearance == "" || variant.DisplayName == "" {
+				t.Errorf("%s: expected appearance and display name to be set", variant.FullName)
+			}
+		}
+	}
+}
@@ -0,0 +1,505 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color is an sRGB color with an alpha channel
+type Color struct {
+	R, G, B, A uint8
+}
+
+// ColorError describes a color value that could not be parsed
+type ColorError struct {
+	File  string // Theme file the value came from, if any
+	Field string // e.g. "colors.bright_black"
+	Value string
+	Err   error
+}
+
+func (e *ColorError) Error() string {
+	var msg string
+	if e.Value == "" {
+		msg = fmt.Sprintf("%s: missing color", e.Field)
+	} else {
+		msg = fmt.Sprintf("%s: invalid color %q: %v", e.Field, e.Value, e.Err)
+	}
+	if e.File != "" {
+		msg = e.File + ": " + msg
+	}
+	return msg
+}
+
+func (e *ColorError) Unwrap() error {
+	return e.Err
+}
+
+var errMissingColor = errors.New("missing color")
+
+// ParseColor parses a color in one of the supported notations:
+// #rgb, #rgba, #rrggbb, #rrggbbaa, rgb()/rgba(), hsl()/hsla() and CSS named colors
+func ParseColor(s string) (Color, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value == "" {
+		return Color{}, errMissingColor
+	}
+
+	if strings.HasPrefix(value, "#") {
+		return parseHexColor(value[1:])
+	}
+
+	if open := strings.IndexByte(value, '('); open > 0 {
+		if !strings.HasSuffix(value, ")") {
+			return Color{}, fmt.Errorf("missing closing parenthesis")
+		}
+		fn := strings.TrimSpace(value[:open])
+		args := value[open+1 : len(value)-1]
+		switch fn {
+		case "rgb", "rgba":
+			return parseRGBFunc(args)
+		case "hsl", "hsla":
+			return parseHSLFunc(args)
+		default:
+			return Color{}, fmt.Errorf("unknown color function %q", fn)
+		}
+	}
+
+	if hex, ok := namedColors[value]; ok {
+		return parseHexColor(hex)
+	}
+
+	// Accept bare hex digits as written by some tools (e.g. "1e1e2e")
+	if len(value) == 6 || len(value) == 8 {
+		if c, err := parseHexColor(value); err == nil {
+			return c, nil
+		}
+	}
+
+	return Color{}, fmt.Errorf("unrecognized color format")
+}
+
+// MustParseColor is like ParseColor but panics on invalid input.
+// It is intended for colors that are known to be valid, such as built-in palettes.
+func MustParseColor(s string) Color {
+	c, err := ParseColor(s)
+	if err != nil {
+		panic(fmt.Sprintf("theme: invalid color %q: %v", s, err))
+	}
+	return c
+}
+
+// NormalizeColor parses a color and returns it in canonical hex form
+func NormalizeColor(s string) (string, error) {
+	c, err := ParseColor(s)
+	if err != nil {
+		return "", err
+	}
+	return c.Hex(), nil
+}
+
+// Hex returns the canonical lowercase hex form: #rrggbb, or #rrggbbaa when not fully opaque
+func (c Color) Hex() string {
+	if c.A != 0xff {
+		return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// String implements fmt.Stringer
+func (c Color) String() string {
+	return c.Hex()
+}
+
+// Luminance returns the WCAG relative luminance of the color (0 = black, 1 = white)
+// https://www.w3.org/TR/WCAG20/#relativeluminancedef
+func (c Color) Luminance() float64 {
+	return 0.2126*srgbToLinear(c.R) + 0.7152*srgbToLinear(c.G) + 0.0722*srgbToLinear(c.B)
+}
+
+// IsLight reports whether dark text reads better on this color than light text.
+// 0.179 is the luminance at which contrast against black and white is equal.
+func (c Color) IsLight() bool {
+	return c.Luminance() > 0.179
+}
+
+func srgbToLinear(v uint8) float64 {
+	f := float64(v) / 255.0
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+func parseHexColor(hex string) (Color, error) {
+	switch len(hex) {
+	case 3, 4:
+		// Expand shorthand (#abc -> #aabbcc)
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	case 6, 8:
+	default:
+		return Color{}, fmt.Errorf("hex color must have 3, 4, 6 or 8 digits, got %d", len(hex))
+	}
+
+	for i := 0; i < len(hex); i++ {
+		if !isHexDigit(hex[i]) {
+			return Color{}, fmt.Errorf("invalid hex digit %q", hex[i])
+		}
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, err
+	}
+
+	if len(hex) == 8 {
+		return Color{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+	}
+	return Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+func isHexDigit(ch byte) bool {
+	return (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+// splitColorArgs splits functional notation arguments in either the legacy
+// comma form "1, 2, 3, 0.5" or the modern space form "1 2 3 / 0.5"
+func splitColorArgs(args string) (components []string, alpha string, err error) {
+	if slash := strings.IndexByte(args, '/'); slash >= 0 {
+		alpha = strings.TrimSpace(args[slash+1:])
+		args = args[:slash]
+		if strings.Contains(args, ",") {
+			return nil, "", fmt.Errorf("cannot mix commas and '/' in color arguments")
+		}
+	}
+
+	var parts []string
+	if strings.Contains(args, ",") {
+		parts = strings.Split(args, ",")
+	} else {
+		parts = strings.Fields(args)
+	}
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	switch len(parts) {
+	case 3:
+		return parts, alpha, nil
+	case 4:
+		if alpha != "" {
+			return nil, "", fmt.Errorf("alpha specified twice")
+		}
+		return parts[:3], parts[3], nil
+	default:
+		return nil, "", fmt.Errorf("expected 3 or 4 arguments, got %d", len(parts))
+	}
+}
+
+func parseRGBFunc(args string) (Color, error) {
+	components, alpha, err := splitColorArgs(args)
+	if err != nil {
+		return Color{}, err
+	}
+
+	var rgb [3]uint8
+	for i, component := range components {
+		var v float64
+		if strings.HasSuffix(component, "%") {
+			pct, err := parseNumber(strings.TrimSuffix(component, "%"))
+			if err != nil {
+				return Color{}, err
+			}
+			v = pct / 100 * 255
+		} else {
+			v, err = parseNumber(component)
+			if err != nil {
+				return Color{}, err
+			}
+		}
+		if v < 0 || v > 255 {
+			return Color{}, fmt.Errorf("component %q out of range", component)
+		}
+		rgb[i] = uint8(math.Round(v))
+	}
+
+	a, err := parseAlpha(alpha)
+	if err != nil {
+		return Color{}, err
+	}
+	return Color{R: rgb[0], G: rgb[1], B: rgb[2], A: a}, nil
+}
+
+func parseHSLFunc(args string) (Color, error) {
+	components, alpha, err := splitColorArgs(args)
+	if err != nil {
+		return Color{}, err
+	}
+
+	hue, err := parseHue(components[0])
+	if err != nil {
+		return Color{}, err
+	}
+	sat, err := parsePercentage(components[1])
+	if err != nil {
+		return Color{}, err
+	}
+	light, err := parsePercentage(components[2])
+	if err != nil {
+		return Color{}, err
+	}
+
+	r, g, b := hslToRGB(hue, sat, light)
+	a, err := parseAlpha(alpha)
+	if err != nil {
+		return Color{}, err
+	}
+	return Color{R: r, G: g, B: b, A: a}, nil
+}
+
+func parseNumber(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
+
+func parseHue(s string) (float64, error) {
+	unit := 1.0
+	switch {
+	case strings.HasSuffix(s, "deg"):
+		s = strings.TrimSuffix(s, "deg")
+	case strings.HasSuffix(s, "grad"):
+		s, unit = strings.TrimSuffix(s, "grad"), 0.9
+	case strings.HasSuffix(s, "rad"):
+		s, unit = strings.TrimSuffix(s, "rad"), 180/math.Pi
+	case strings.HasSuffix(s, "turn"):
+		s, unit = strings.TrimSuffix(s, "turn"), 360
+	}
+	v, err := parseNumber(s)
+	if err != nil {
+		return 0, err
+	}
+	return math.Mod(math.Mod(v*unit, 360)+360, 360), nil
+}
+
+func parsePercentage(s string) (float64, error) {
+	if !strings.HasSuffix(s, "%") {
+		return 0, fmt.Errorf("expected percentage, got %q", s)
+	}
+	v, err := parseNumber(strings.TrimSuffix(s, "%"))
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 || v > 100 {
+		return 0, fmt.Errorf("percentage %q out of range", s)
+	}
+	return v / 100, nil
+}
+
+func parseAlpha(s string) (uint8, error) {
+	if s == "" {
+		return 0xff, nil
+	}
+	var v float64
+	var err error
+	if strings.HasSuffix(s, "%") {
+		v, err = parseNumber(strings.TrimSuffix(s, "%"))
+		v /= 100
+	} else {
+		v, err = parseNumber(s)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("alpha %q out of range", s)
+	}
+	return uint8(math.Round(v * 255)), nil
+}
+
+// hslToRGB converts hue (degrees), saturation and lightness (0-1) to sRGB
+func hslToRGB(h, s, l float64) (uint8, uint8, uint8) {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return uint8(math.Round((r + m) * 255)), uint8(math.Round((g + m) * 255)), uint8(math.Round((b + m) * 255))
+}
+
+// namedColors maps CSS Color Module Level 4 named colors to hex
+var namedColors = map[string]string{
+	"aliceblue":            "f0f8ff",
+	"antiquewhite":         "faebd7",
+	"aqua":                 "00ffff",
+	"aquamarine":           "7fffd4",
+	"azure":                "f0ffff",
+	"beige":                "f5f5dc",
+	"bisque":               "ffe4c4",
+	"black":                "000000",
+	"blanchedalmond":       "ffebcd",
+	"blue":                 "0000ff",
+	"blueviolet":           "8a2be2",
+	"brown":                "a52a2a",
+	"burlywood":            "deb887",
+	"cadetblue":            "5f9ea0",
+	"chartreuse":           "7fff00",
+	"chocolate":            "d2691e",
+	"coral":                "ff7f50",
+	"cornflowerblue":       "6495ed",
+	"cornsilk":             "fff8dc",
+	"crimson":              "dc143c",
+	"cyan":                 "00ffff",
+	"darkblue":             "00008b",
+	"darkcyan":             "008b8b",
+	"darkgoldenrod":        "b8860b",
+	"darkgray":             "a9a9a9",
+	"darkgreen":            "006400",
+	"darkgrey":             "a9a9a9",
+	"darkkhaki":            "bdb76b",
+	"darkmagenta":          "8b008b",
+	"darkolivegreen":       "556b2f",
+	"darkorange":           "ff8c00",
+	"darkorchid":           "9932cc",
+	"darkred":              "8b0000",
+	"darksalmon":           "e9967a",
+	"darkseagreen":         "8fbc8f",
+	"darkslateblue":        "483d8b",
+	"darkslategray":        "2f4f4f",
+	"darkslategrey":        "2f4f4f",
+	"darkturquoise":        "00ced1",
+	"darkviolet":           "9400d3",
+	"deeppink":             "ff1493",
+	"deepskyblue":          "00bfff",
+	"dimgray":              "696969",
+	"dimgrey":              "696969",
+	"dodgerblue":           "1e90ff",
+	"firebrick":            "b22222",
+	"floralwhite":          "fffaf0",
+	"forestgreen":          "228b22",
+	"fuchsia":              "ff00ff",
+	"gainsboro":            "dcdcdc",
+	"ghostwhite":           "f8f8ff",
+	"gold":                 "ffd700",
+	"goldenrod":            "daa520",
+	"gray":                 "808080",
+	"green":                "008000",
+	"greenyellow":          "adff2f",
+	"grey":                 "808080",
+	"honeydew":             "f0fff0",
+	"hotpink":              "ff69b4",
+	"indianred":            "cd5c5c",
+	"indigo":               "4b0082",
+	"ivory":                "fffff0",
+	"khaki":                "f0e68c",
+	"lavender":             "e6e6fa",
+	"lavenderblush":        "fff0f5",
+	"lawngreen":            "7cfc00",
+	"lemonchiffon":         "fffacd",
+	"lightblue":            "add8e6",
+	"lightcoral":           "f08080",
+	"lightcyan":            "e0ffff",
+	"lightgoldenrodyellow": "fafad2",
+	"lightgray":            "d3d3d3",
+	"lightgreen":           "90ee90",
+	"lightgrey":            "d3d3d3",
+	"lightpink":            "ffb6c1",
+	"lightsalmon":          "ffa07a",
+	"lightseagreen":        "20b2aa",
+	"lightskyblue":         "87cefa",
+	"lightslategray":       "778899",
+	"lightslategrey":       "778899",
+	"lightsteelblue":       "b0c4de",
+	"lightyellow":          "ffffe0",
+	"lime":                 "00ff00",
+	"limegreen":            "32cd32",
+	"linen":                "faf0e6",
+	"magenta":              "ff00ff",
+	"maroon":               "800000",
+	"mediumaquamarine":     "66cdaa",
+	"mediumblue":           "0000cd",
+	"mediumorchid":         "ba55d3",
+	"mediumpurple":         "9370db",
+	"mediumseagreen":       "3cb371",
+	"mediumslateblue":      "7b68ee",
+	"mediumspringgreen":    "00fa9a",
+	"mediumturquoise":      "48d1cc",
+	"mediumvioletred":      "c71585",
+	"midnightblue":         "191970",
+	"mintcream":            "f5fffa",
+	"mistyrose":            "ffe4e1",
+	"moccasin":             "ffe4b5",
+	"navajowhite":          "ffdead",
+	"navy":                 "000080",
+	"oldlace":              "fdf5e6",
+	"olive":                "808000",
+	"olivedrab":            "6b8e23",
+	"orange":               "ffa500",
+	"orangered":            "ff4500",
+	"orchid":               "da70d6",
+	"palegoldenrod":        "eee8aa",
+	"palegreen":            "98fb98",
+	"paleturquoise":        "afeeee",
+	"palevioletred":        "db7093",
+	"papayawhip":           "ffefd5",
+	"peachpuff":            "ffdab9",
+	"peru":                 "cd853f",
+	"pink":                 "ffc0cb",
+	"plum":                 "dda0dd",
+	"powderblue":           "b0e0e6",
+	"purple":               "800080",
+	"rebeccapurple":        "663399",
+	"red":                  "ff0000",
+	"rosybrown":            "bc8f8f",
+	"royalblue":            "4169e1",
+	"saddlebrown":          "8b4513",
+	"salmon":               "fa8072",
+	"sandybrown":           "f4a460",
+	"seagreen":             "2e8b57",
+	"seashell":             "fff5ee",
+	"sienna":               "a0522d",
+	"silver":               "c0c0c0",
+	"skyblue":              "87ceeb",
+	"slateblue":            "6a5acd",
+	"slategray":            "708090",
+	"slategrey":            "708090",
+	"snow":                 "fffafa",
+	"springgreen":          "00ff7f",
+	"steelblue":            "4682b4",
+	"tan":                  "d2b48c",
+	"teal":                 "008080",
+	"thistle":              "d8bfd8",
+	"tomato":               "ff6347",
+	"transparent":          "00000000",
+	"turquoise":            "40e0d0",
+	"violet":               "ee82ee",
+	"wheat":                "f5deb3",
+	"white":                "ffffff",
+	"whitesmoke":           "f5f5f5",
+	"yellow":               "ffff00",
+	"yellowgreen":          "9acd32",
+}
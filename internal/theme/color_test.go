@@ -0,0 +1,115 @@
+package theme
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestParseColor verifies every supported notation normalizes to canonical hex
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"#1E1E2E", "#1e1e2e"},
+		{"#abc", "#aabbcc"},
+		{"#abcd", "#aabbccdd"},
+		{"#1e1e2e80", "#1e1e2e80"},
+		{"#1e1e2eff", "#1e1e2e"},
+		{"1e1e2e", "#1e1e2e"},
+		{"rgb(255, 0, 128)", "#ff0080"},
+		{"rgba(255, 0, 128, 0.5)", "#ff008080"},
+		{"rgb(100% 0% 50% / 50%)", "#ff008080"},
+		{"hsl(0, 100%, 50%)", "#ff0000"},
+		{"hsl(120deg 100% 25%)", "#008000"},
+		{"hsla(240, 100%, 50%, 1)", "#0000ff"},
+		{"hsl(0.5turn 100% 50%)", "#00ffff"},
+		{"RebeccaPurple", "#663399"},
+		{"  white ", "#ffffff"},
+		{"transparent", "#00000000"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeColor(tt.input)
+		if err != nil {
+			t.Errorf("NormalizeColor(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeColor(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestParseColorInvalid verifies malformed colors are rejected instead of becoming black
+func TestParseColorInvalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"#ff00zz",
+		"#12345",
+		"rgb(256, 0, 0)",
+		"rgb(1, 2)",
+		"rgb(1, 2, 3",
+		"hsl(0, 100, 50%)",
+		"cmyk(0, 0, 0, 0)",
+		"notacolor",
+	}
+
+	for _, input := range inputs {
+		if c, err := ParseColor(input); err == nil {
+			t.Errorf("ParseColor(%q) = %s, expected error", input, c.Hex())
+		}
+	}
+}
+
+// TestColorLuminance verifies light/dark classification of known backgrounds
+func TestColorLuminance(t *testing.T) {
+	if !MustParseColor("#eff1f5").IsLight() {
+		t.Error("Expected Catppuccin Latte background to be light")
+	}
+	if MustParseColor("#1e1e2e").IsLight() {
+		t.Error("Expected Catppuccin Mocha background to be dark")
+	}
+	if l := MustParseColor("#ffffff").Luminance(); l < 0.999 {
+		t.Errorf("Expected white luminance 1.0, got %f", l)
+	}
+}
+
+// TestPaletteNormalizeReportsFields verifies errors name the field and value
+func TestPaletteNormalizeReportsFields(t *testing.T) {
+	palette := GetBuiltInThemes()[0].Colors
+	palette.Red = "#ff00zz"
+	palette.BrightBlack = ""
+
+	err := palette.normalize("custom.json", false)
+	if err == nil {
+		t.Fatal("Expected an error for invalid colors")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{`custom.json: colors.red: invalid color "#ff00zz"`, "colors.bright_black: missing color"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected error to contain %q, got: %s", want, msg)
+		}
+	}
+
+	var colorErr *ColorError
+	if !errors.As(err, &colorErr) {
+		t.Error("Expected error to unwrap to *ColorError")
+	}
+}
+
+// TestBuiltInPalettesAreCanonical verifies built-in colors are already normalized
+func TestBuiltInPalettesAreCanonical(t *testing.T) {
+	for _, theme := range GetBuiltInThemes() {
+		normalized := theme
+		if err := normalized.Normalize(); err != nil {
+			t.Errorf("Theme %s has invalid colors: %v", theme.Name, err)
+			continue
+		}
+		if normalized.Colors != theme.Colors {
+			t.Errorf("Theme %s colors are not in canonical form", theme.Name)
+		}
+	}
+}
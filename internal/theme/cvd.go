@@ -0,0 +1,101 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deficiency is a color vision deficiency the preview can simulate
+type Deficiency string
+
+const (
+	NormalVision  Deficiency = ""
+	Protanopia    Deficiency = "protanopia"    // No red cones
+	Deuteranopia  Deficiency = "deuteranopia"  // No green cones
+	Tritanopia    Deficiency = "tritanopia"    // No blue cones
+	Achromatopsia Deficiency = "achromatopsia" // No color vision
+)
+
+// Deficiencies lists the simulated deficiencies in the order the TUI cycles through them
+var Deficiencies = []Deficiency{Protanopia, Deuteranopia, Tritanopia, Achromatopsia}
+
+// cvdMatrices transform linear sRGB for full dichromacy (severity 1), from
+// Machado, Oliveira and Fernandes, "A Physiologically-based Model for
+// Simulation of Color Vision Deficiency" (2009).
+// Achromatopsia maps every channel to the relative luminance.
+var cvdMatrices = map[Deficiency][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+	Achromatopsia: {
+		{0.2126, 0.7152, 0.0722},
+		{0.2126, 0.7152, 0.0722},
+		{0.2126, 0.7152, 0.0722},
+	},
+}
+
+// ParseDeficiency parses a deficiency name, case-insensitively; empty means normal vision
+func ParseDeficiency(s string) (Deficiency, error) {
+	d := Deficiency(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := cvdMatrices[d]; ok || d == NormalVision {
+		return d, nil
+	}
+	return "", fmt.Errorf("invalid color vision deficiency %q (use protanopia, deuteranopia, tritanopia or achromatopsia)", s)
+}
+
+// Simulate returns the color as a person with the deficiency sees it
+func (c Color) Simulate(d Deficiency) Color {
+	m, ok := cvdMatrices[d]
+	if !ok {
+		return c
+	}
+	r, g, b := srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)
+	return Color{
+		R: linearToSRGB(m[0][0]*r + m[0][1]*g + m[0][2]*b),
+		G: linearToSRGB(m[1][0]*r + m[1][1]*g + m[1][2]*b),
+		B: linearToSRGB(m[2][0]*r + m[2][1]*g + m[2][2]*b),
+		A: c.A,
+	}
+}
+
+// Simulate returns the palette as a person with the deficiency sees it.
+// Empty and invalid colors are left as they are.
+func (p ColorPalette) Simulate(d Deficiency) ColorPalette {
+	for _, slot := range p.slots() {
+		if c, err := ParseColor(*slot.value); err == nil && *slot.value != "" {
+			*slot.value = c.Simulate(d).Hex()
+		}
+	}
+	return p
+}
+
+// minDiffDistance is the OKLab distance below which red and green are hard to
+// tell apart in a diff at a glance
+const minDiffDistance = 0.08
+
+// DiffColorsConfusable reports whether a person with the deficiency can hardly
+// tell the palette's red and green apart, the colors git and most diff tools
+// use for removed and added lines
+func DiffColorsConfusable(p ColorPalette, d Deficiency) bool {
+	red, err := ParseColor(p.Red)
+	if err != nil {
+		return false
+	}
+	green, err := ParseColor(p.Green)
+	if err != nil {
+		return false
+	}
+	return red.Simulate(d).OKLab().DistanceTo(green.Simulate(d).OKLab()) < minDiffDistance
+}
@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSimulate verifies deficiencies remove the expected color information
+func TestSimulate(t *testing.T) {
+	red := Color{R: 0xe0, G: 0x40, B: 0x40, A: 0x80}
+	if got := red.Simulate(NormalVision); got != red {
+		t.Errorf("Expected normal vision to keep %s, got %s", red.Hex(), got.Hex())
+	}
+
+	gray := red.Simulate(Achromatopsia)
+	if gray.R != gray.G || gray.G != gray.B {
+		t.Errorf("Expected achromatopsia to give a gray, got %s", gray.Hex())
+	}
+	if gray.A != red.A {
+		t.Errorf("Expected alpha %d kept, got %d", red.A, gray.A)
+	}
+
+	green := Color{R: 0x40, G: 0xa0, B: 0x40, A: 0xff}
+	normal := red.OKLab().DistanceTo(green.OKLab())
+	protan := red.Simulate(Protanopia).OKLab().DistanceTo(green.Simulate(Protanopia).OKLab())
+	tritan := red.Simulate(Tritanopia).OKLab().DistanceTo(green.Simulate(Tritanopia).OKLab())
+	if protan >= normal*0.6 {
+		t.Errorf("Expected protanopia to bring red and green closer, got %.3f from %.3f", protan, normal)
+	}
+	if tritan < normal*0.6 {
+		t.Errorf("Expected tritanopia to keep red and green apart, got %.3f from %.3f", tritan, normal)
+	}
+}
+
+// TestSimulatePalette verifies every set color is simulated and empty ones stay empty
+func TestSimulatePalette(t *testing.T) {
+	dracula, _ := DefaultRegistry().Lookup("Dracula")
+	simulated := dracula.Colors.Simulate(Achromatopsia)
+	if simulated.SelectionBackground != "" {
+		t.Errorf("Expected empty selection background kept, got %q", simulated.SelectionBackground)
+	}
+	for key, c := range paletteColors(simulated) {
+		if c.R != c.G || c.G != c.B {
+			t.Errorf("Expected %s gray, got %s", key, c.Hex())
+		}
+	}
+	if dracula.Colors.Red == simulated.Red {
+		t.Error("Expected the original palette unchanged")
+	}
+}
+
+// TestDiffColorsConfusable verifies the red/green warning for diff colors
+func TestDiffColorsConfusable(t *testing.T) {
+	tokyo, _ := DefaultRegistry().Lookup("Tokyo Night Light")
+	if !DiffColorsConfusable(tokyo.Colors, Deuteranopia) {
+		t.Error("Expected Tokyo Night Light's red and green confusable with deuteranopia")
+	}
+	if DiffColorsConfusable(tokyo.Colors, NormalVision) {
+		t.Error("Expected Tokyo Night Light's red and green distinct with normal vision")
+	}
+	dracula, _ := DefaultRegistry().Lookup("Dracula")
+	for _, d := range Deficiencies {
+		if DiffColorsConfusable(dracula.Colors, d) {
+			t.Errorf("Expected Dracula's red and green distinct with %s", d)
+		}
+	}
+
+	preview := NewThemePreview(tokyo).Simulating(Deuteranopia).Render()
+	if !strings.Contains(preview, "simulating deuteranopia") || !strings.Contains(preview, "hard to tell apart") {
+		t.Error("Expected the preview to name the deficiency and warn about diff colors")
+	}
+}
+
+// TestParseDeficiency verifies deficiency names and their errors
+func TestParseDeficiency(t *testing.T) {
+	if d, err := ParseDeficiency(" Protanopia "); err != nil || d != Protanopia {
+		t.Errorf("Expected protanopia, got %q (%v)", d, err)
+	}
+	if d, err := ParseDeficiency(""); err != nil || d != NormalVision {
+		t.Errorf("Expected normal vision, got %q (%v)", d, err)
+	}
+	if _, err := ParseDeficiency("colorblind"); err == nil {
+		t.Error("Expected an error for an unknown deficiency")
+	}
+}
@@ -0,0 +1,90 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DeriveAppearance returns a variant of t with the given appearance, named
+// "<theme> Light" or "<theme> Dark". The background's OKLCH lightness is
+// inverted into the range of typical backgrounds of that appearance; every
+// other color keeps its hue and chroma and gets the lightness that gives it
+// the same contrast with the new background as it had with the old one, on
+// the other side of it. Dim colors stay dim and readable ones readable.
+func DeriveAppearance(t Theme, appearance Appearance) (Theme, error) {
+	if t.ResolvedAppearance() == appearance {
+		return Theme{}, fmt.Errorf("%s is already %s", t.Name, appearance)
+	}
+	colors, err := invertPalette(t.Colors, appearance)
+	if err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", t.Name, err)
+	}
+
+	suffix := strings.ToUpper(string(appearance[:1])) + string(appearance[1:])
+	return Theme{
+		Name:        t.Name + " " + suffix,
+		Description: fmt.Sprintf("%s variant derived from %s", suffix, t.Name),
+		Colors:      colors,
+		Author:      t.Author,
+		License:     t.License,
+		Homepage:    t.Homepage,
+		Appearance:  appearance,
+		Tags:        mergeTags(t.Tags, []string{"derived"}),
+	}, nil
+}
+
+// invertPalette moves the palette to the other side of the lightness scale,
+// keeping each color's contrast with the background
+func invertPalette(p ColorPalette, appearance Appearance) (ColorPalette, error) {
+	colors := paletteColors(p)
+	bg, ok := colors["background"]
+	if !ok {
+		return p, fmt.Errorf("invalid background %q", p.Background)
+	}
+
+	// Darker dark backgrounds become lighter light ones, within the usual range
+	target := bg.OKLCH()
+	if appearance == AppearanceLight {
+		target.L = math.Max(0.93, math.Min(0.98, 1.18-target.L))
+	} else {
+		target.L = math.Max(0.16, math.Min(0.26, 1.18-target.L))
+	}
+	newBg := target.Color()
+	newBg.A = bg.A
+
+	for _, slot := range p.slots() {
+		c, ok := colors[slot.key]
+		if !ok {
+			continue
+		}
+		inverted := newBg
+		if slot.key != "background" {
+			lighter := c.Luminance() < bg.Luminance() // Darker colors end up lighter than the new background
+			inverted = c.OKLCH().matchContrast(newBg, ContrastRatio(c, bg), lighter)
+			inverted.A = c.A
+		}
+		*slot.value = inverted.Hex()
+	}
+	return p, nil
+}
+
+// matchContrast returns the color with the lightness, on the lighter or
+// darker side of against, whose contrast ratio with against is closest to ratio
+func (p OKLCH) matchContrast(against Color, ratio float64, lighter bool) Color {
+	low, high := 0.0, against.OKLCH().L
+	if lighter {
+		low, high = high, 1
+	}
+	for i := 0; i < 24; i++ {
+		p.L = (low + high) / 2
+		// Contrast grows with the distance from against's lightness
+		if (ContrastRatio(p.Color(), against) < ratio) == lighter {
+			low = p.L
+		} else {
+			high = p.L
+		}
+	}
+	p.L = (low + high) / 2
+	return p.Color()
+}
@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDeriveAppearance verifies derived variants flip the appearance while
+// keeping each color's hue and contrast with the background
+func TestDeriveAppearance(t *testing.T) {
+	for _, name := range []string{"Nord", "Rose Pine Dawn"} {
+		original, _ := DefaultRegistry().Lookup(name)
+		appearance := AppearanceLight
+		if original.IsLight() {
+			appearance = AppearanceDark
+		}
+		derived, err := DeriveAppearance(original, appearance)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if derived.Appearance != appearance || derived.IsLight() != (appearance == AppearanceLight) {
+			t.Errorf("%s: expected a %s theme with background to match, got %s", name, appearance, derived.Colors.Background)
+		}
+
+		before, after := Audit(original).Checks, Audit(derived).Checks
+		if len(before) != len(after) {
+			t.Fatalf("%s: expected %d checks, got %d", name, len(before), len(after))
+		}
+		for i := range before {
+			if math.Abs(before[i].Ratio-after[i].Ratio) > 0.15 {
+				t.Errorf("%s: %s contrast changed from %.2f to %.2f", derived.Name, before[i].Label, before[i].Ratio, after[i].Ratio)
+			}
+		}
+
+		for _, pair := range [][2]string{{original.Colors.Red, derived.Colors.Red}, {original.Colors.Blue, derived.Colors.Blue}} {
+			a, b := MustParseColor(pair[0]).OKLCH(), MustParseColor(pair[1]).OKLCH()
+			if HueDistance(a.H, b.H) > 5 {
+				t.Errorf("%s: hue of %s changed to %s", derived.Name, pair[0], pair[1])
+			}
+		}
+	}
+
+	nord, _ := DefaultRegistry().Lookup("Nord")
+	if derived, _ := DeriveAppearance(nord, AppearanceLight); derived.Name != "Nord Light" {
+		t.Errorf("Expected Nord Light, got %s", derived.Name)
+	}
+	if _, err := DeriveAppearance(nord, AppearanceDark); err == nil {
+		t.Error("Expected an error deriving the theme's own appearance")
+	}
+}
+
+// TestSaveCustomVariant verifies derived variants join their built-in family
+// when loaded, also after reloading, and need a family
+func TestSaveCustomVariant(t *testing.T) {
+	dir := t.TempDir()
+	loader := NewThemeLoader(dir)
+	nord, _ := DefaultRegistry().Lookup("Nord")
+	light, err := DeriveAppearance(nord, AppearanceLight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.SaveCustomVariant("Nord", light, "toml"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewBuiltInRegistry()
+	for i := 0; i < 2; i++ {
+		if err := loader.LoadInto(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	family, ok := r.Family("Nord")
+	if !ok || len(family.Variants) != 2 {
+		t.Fatalf("Expected Nord with 2 variants, got %+v", family)
+	}
+	if v := family.Variants[1]; v.Name != "Light" || v.FullName != "Nord Light" || v.Appearance != AppearanceLight {
+		t.Errorf("Unexpected derived variant %s (%s, %s)", v.FullName, v.Name, v.Appearance)
+	}
+	if len(r.Families()) != len(GetBuiltInBaseThemes()) {
+		t.Errorf("Expected no new families, got %d", len(r.Families()))
+	}
+	if err := loader.SaveCustomVariant("", light, "toml"); err == nil {
+		t.Error("Expected a variant without a family to be rejected")
+	}
+}
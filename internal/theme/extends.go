@@ -0,0 +1,130 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ExtendsCycleError reports custom themes that extend each other in a loop
+type ExtendsCycleError struct {
+	Chain []string // Theme names in extension order, ending with the repeated theme
+}
+
+func (e *ExtendsCycleError) Error() string {
+	return fmt.Sprintf("theme %q: extends cycle: %s", e.Chain[0], strings.Join(e.Chain, " -> "))
+}
+
+// extendsResolver resolves Extends chains of custom themes
+type extendsResolver struct {
+	registered map[string]Theme // By name and alias
+	custom     map[string]*Theme
+	state      map[string]int // 0 = pending, 1 = resolving, 2 = resolved
+	resolved   map[string]error
+}
+
+const (
+	resolvePending = iota
+	resolveInProgress
+	resolveDone
+)
+
+// resolveExtends fills in, in place, the palettes of custom themes that extend
+// another theme, either already registered or custom, and records their base
+// and overrides. The returned slice holds one error per theme (nil when resolved);
+// a theme fails when its parent is unknown, unresolvable or part of a cycle.
+func resolveExtends(registered, custom []Theme) []error {
+	r := &extendsResolver{
+		registered: make(map[string]Theme, len(registered)),
+		custom:     make(map[string]*Theme, len(custom)),
+		state:      make(map[string]int, len(custom)),
+		resolved:   make(map[string]error, len(custom)),
+	}
+	for _, t := range registered {
+		r.registered[themeKey(t.Name)] = t
+		for _, alias := range t.Aliases {
+			r.registered[themeKey(alias)] = t
+		}
+	}
+	errs := make([]error, len(custom))
+	for i := range custom {
+		key := themeKey(custom[i].Name)
+		if _, exists := r.custom[key]; exists {
+			errs[i] = fmt.Errorf("theme %q: another custom theme has the same name", custom[i].Name)
+			continue
+		}
+		r.custom[key] = &custom[i]
+	}
+
+	for i := range custom {
+		if errs[i] == nil {
+			errs[i] = r.resolve(themeKey(custom[i].Name), nil)
+		}
+	}
+	return errs
+}
+
+func (r *extendsResolver) resolve(key string, chain []string) error {
+	t := r.custom[key]
+	switch r.state[key] {
+	case resolveDone:
+		return r.resolved[key]
+	case resolveInProgress:
+		cycle := append([]string{}, chain...)
+		return &ExtendsCycleError{Chain: append(cycle, t.Name)}
+	}
+
+	r.state[key] = resolveInProgress
+	err := r.resolveTheme(t, append(chain, t.Name))
+	r.state[key] = resolveDone
+	r.resolved[key] = err
+	return err
+}
+
+func (r *extendsResolver) resolveTheme(t *Theme, chain []string) error {
+	if t.Extends == "" {
+		return nil
+	}
+
+	parentKey := themeKey(t.Extends)
+	var parent Theme
+	if _, ok := r.custom[parentKey]; ok && parentKey != themeKey(t.Name) {
+		if err := r.resolve(parentKey, chain); err != nil {
+			var cycleErr *ExtendsCycleError
+			if errors.As(err, &cycleErr) {
+				return err
+			}
+			return fmt.Errorf("theme %q: parent %q could not be resolved: %w", t.Name, t.Extends, err)
+		}
+		parent = *r.custom[parentKey]
+	} else if registered, ok := r.registered[parentKey]; ok {
+		parent = registered
+		// A registered theme that extends another keeps its own base
+		if parent.Base == "" {
+			parent.Base = registered.Name
+		}
+	} else {
+		return fmt.Errorf("theme %q: extends unknown theme %q", t.Name, t.Extends)
+	}
+
+	t.Colors.inherit(parent.Colors)
+	if t.Description == "" {
+		t.Description = parent.Description
+	}
+	if t.SynthesizeBrights == nil {
+		t.SynthesizeBrights = parent.SynthesizeBrights
+	}
+
+	t.Base = parent.Base
+	t.Overrides = nil
+	if t.Base != "" {
+		t.Overrides = t.Colors.diff(r.registered[themeKey(t.Base)].Colors)
+	}
+
+	return nil
+}
+
+// themeKey returns the case-insensitive lookup key for a theme name
+func themeKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,310 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// themeFile is the on-disk form of a custom theme. A file with Variants
+// describes a whole family; otherwise it describes a single theme.
+//
+// Inside a family file, each variant's Name is the short variant name
+// ("Dark") and FullName defaults to "<family> <variant>". A standalone theme
+// file placed in a family directory uses Name for the full theme name and
+// may set Variant to the short name.
+type themeFile struct {
+	Name        string       `json:"name" yaml:"name" toml:"name"`
+	Description string       `json:"description" yaml:"description" toml:"description"`
+	Aliases     []string     `json:"aliases" yaml:"aliases" toml:"aliases"`
+	Extends     string       `json:"extends" yaml:"extends" toml:"extends"`
+	Colors      ColorPalette `json:"colors" yaml:"colors" toml:"colors"`
+	Author      string       `json:"author" yaml:"author" toml:"author"`
+	License     string       `json:"license" yaml:"license" toml:"license"`
+	Homepage    string       `json:"homepage" yaml:"homepage" toml:"homepage"`
+	Appearance  Appearance   `json:"appearance" yaml:"appearance" toml:"appearance"`
+	Tags        []string     `json:"tags" yaml:"tags" toml:"tags"`
+	Source      *ThemeSource `json:"source" yaml:"source" toml:"source"`
+	Wallpaper   string       `json:"wallpaper" yaml:"wallpaper" toml:"wallpaper"`
+
+	SynthesizeBrights *bool `json:"synthesize_brights" yaml:"synthesize_brights" toml:"synthesize_brights"`
+
+	Variant     string      `json:"variant" yaml:"variant" toml:"variant"`
+	DisplayName string      `json:"display_name" yaml:"display_name" toml:"display_name"`
+	FullName    string      `json:"full_name" yaml:"full_name" toml:"full_name"`
+	Variants    []themeFile `json:"variants" yaml:"variants" toml:"variants"`
+}
+
+// theme returns the file's own theme, ignoring any variants
+func (f themeFile) theme() Theme {
+	return Theme{
+		Name:        f.Name,
+		Description: f.Description,
+		Aliases:     f.Aliases,
+		Extends:     f.Extends,
+		Colors:      f.Colors,
+		Author:      f.Author,
+		License:     f.License,
+		Homepage:    f.Homepage,
+		Appearance:  f.Appearance,
+		Tags:        f.Tags,
+		Source:      f.Source,
+		Wallpaper:   f.Wallpaper,
+
+		SynthesizeBrights: f.SynthesizeBrights,
+	}
+}
+
+// family returns the file's family-level fields as a BaseTheme without variants
+func (f themeFile) family() BaseTheme {
+	return BaseTheme{
+		Name:        f.Name,
+		Description: f.Description,
+		Author:      f.Author,
+		License:     f.License,
+		Homepage:    f.Homepage,
+		Tags:        f.Tags,
+	}
+}
+
+// normalizeAppearance validates the declared appearance and lowercases it
+func (f *themeFile) normalizeAppearance(path string) error {
+	appearance, err := ParseAppearance(string(f.Appearance))
+	if err != nil {
+		return fmt.Errorf("%s: appearance: %w", path, err)
+	}
+	f.Appearance = appearance
+	return nil
+}
+
+// normalizeVariants validates the colors of every variant of a family file
+func (f *themeFile) normalizeVariants(path string) error {
+	var errs []error
+	for i := range f.Variants {
+		variant := &f.Variants[i]
+		if variant.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: variants[%d]: missing variant name", path, i))
+			continue
+		}
+		if err := variant.normalizeAppearance(path); err != nil {
+			errs = append(errs, fmt.Errorf("variant %q: %w", variant.Name, err))
+		}
+		// Variants extending another theme may leave colors unset to inherit them
+		if err := variant.Colors.normalize(path, variant.Extends != ""); err != nil {
+			errs = append(errs, fmt.Errorf("variant %q: %w", variant.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// familyFileNames are the accepted names of a family directory's metadata file
+var familyFileNames = []string{"family.yaml", "family.yml", "family.json", "family.toml"}
+
+// isFamilyFile reports whether the path names a family metadata file
+func isFamilyFile(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	for _, name := range familyFileNames {
+		if base == name {
+			return true
+		}
+	}
+	return false
+}
+
+// customMember is a custom theme waiting for Extends resolution,
+// together with the family and variant it will be placed in
+type customMember struct {
+	theme       Theme
+	family      int // Index into customThemeSet.families
+	variant     string
+	displayName string
+}
+
+// customThemeSet collects custom themes and families while loading so that
+// Extends can be resolved across all of them before families are assembled
+type customThemeSet struct {
+	families []BaseTheme
+	members  []customMember
+}
+
+// addFile adds a standalone theme or a single-file family
+func (s *customThemeSet) addFile(file themeFile) {
+	family := len(s.families)
+	s.families = append(s.families, file.family())
+
+	if len(file.Variants) == 0 {
+		// Family-level metadata already lives on the BaseTheme
+		t := file.theme()
+		t.Author, t.License, t.Homepage, t.Tags = "", "", "", nil
+		s.members = append(s.members, customMember{
+			theme:       t,
+			family:      family,
+			variant:     file.Name,
+			displayName: file.DisplayName,
+		})
+		return
+	}
+
+	for _, variant := range file.Variants {
+		fullName := variant.FullName
+		if fullName == "" {
+			fullName = file.Name + " " + variant.Name
+			if strings.EqualFold(variant.Name, file.Name) {
+				fullName = file.Name
+			}
+		}
+		t := variant.theme()
+		t.Name = fullName
+		if t.Description == "" {
+			t.Description = file.Description
+		}
+		s.members = append(s.members, customMember{
+			theme:       t,
+			family:      family,
+			variant:     variant.Name,
+			displayName: variant.DisplayName,
+		})
+	}
+}
+
+// addFamilyDir adds a family directory: an optional family metadata file
+// (name, description and optionally variants) plus one theme file per variant
+func (s *customThemeSet) addFamilyDir(tl *ThemeLoader, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	// Family metadata; the directory name is used when there is none
+	var family themeFile
+	foundFamilyFile := false
+	for _, entry := range entries {
+		if entry.IsDir() || !isFamilyFile(entry.Name()) {
+			continue
+		}
+		if foundFamilyFile {
+			return fmt.Errorf("%s: more than one family file", dir)
+		}
+		foundFamilyFile = true
+
+		path := filepath.Join(dir, entry.Name())
+		if err := decodeThemeFile(path, &family); err != nil {
+			return err
+		}
+		if err := family.normalizeVariants(path); err != nil {
+			return err
+		}
+	}
+	if family.Name == "" {
+		family.Name = filepath.Base(dir)
+	}
+
+	// One theme file per variant, in file name order
+	var errs []error
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || !isThemeFile(path) || isFamilyFile(path) {
+			continue
+		}
+
+		file, err := tl.loadThemeFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(file.Variants) > 0 {
+			errs = append(errs, fmt.Errorf("%s: nested families are not supported", path))
+			continue
+		}
+
+		variantName := file.Variant
+		if variantName == "" {
+			variantName = strings.TrimSpace(strings.TrimPrefix(file.Name, family.Name))
+			if variantName == "" {
+				variantName = file.Name
+			}
+		}
+		family.Variants = append(family.Variants, themeFile{
+			Name:        variantName,
+			FullName:    file.Name,
+			DisplayName: file.DisplayName,
+			Description: file.Description,
+			Aliases:     file.Aliases,
+			Extends:     file.Extends,
+			Colors:      file.Colors,
+			Appearance:  file.Appearance,
+			Tags:        file.Tags,
+			Source:      file.Source,
+			Wallpaper:   file.Wallpaper,
+
+			SynthesizeBrights: file.SynthesizeBrights,
+		})
+	}
+
+	if len(family.Variants) == 0 {
+		errs = append(errs, fmt.Errorf("%s: family has no variants", dir))
+		return errors.Join(errs...)
+	}
+
+	s.addFile(family)
+	return errors.Join(errs...)
+}
+
+// resolve resolves Extends for all collected themes and assembles the families.
+// Themes that fail to resolve are dropped and their errors returned; families
+// left empty are dropped too.
+func (s *customThemeSet) resolve(registered []Theme) ([]BaseTheme, []error) {
+	themes := make([]Theme, len(s.members))
+	for i, member := range s.members {
+		themes[i] = member.theme
+	}
+
+	resolveErrs := resolveExtends(registered, themes)
+
+	families := make([]BaseTheme, len(s.families))
+	copy(families, s.families)
+	var errs []error
+	for i, member := range s.members {
+		if resolveErrs[i] != nil {
+			errs = append(errs, resolveErrs[i])
+			continue
+		}
+
+		t := themes[i]
+		family := &families[member.family]
+		if family.Description == "" {
+			family.Description = t.Description
+		}
+
+		displayName := member.displayName
+		if displayName == "" {
+			displayName = member.variant
+		}
+		family.Variants = append(family.Variants, ThemeVariant{
+			Name:        member.variant,
+			DisplayName: displayName,
+			FullName:    t.Name,
+			Colors:      t.Colors,
+			Appearance:  t.Appearance,
+			Tags:        t.Tags,
+			Source:      t.Source,
+			Wallpaper:   t.Wallpaper,
+			Aliases:     t.Aliases,
+			Extends:     t.Extends,
+			Base:        t.Base,
+			Overrides:   t.Overrides,
+
+			SynthesizeBrights: t.SynthesizeBrights,
+		})
+	}
+
+	result := make([]BaseTheme, 0, len(families))
+	for _, family := range families {
+		if len(family.Variants) > 0 {
+			result = append(result, family)
+		}
+	}
+	return result, errs
+}
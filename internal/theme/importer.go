@@ -0,0 +1,23 @@
+package theme
+
+// Importer converts the color scheme files of another tool into themes
+type Importer interface {
+	// Name identifies the format, e.g. "kitty"
+	Name() string
+	// Detect reports whether data, read from path, is in the importer's format.
+	// It looks at the name and content only and may accept files Parse rejects.
+	Detect(path string, data []byte) bool
+	// Parse converts the color schemes in a file, which may hold several
+	Parse(path string, data []byte) ([]Theme, error)
+}
+
+// DetectImporter returns the first of importers that recognizes the file, so
+// importers for more specific formats should come first
+func DetectImporter(importers []Importer, path string, data []byte) (Importer, bool) {
+	for _, importer := range importers {
+		if importer.Detect(path, data) {
+			return importer, true
+		}
+	}
+	return nil, false
+}
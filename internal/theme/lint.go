@@ -0,0 +1,93 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// LintIssue is a problem found in a theme file
+type LintIssue struct {
+	Field   string // Key path, e.g. "variants[1].colors.bright_red"
+	Message string
+	Warning bool // Tolerated by the loader, e.g. a legacy key spelling
+}
+
+func (i LintIssue) String() string {
+	level := "error"
+	if i.Warning {
+		level = "warning"
+	}
+	return fmt.Sprintf("%s: %s: %s", level, i.Field, i.Message)
+}
+
+// LintThemeFile checks a theme or family file against the schema, reporting
+// unknown keys, legacy key spellings, missing slots and invalid values, and
+// family metadata set on a variant, which the loader ignores. An error is
+// returned only if the file can't be read or decoded.
+func LintThemeFile(path string) ([]LintIssue, error) {
+	tree, issues, err := decodeThemeTree(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var file themeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// family.yaml only holds family metadata and takes its name from the
+	// directory; the other files of a family directory are its variants
+	familyMetadata := isFamilyFile(path)
+	variant := !familyMetadata && hasFamilyFile(filepath.Dir(path))
+	return append(issues, file.lint("", familyMetadata, variant)...), nil
+}
+
+// lint checks the values of a decoded theme file, prefixing fields with prefix.
+// Variants take their author, license and homepage from the family.
+func (f themeFile) lint(prefix string, familyMetadata, variant bool) []LintIssue {
+	var issues []LintIssue
+
+	if f.Name == "" && !familyMetadata {
+		issues = append(issues, LintIssue{Field: prefix + "name", Message: "missing theme name"})
+	}
+	if variant {
+		for _, field := range []struct{ key, value string }{
+			{"author", f.Author}, {"license", f.License}, {"homepage", f.Homepage},
+		} {
+			if field.value != "" {
+				issues = append(issues, LintIssue{Field: prefix + field.key, Message: "family metadata can't be set on a variant; set it in the family file"})
+			}
+		}
+	}
+	if _, err := ParseAppearance(string(f.Appearance)); err != nil {
+		issues = append(issues, LintIssue{Field: prefix + "appearance", Message: err.Error()})
+	}
+
+	// Family files carry their colors in the variants
+	if !familyMetadata && len(f.Variants) == 0 {
+		for _, slot := range f.Colors.slots() {
+			field := prefix + "colors." + slot.key
+			if *slot.value == "" {
+				// Themes extending another theme inherit unset colors
+				if f.Extends == "" && !slot.optional {
+					issues = append(issues, LintIssue{Field: field, Message: "missing color"})
+				}
+				continue
+			}
+			if _, err := ParseColor(*slot.value); err != nil {
+				issues = append(issues, LintIssue{Field: field, Message: fmt.Sprintf("invalid color %q: %v", *slot.value, err)})
+			}
+		}
+	}
+
+	for i, variant := range f.Variants {
+		issues = append(issues, variant.lint(fmt.Sprintf("%svariants[%d].", prefix, i), false, true)...)
+	}
+
+	return issues
+}
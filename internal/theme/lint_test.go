@@ -0,0 +1,117 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLintThemeFile verifies lint reports unknown keys, legacy spellings,
+// missing slots and invalid values
+func TestLintThemeFile(t *testing.T) {
+	dir := t.TempDir()
+	palette := strings.Replace(testPaletteYAML("", "#101010"), "  red: \"#808080\"\n", "  red: \"#80808\"\n", 1)
+	palette = strings.Replace(palette, "  cyan: \"#808080\"\n", "", 1)
+	content := "name: Lint\nappearance: dim\nauthr: Jane\n" + palette
+	path := filepath.Join(dir, "lint.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := LintThemeFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{
+		"error: authr: unknown key":                          false,
+		`warning: colors.brightblack: use "bright_black"`:    false,
+		"error: colors.cyan: missing color":                  false,
+		`error: colors.red: invalid color "#80808"`:          false,
+		`error: appearance: invalid appearance "dim" (use l`: false,
+	}
+	for _, issue := range issues {
+		for prefix := range want {
+			if strings.HasPrefix(issue.String(), prefix) {
+				want[prefix] = true
+			}
+		}
+	}
+	for prefix, found := range want {
+		if !found {
+			t.Errorf("Expected an issue starting with %q, got %v", prefix, issues)
+		}
+	}
+}
+
+// TestLintThemeFileClean verifies canonical files, partial themes with
+// extends and family metadata have no issues
+func TestLintThemeFileClean(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"full.yaml":    "name: Full\nappearance: dark\ntags: [calm]\n" + strings.ReplaceAll(testPaletteYAML("", "#101010"), "bright", "bright_"),
+		"extends.json": `{"name": "Darker", "extends": "Nord", "colors": {"background": "#101010"}}`,
+		"family.toml":  "name = \"Family\"\nauthor = \"Jane\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		issues, err := LintThemeFile(path)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if len(issues) > 0 {
+			t.Errorf("%s: expected no issues, got %v", name, issues)
+		}
+	}
+}
+
+// TestLintVariantMetadata verifies author, license and homepage are reported
+// on variants, which take them from the family, but not on standalone themes
+func TestLintVariantMetadata(t *testing.T) {
+	dir := t.TempDir()
+	familyDir := filepath.Join(dir, "paper")
+	if err := os.MkdirAll(familyDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	palette := strings.ReplaceAll(testPaletteYAML("", "#fafafa"), "bright", "bright_")
+	files := map[string]string{
+		filepath.Join(familyDir, "family.yaml"): "name: Paper\nauthor: Jane\n",
+		filepath.Join(familyDir, "light.yaml"):  "name: Paper Light\nauthor: Jane\nlicense: MIT\n" + palette,
+		filepath.Join(dir, "ink.yaml"):          "name: Ink\nauthor: Jane\nhomepage: https://example.com\n" + palette,
+		filepath.Join(dir, "family-file.yaml"):  "name: Ink\nvariants:\n  - name: Dark\n    homepage: https://example.com\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path   string
+		fields []string
+	}{
+		{filepath.Join(familyDir, "light.yaml"), []string{"author", "license"}},
+		{filepath.Join(dir, "ink.yaml"), nil},
+		{filepath.Join(dir, "family-file.yaml"), []string{"variants[0].homepage"}},
+	}
+	for _, tt := range tests {
+		issues, err := LintThemeFile(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields []string
+		for _, issue := range issues {
+			if strings.Contains(issue.Message, "family metadata") {
+				fields = append(fields, issue.Field)
+			}
+		}
+		if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+			t.Errorf("%s: expected metadata issues on %v, got %v", filepath.Base(tt.path), tt.fields, issues)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package theme
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,154 +14,286 @@ import (
 )
 
 type ThemeLoader struct {
-	builtInThemes []Theme
-	customPath    string
+	customPath string
+	loaded     []string    // Families registered by the last LoadInto
+	joined     []BaseTheme // Variants the last LoadInto added to families registered by others
 }
 
 func NewThemeLoader(customPath string) *ThemeLoader {
 	return &ThemeLoader{
-		builtInThemes: GetBuiltInThemes(),
-		customPath:    customPath,
+		customPath: customPath,
 	}
 }
 
-// LoadAllThemes loads built-in and custom themes
+// LoadAllThemes loads built-in and custom themes. Custom themes that fail
+// to load are reported in the error; the other themes are still returned.
 func (tl *ThemeLoader) LoadAllThemes() ([]Theme, error) {
-	themes := make([]Theme, len(tl.builtInThemes))
-	copy(themes, tl.builtInThemes)
+	baseThemes, err := tl.LoadBaseThemes()
+	return FlattenBaseThemes(baseThemes), err
+}
 
-	// Load custom themes
-	customThemes, err := tl.loadCustomThemes()
-	if err != nil {
-		// Don't fail if custom themes can't be loaded
-		fmt.Printf("Warning: Could not load custom themes: %v\n", err)
-	} else {
-		themes = append(themes, customThemes...)
+// LoadBaseThemes loads built-in and custom theme families.
+// Standalone custom themes are returned as single-variant families.
+// Custom themes that fail to load are reported in the error; the other
+// families are still returned.
+func (tl *ThemeLoader) LoadBaseThemes() ([]BaseTheme, error) {
+	r := NewBuiltInRegistry()
+	err := tl.LoadInto(r)
+	return r.Families(), err
+}
+
+// LoadInto loads custom themes and families and registers them in r, after
+// the themes already there. Custom themes may extend any registered theme,
+// and a custom family named like a registered one, such as a built-in, adds
+// its variants to it. Themes registered by a previous LoadInto are removed
+// first, so calling it again reloads the themes from disk. Files that fail
+// to load are reported in the error; the others are still registered.
+func (tl *ThemeLoader) LoadInto(r *Registry) error {
+	for _, name := range tl.loaded {
+		// Ignore families someone else already unregistered
+		_ = r.Unregister(name)
+	}
+	for _, family := range tl.joined {
+		var names []string
+		for _, v := range family.Variants {
+			names = append(names, v.FullName)
+		}
+		_ = r.RemoveVariants(family.Name, names...)
 	}
+	tl.loaded, tl.joined = nil, nil
 
-	return themes, nil
-}
+	customThemes, errs := tl.loadCustomThemes(r.Themes())
 
-func (tl *ThemeLoader) loadCustomThemes() ([]Theme, error) {
-	var themes []Theme
+	for _, family := range customThemes {
+		if _, ok := r.Family(family.Name); ok {
+			if err := r.AddVariants(family.Name, family.Variants...); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			tl.joined = append(tl.joined, family)
+			continue
+		}
+		if err := r.Register(family); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tl.loaded = append(tl.loaded, family.Name)
+	}
+	return errors.Join(errs...)
+}
 
+// loadCustomThemes reads the custom themes directory, resolving Extends
+// against the given registered themes and the custom themes themselves, and
+// returns the errors of the files and themes that failed to load
+func (tl *ThemeLoader) loadCustomThemes(registered []Theme) ([]BaseTheme, []error) {
 	// Create custom themes directory if it doesn't exist
 	if err := os.MkdirAll(tl.customPath, 0755); err != nil {
-		return themes, err
+		return nil, []error{err}
 	}
 
 	// Read all files in custom themes directory
 	entries, err := os.ReadDir(tl.customPath)
 	if err != nil {
-		return themes, err
+		return nil, []error{err}
 	}
 
+	var set customThemeSet
+	var errs []error
 	for _, entry := range entries {
+		path := filepath.Join(tl.customPath, entry.Name())
+
+		// Subdirectories hold theme families (family.yaml plus one file per variant)
 		if entry.IsDir() {
+			if err := set.addFamilyDir(tl, path); err != nil {
+				errs = append(errs, err)
+			}
 			continue
 		}
 
-		filePath := filepath.Join(tl.customPath, entry.Name())
-		ext := strings.ToLower(filepath.Ext(entry.Name()))
-
-		var theme Theme
-		var loadErr error
-
-		switch ext {
-		case ".json":
-			theme, loadErr = tl.loadJSONTheme(filePath)
-		case ".yaml", ".yml":
-			theme, loadErr = tl.loadYAMLTheme(filePath)
-		case ".toml":
-			theme, loadErr = tl.loadTOMLTheme(filePath)
-		default:
+		if !isThemeFile(path) || isFamilyFile(path) {
 			continue // Skip unsupported formats
 		}
 
+		file, loadErr := tl.loadThemeFile(path)
 		if loadErr != nil {
-			fmt.Printf("Warning: Failed to load theme %s: %v\n", entry.Name(), loadErr)
+			errs = append(errs, loadErr)
 			continue
 		}
-
-		themes = append(themes, theme)
+		set.addFile(file)
 	}
 
-	return themes, nil
+	families, resolveErrs := set.resolve(registered)
+	return families, append(errs, resolveErrs...)
 }
 
-func (tl *ThemeLoader) loadJSONTheme(path string) (Theme, error) {
-	var theme Theme
+// isThemeFile reports whether the file has an extension the loader can read
+func isThemeFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml", ".toml":
+		return true
+	}
+	return false
+}
 
+// loadThemeFile parses a theme or single-file family based on its extension
+// and validates its colors. Errors name the file, and color errors also name
+// the field and offending value.
+func (tl *ThemeLoader) loadThemeFile(path string) (themeFile, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return theme, err
+		return themeFile{}, err
 	}
+	return parseThemeFile(path, data)
+}
 
-	if err := json.Unmarshal(data, &theme); err != nil {
-		return theme, err
+// parseThemeFile is loadThemeFile for data already read from path
+func parseThemeFile(path string, data []byte) (themeFile, error) {
+	var file themeFile
+	if err := decodeThemeData(path, data, &file); err != nil {
+		return file, err
 	}
 
-	return theme, nil
-}
+	if file.Name == "" {
+		return file, fmt.Errorf("%s: missing theme name", path)
+	}
+	if err := file.normalizeAppearance(path); err != nil {
+		return file, err
+	}
+
+	if len(file.Variants) == 0 {
+		// Themes extending another theme may leave colors unset to inherit them
+		if err := file.Colors.normalize(path, file.Extends != ""); err != nil {
+			return file, err
+		}
+		return file, nil
+	}
 
-func (tl *ThemeLoader) loadYAMLTheme(path string) (Theme, error) {
-	var theme Theme
+	return file, file.normalizeVariants(path)
+}
 
+// decodeThemeFile decodes a JSON, YAML or TOML file into v based on its extension.
+// Keys are matched to the schema first, so legacy spellings such as
+// "brightBlack" decode the same way in every format; unknown keys are ignored.
+func decodeThemeFile(path string, v any) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return theme, err
+		return err
 	}
+	return decodeThemeData(path, data, v)
+}
 
-	if err := yaml.Unmarshal(data, &theme); err != nil {
-		return theme, err
+// decodeThemeData is decodeThemeFile for data already read from path
+func decodeThemeData(path string, data []byte, v any) error {
+	tree, _, err := parseThemeTree(path, data)
+	if err != nil {
+		return err
+	}
+
+	data, err = json.Marshal(tree)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
 
-	return theme, nil
+	return nil
 }
 
-func (tl *ThemeLoader) loadTOMLTheme(path string) (Theme, error) {
-	var theme Theme
+// SaveCustomTheme saves a theme to the custom themes directory
+func (tl *ThemeLoader) SaveCustomTheme(theme Theme, format string) error {
+	if err := theme.Normalize(); err != nil {
+		return fmt.Errorf("invalid theme %q: %w", theme.Name, err)
+	}
+	return tl.saveCustomFile(theme.Name, theme, format)
+}
 
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return theme, err
+// SaveCustomFamily saves a theme family as a single file with its variants
+// to the custom themes directory
+func (tl *ThemeLoader) SaveCustomFamily(family BaseTheme, format string) error {
+	doc := familyDocument{
+		Name:        family.Name,
+		Description: family.Description,
+		Author:      family.Author,
+		License:     family.License,
+		Homepage:    family.Homepage,
+		Tags:        family.Tags,
+	}
+	for _, v := range family.Variants {
+		t := v.Theme(family)
+		if err := t.Normalize(); err != nil {
+			return fmt.Errorf("invalid theme %q: %w", t.Name, err)
+		}
+		doc.Variants = append(doc.Variants, variantDocument{
+			Name:        v.Name,
+			DisplayName: v.DisplayName,
+			FullName:    v.FullName,
+			Appearance:  v.Appearance,
+			Tags:        v.Tags,
+			Colors:      t.Colors,
+			Source:      v.Source,
+			Wallpaper:   v.Wallpaper,
+
+			SynthesizeBrights: v.SynthesizeBrights,
+		})
+	}
+	return tl.saveCustomFile(family.Name, doc, format)
+}
+
+// SaveCustomVariant saves a theme as a variant of a family: a file in the
+// family's directory of the custom themes directory, which is created with a
+// family file naming it when missing. The family may be a built-in one.
+func (tl *ThemeLoader) SaveCustomVariant(family string, theme Theme, format string) error {
+	if strings.TrimSpace(family) == "" {
+		return fmt.Errorf("no family given for variant %q", theme.Name)
+	}
+	if err := theme.Normalize(); err != nil {
+		return fmt.Errorf("invalid theme %q: %w", theme.Name, err)
 	}
 
-	// Use the BurntSushi/toml package
-	if err := toml.Unmarshal(data, &theme); err != nil {
-		return theme, err
+	dir := filepath.Join(tl.customPath, SanitizeFileName(family))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create family directory: %w", err)
+	}
+	if !hasFamilyFile(dir) {
+		data, err := yaml.Marshal(map[string]string{"name": family})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, familyFileNames[0]), data, 0644); err != nil {
+			return fmt.Errorf("failed to write family file: %w", err)
+		}
 	}
+	return NewThemeLoader(dir).saveCustomFile(theme.Name, theme, format)
+}
 
-	return theme, nil
+// hasFamilyFile reports whether dir holds a family metadata file
+func hasFamilyFile(dir string) bool {
+	for _, name := range familyFileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
-// SaveCustomTheme saves a theme to the custom themes directory
-func (tl *ThemeLoader) SaveCustomTheme(theme Theme, format string) error {
+// saveCustomFile writes v to the custom themes directory in a file named after name
+func (tl *ThemeLoader) saveCustomFile(name string, v any, format string) error {
 	// Ensure directory exists
 	if err := os.MkdirAll(tl.customPath, 0755); err != nil {
 		return fmt.Errorf("failed to create themes directory: %w", err)
 	}
 
-	var data []byte
-	var err error
-	var ext string
-
-	switch strings.ToLower(format) {
-	case "json":
-		data, err = json.MarshalIndent(theme, "", "  ")
-		ext = ".json"
-	case "yaml", "yml":
-		data, err = yaml.Marshal(theme)
-		ext = ".yaml"
-	default:
-		return fmt.Errorf("unsupported format: %s", format)
-	}
-
+	data, err := marshalDocument(v, format)
 	if err != nil {
-		return fmt.Errorf("failed to marshal theme: %w", err)
+		return err
+	}
+	ext := "." + strings.ToLower(format)
+	if ext == ".yml" {
+		ext = ".yaml"
 	}
 
-	filename := SanitizeFileName(theme.Name) + ext
+	filename := SanitizeFileName(name) + ext
 	filePath := filepath.Join(tl.customPath, filename)
 
 	if err := os.WriteFile(filePath, data, 0644); err != nil {
@@ -169,25 +303,76 @@ func (tl *ThemeLoader) SaveCustomTheme(theme Theme, format string) error {
 	return nil
 }
 
-// ExportTheme exports a built-in theme to a file
-func (tl *ThemeLoader) ExportTheme(theme Theme, outputPath string) error {
-	ext := strings.ToLower(filepath.Ext(outputPath))
-	format := strings.TrimPrefix(ext, ".")
+// familyDocument is the canonical form of a single-file family
+type familyDocument struct {
+	Name        string            `json:"name" yaml:"name" toml:"name"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Author      string            `json:"author,omitempty" yaml:"author,omitempty" toml:"author,omitempty"`
+	License     string            `json:"license,omitempty" yaml:"license,omitempty" toml:"license,omitempty"`
+	Homepage    string            `json:"homepage,omitempty" yaml:"homepage,omitempty" toml:"homepage,omitempty"`
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`
+	Variants    []variantDocument `json:"variants" yaml:"variants" toml:"variants"`
+}
+
+// variantDocument is a variant of a familyDocument
+type variantDocument struct {
+	Name        string       `json:"name" yaml:"name" toml:"name"`
+	DisplayName string       `json:"display_name,omitempty" yaml:"display_name,omitempty" toml:"display_name,omitempty"`
+	FullName    string       `json:"full_name,omitempty" yaml:"full_name,omitempty" toml:"full_name,omitempty"`
+	Appearance  Appearance   `json:"appearance,omitempty" yaml:"appearance,omitempty" toml:"appearance,omitempty"`
+	Tags        []string     `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`
+	Colors      ColorPalette `json:"colors" yaml:"colors" toml:"colors"`
+	Source      *ThemeSource `json:"source,omitempty" yaml:"source,omitempty" toml:"source,omitempty"`
+	Wallpaper   string       `json:"wallpaper,omitempty" yaml:"wallpaper,omitempty" toml:"wallpaper,omitempty"`
+
+	SynthesizeBrights *bool `json:"synthesize_brights,omitempty" yaml:"synthesize_brights,omitempty" toml:"synthesize_brights,omitempty"`
+}
+
+// ThemeFormats are the file formats themes can be saved and exported in
+var ThemeFormats = []string{"toml", "yaml", "json"}
 
+// MarshalTheme encodes a theme in the canonical schema as JSON, YAML or TOML
+func MarshalTheme(theme Theme, format string) ([]byte, error) {
+	return marshalDocument(theme, format)
+}
+
+// marshalDocument encodes a theme or family document as JSON, YAML or TOML
+func marshalDocument(v any, format string) ([]byte, error) {
 	var data []byte
 	var err error
 
-	switch format {
+	switch strings.ToLower(format) {
 	case "json":
-		data, err = json.MarshalIndent(theme, "", "  ")
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
 	case "yaml", "yml":
-		data, err = yaml.Marshal(theme)
+		data, err = yaml.Marshal(v)
+	case "toml":
+		var buf bytes.Buffer
+		encoder := toml.NewEncoder(&buf)
+		encoder.Indent = ""
+		err = encoder.Encode(v)
+		data = buf.Bytes()
 	default:
-		return fmt.Errorf("unsupported format: %s (use .json or .yaml)", format)
+		return nil, fmt.Errorf("unsupported format: %s (use .toml, .yaml or .json)", format)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to marshal theme: %w", err)
+		return nil, fmt.Errorf("failed to marshal theme: %w", err)
+	}
+	return data, nil
+}
+
+// ExportTheme exports a theme to a file in the format given by its extension
+func (tl *ThemeLoader) ExportTheme(theme Theme, outputPath string) error {
+	if err := theme.Normalize(); err != nil {
+		return fmt.Errorf("invalid theme %q: %w", theme.Name, err)
+	}
+
+	format := strings.TrimPrefix(filepath.Ext(outputPath), ".")
+	data, err := MarshalTheme(theme, format)
+	if err != nil {
+		return err
 	}
 
 	if err := os.WriteFile(outputPath, data, 0644); err != nil {
@@ -201,6 +386,10 @@ func (tl *ThemeLoader) ExportTheme(theme Theme, outputPath string) error {
 type BaseTheme struct {
 	Name        string
 	Description string
+	Author      string
+	License     string
+	Homepage    string
+	Tags        []string // Shared by all variants
 	Variants    []ThemeVariant
 }
 
@@ -210,245 +399,54 @@ type ThemeVariant struct {
 	DisplayName string // e.g., "Latte (Light)", "Moon (Dark)"
 	FullName    string // e.g., "Catppuccin Latte", "Rose Pine Moon", "Nord"
 	Colors      ColorPalette
+	Appearance  Appearance
+	Tags        []string // In addition to the family's tags
+	Source      *ThemeSource
+	Wallpaper   string
+	Aliases     []string // Other names the theme can be looked up by
+
+	SynthesizeBrights *bool // See Theme
+
+	// Set for custom variants that extend another theme (see Theme)
+	Extends   string
+	Base      string
+	Overrides []string
 }
 
-// GetBuiltInBaseThemes returns all built-in base themes with their variants
-func GetBuiltInBaseThemes() []BaseTheme {
-	return []BaseTheme{
-		{
-			Name:        "Nord",
-			Description: "An arctic, north-bluish color palette",
-			Variants: []ThemeVariant{
-				{
-					Name:        "Nord",
-					DisplayName: "Nord",
-					FullName:    "Nord",
-					Colors: ColorPalette{
-						Background:    "#2e3440", // nord0
-						Foreground:    "#d8dee9", // nord4
-						Black:         "#3b4252", // nord1
-						Red:           "#bf616a", // nord11
-						Green:         "#a3be8c", // nord14
-						Yellow:        "#ebcb8b", // nord13
-						Blue:          "#81a1c1", // nord9
-						Magenta:       "#b48ead", // nord15
-						Cyan:          "#88c0d0", // nord8
-						White:         "#e5e9f0", // nord5
-						BrightBlack:   "#4c566a", // nord3
-						BrightRed:     "#bf616a", // nord11
-						BrightGreen:   "#a3be8c", // nord14
-						BrightYellow:  "#ebcb8b", // nord13
-						BrightBlue:    "#81a1c1", // nord9
-						BrightMagenta: "#b48ead", // nord15
-						BrightCyan:    "#8fbcbb", // nord7
-						BrightWhite:   "#eceff4", // nord6
-					},
-				},
-			},
-		},
-		{
-			Name:        "Catppuccin",
-			Description: "Soothing pastel theme for the high-spirited!",
-			Variants: []ThemeVariant{
-				{
-					Name:        "Latte",
-					DisplayName: "Latte (Light)",
-					FullName:    "Catppuccin Latte",
-					Colors: ColorPalette{
-						Background:    "#eff1f5", // Base
-						Foreground:    "#4c4f69", // Text
-						Black:         "#5c5f77", // Subtext 1
-						Red:           "#d20f39", // Red
-						Green:         "#40a02b", // Green
-						Yellow:        "#df8e1d", // Yellow
-						Blue:          "#1e66f5", // Blue
-						Magenta:       "#ea76cb", // Pink
-						Cyan:          "#179299", // Teal
-						White:         "#4c4f69", // Text
-						BrightBlack:   "#6c6f85", // Subtext 0
-						BrightRed:     "#d20f39", // Red
-						BrightGreen:   "#40a02b", // Green
-						BrightYellow:  "#df8e1d", // Yellow
-						BrightBlue:    "#1e66f5", // Blue
-						BrightMagenta: "#ea76cb", // Pink
-						BrightCyan:    "#179299", // Teal
-						BrightWhite:   "#4c4f69", // Text
-					},
-				},
-				{
-					Name:        "Frappe",
-					DisplayName: "Frappé (Dark)",
-					FullName:    "Catppuccin Frappe",
-					Colors: ColorPalette{
-						Background:    "#303446", // Base
-						Foreground:    "#c6d0f5", // Text
-						Black:         "#51576d", // Surface 1
-						Red:           "#e78284", // Red
-						Green:         "#a6d189", // Green
-						Yellow:        "#e5c890", // Yellow
-						Blue:          "#8caaee", // Blue
-						Magenta:       "#f4b8e4", // Pink
-						Cyan:          "#81c8be", // Teal
-						White:         "#b5bfe2", // Subtext 1
-						BrightBlack:   "#626880", // Surface 2
-						BrightRed:     "#e78284", // Red
-						BrightGreen:   "#a6d189", // Green
-						BrightYellow:  "#e5c890", // Yellow
-						BrightBlue:    "#8caaee", // Blue
-						BrightMagenta: "#f4b8e4", // Pink
-						BrightCyan:    "#81c8be", // Teal
-						BrightWhite:   "#a5adce", // Subtext 0
-					},
-				},
-				{
-					Name:        "Macchiato",
-					DisplayName: "Macchiato (Dark)",
-					FullName:    "Catppuccin Macchiato",
-					Colors: ColorPalette{
-						Background:    "#24273a", // Base
-						Foreground:    "#cad3f5", // Text
-						Black:         "#494d64", // Surface 1
-						Red:           "#ed8796", // Red
-						Green:         "#a6da95", // Green
-						Yellow:        "#eed49f", // Yellow
-						Blue:          "#8aadf4", // Blue
-						Magenta:       "#f5bde6", // Pink
-						Cyan:          "#8bd5ca", // Teal
-						White:         "#b8c0e0", // Subtext 1
-						BrightBlack:   "#5b6078", // Surface 2
-						BrightRed:     "#ed8796", // Red
-						BrightGreen:   "#a6da95", // Green
-						BrightYellow:  "#eed49f", // Yellow
-						BrightBlue:    "#8aadf4", // Blue
-						BrightMagenta: "#f5bde6", // Pink
-						BrightCyan:    "#8bd5ca", // Teal
-						BrightWhite:   "#a5adcb", // Subtext 0
-					},
-				},
-				{
-					Name:        "Mocha",
-					DisplayName: "Mocha (Dark)",
-					FullName:    "Catppuccin Mocha",
-					Colors: ColorPalette{
-						Background:    "#1e1e2e", // Base
-						Foreground:    "#cdd6f4", // Text
-						Black:         "#45475a", // Surface 1
-						Red:           "#f38ba8", // Red
-						Green:         "#a6e3a1", // Green
-						Yellow:        "#f9e2af", // Yellow
-						Blue:          "#89b4fa", // Blue
-						Magenta:       "#f5c2e7", // Pink
-						Cyan:          "#94e2d5", // Teal
-						White:         "#bac2de", // Subtext 1
-						BrightBlack:   "#585b70", // Surface 2
-						BrightRed:     "#f38ba8", // Red
-						BrightGreen:   "#a6e3a1", // Green
-						BrightYellow:  "#f9e2af", // Yellow
-						BrightBlue:    "#89b4fa", // Blue
-						BrightMagenta: "#f5c2e7", // Pink
-						BrightCyan:    "#94e2d5", // Teal
-						BrightWhite:   "#a6adc8", // Subtext 0
-					},
-				},
-			},
-		},
-		{
-			Name:        "Rose Pine",
-			Description: "All natural pine, faux fur and a bit of soho vibes",
-			Variants: []ThemeVariant{
-				{
-					Name:        "Main",
-					DisplayName: "Main (Dark)",
-					FullName:    "Rose Pine",
-					Colors: ColorPalette{
-						Background:    "#191724", // Base
-						Foreground:    "#e0def4", // Text
-						Black:         "#26233a", // Overlay
-						Red:           "#eb6f92", // Love
-						Green:         "#9ccfd8", // Foam
-						Yellow:        "#f6c177", // Gold
-						Blue:          "#31748f", // Pine
-						Magenta:       "#c4a7e7", // Iris
-						Cyan:          "#ebbcba", // Rose
-						White:         "#e0def4", // Text
-						BrightBlack:   "#6e6a86", // Muted
-						BrightRed:     "#eb6f92", // Love
-						BrightGreen:   "#9ccfd8", // Foam
-						BrightYellow:  "#f6c177", // Gold
-						BrightBlue:    "#31748f", // Pine
-						BrightMagenta: "#c4a7e7", // Iris
-						BrightCyan:    "#ebbcba", // Rose
-						BrightWhite:   "#e0def4", // Text
-					},
-				},
-				{
-					Name:        "Moon",
-					DisplayName: "Moon (Dark)",
-					FullName:    "Rose Pine Moon",
-					Colors: ColorPalette{
-						Background:    "#232136", // Base
-						Foreground:    "#e0def4", // Text
-						Black:         "#393552", // Overlay
-						Red:           "#eb6f92", // Love
-						Green:         "#9ccfd8", // Foam
-						Yellow:        "#f6c177", // Gold
-						Blue:          "#3e8fb0", // Pine
-						Magenta:       "#c4a7e7", // Iris
-						Cyan:          "#ea9a97", // Rose
-						White:         "#e0def4", // Text
-						BrightBlack:   "#6e6a86", // Muted
-						BrightRed:     "#eb6f92", // Love
-						BrightGreen:   "#9ccfd8", // Foam
-						BrightYellow:  "#f6c177", // Gold
-						BrightBlue:    "#3e8fb0", // Pine
-						BrightMagenta: "#c4a7e7", // Iris
-						BrightCyan:    "#ea9a97", // Rose
-						BrightWhite:   "#e0def4", // Text
-					},
-				},
-				{
-					Name:        "Dawn",
-					DisplayName: "Dawn (Light)",
-					FullName:    "Rose Pine Dawn",
-					Colors: ColorPalette{
-						Background:    "#faf4ed", // Base
-						Foreground:    "#575279", // Text
-						Black:         "#f2e9e1", // Overlay
-						Red:           "#b4637a", // Love
-						Green:         "#56949f", // Foam
-						Yellow:        "#ea9d34", // Gold
-						Blue:          "#286983", // Pine
-						Magenta:       "#907aa9", // Iris
-						Cyan:          "#d7827e", // Rose
-						White:         "#575279", // Text
-						BrightBlack:   "#9893a5", // Muted
-						BrightRed:     "#b4637a", // Love
-						BrightGreen:   "#56949f", // Foam
-						BrightYellow:  "#ea9d34", // Gold
-						BrightBlue:    "#286983", // Pine
-						BrightMagenta: "#907aa9", // Iris
-						BrightCyan:    "#d7827e", // Rose
-						BrightWhite:   "#575279", // Text
-					},
-				},
-			},
-		},
+// Theme returns the variant as a standalone theme of the given family
+func (v ThemeVariant) Theme(family BaseTheme) Theme {
+	t := Theme{
+		Name:        v.FullName,
+		Description: family.Description,
+		Aliases:     v.Aliases,
+		Extends:     v.Extends,
+		Colors:      v.Colors,
+		Author:      family.Author,
+		License:     family.License,
+		Homepage:    family.Homepage,
+		Appearance:  v.Appearance,
+		Tags:        mergeTags(family.Tags, v.Tags),
+		Source:      v.Source,
+		Wallpaper:   v.Wallpaper,
+		Base:        v.Base,
+		Overrides:   v.Overrides,
+
+		SynthesizeBrights: v.SynthesizeBrights,
 	}
+	if t.Appearance == "" {
+		t.Appearance = AppearanceOf(t.Colors.Background)
+	}
+	return t
 }
 
-var (
-	// Cache for built-in themes to avoid rebuilding on every call
-	builtInThemesCache []Theme
-)
-
-// GetBuiltInThemes returns all built-in themes (flattened from base themes)
+// GetBuiltInThemes returns all built-in themes (flattened from base themes).
+// Every call returns new themes, so callers may modify them.
 func GetBuiltInThemes() []Theme {
-	// Return cached themes if available
-	if builtInThemesCache != nil {
-		return builtInThemesCache
-	}
+	return FlattenBaseThemes(GetBuiltInBaseThemes())
+}
 
-	baseThemes := GetBuiltInBaseThemes()
+// FlattenBaseThemes returns one Theme per variant, in family order
+func FlattenBaseThemes(baseThemes []BaseTheme) []Theme {
 	// Pre-calculate total number of themes to avoid slice reallocations
 	totalThemes := 0
 	for _, baseTheme := range baseThemes {
@@ -458,15 +456,9 @@ func GetBuiltInThemes() []Theme {
 	themes := make([]Theme, 0, totalThemes)
 	for _, baseTheme := range baseThemes {
 		for _, variant := range baseTheme.Variants {
-			themes = append(themes, Theme{
-				Name:        variant.FullName,
-				Description: baseTheme.Description,
-				Colors:      variant.Colors,
-			})
+			themes = append(themes, variant.Theme(baseTheme))
 		}
 	}
 
-	// Cache the result
-	builtInThemesCache = themes
 	return themes
 }
@@ -1,32 +1,33 @@
 package theme
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
-// TestGetBuiltInThemesCaching verifies that built-in themes are cached
-func TestGetBuiltInThemesCaching(t *testing.T) {
-	// Clear cache for testing
-	builtInThemesCache = nil
-
-	// First call should populate the cache
+// TestGetBuiltInThemesReturnsCopies verifies that callers can't modify the built-in themes
+func TestGetBuiltInThemesReturnsCopies(t *testing.T) {
 	themes1 := GetBuiltInThemes()
 	if len(themes1) == 0 {
 		t.Fatal("Expected at least one built-in theme")
 	}
 
-	// Second call should return the same cached slice
-	themes2 := GetBuiltInThemes()
+	// Modifying the returned themes must not affect later calls
+	themes1[0].Name = "Modified"
+	themes1[0].Tags[0] = "modified"
 
-	// Verify the slices are identical (same memory address)
-	if &themes1[0] != &themes2[0] {
-		t.Error("Expected themes to be cached and return the same slice")
+	themes2 := GetBuiltInThemes()
+	if themes2[0].Name == "Modified" || themes2[0].Tags[0] == "modified" {
+		t.Error("Expected modifications to the returned themes not to leak into the built-ins")
 	}
 
-	// Verify we have the expected number of themes (Nord + 4 Catppuccin + 3 Rose Pine = 8)
-	expectedCount := 8
-	if len(themes1) != expectedCount {
-		t.Errorf("Expected %d themes, got %d", expectedCount, len(themes1))
+	// Verify we have the expected number of themes (Nord 1, Catppuccin 4, Rose Pine 3,
+	// Gruvbox 2, Tokyo Night 3, Dracula 1, Solarized 2, Everforest 2, Kanagawa 3, One Dark 1)
+	expectedCount := 22
+	if len(themes2) != expectedCount {
+		t.Errorf("Expected %d themes, got %d", expectedCount, len(themes2))
 	}
 }
 
@@ -53,22 +54,183 @@ func TestGetBuiltInThemesContent(t *testing.T) {
 
 // BenchmarkGetBuiltInThemes benchmarks the performance of getting built-in themes
 func BenchmarkGetBuiltInThemes(b *testing.B) {
-	// Clear cache before benchmark
-	builtInThemesCache = nil
-
-	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		GetBuiltInThemes()
 	}
 }
 
-// BenchmarkGetBuiltInThemesCached benchmarks cached theme retrieval
-func BenchmarkGetBuiltInThemesCached(b *testing.B) {
-	// Populate cache
-	GetBuiltInThemes()
+// TestLoadCustomThemeValidatesColors verifies custom theme files are normalized and validated
+func TestLoadCustomThemeValidatesColors(t *testing.T) {
+	dir := t.TempDir()
+	loader := NewThemeLoader(dir)
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		GetBuiltInThemes()
+	valid := `{"name": "Valid", "colors": {
+		"background": "rgb(30, 30, 46)", "foreground": "#CDD6F4",
+		"black": "#45475a", "red": "red", "green": "#a6e3a1", "yellow": "#f9e2af",
+		"blue": "#89b4fa", "magenta": "#f5c2e7", "cyan": "#94e2d5", "white": "#bac2de",
+		"brightBlack": "#585b70", "brightRed": "#f38ba8", "brightGreen": "#a6e3a1",
+		"brightYellow": "#f9e2af", "brightBlue": "#89b4fa", "brightMagenta": "#f5c2e7",
+		"brightCyan": "#94e2d5", "brightWhite": "hsl(228, 24%, 72%)"}}`
+	validPath := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(validPath, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	theme, err := loader.loadThemeFile(validPath)
+	if err != nil {
+		t.Fatalf("Expected valid theme to load, got: %v", err)
+	}
+	if theme.Colors.Background != "#1e1e2e" || theme.Colors.Red != "#ff0000" || theme.Colors.Foreground != "#cdd6f4" {
+		t.Errorf("Expected colors to be normalized, got %+v", theme.Colors)
+	}
+
+	invalidPath := filepath.Join(dir, "typo.json")
+	invalid := strings.Replace(valid, `"red": "red"`, `"red": "#f38ba"`, 1)
+	if err := os.WriteFile(invalidPath, []byte(invalid), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = loader.loadThemeFile(invalidPath)
+	if err == nil {
+		t.Fatal("Expected typo in color to fail loading")
+	}
+	if want := invalidPath + `: colors.red: invalid color "#f38ba"`; !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error to contain %q, got: %v", want, err)
+	}
+}
+
+// TestLoadCustomThemeExtends verifies extends chains, overrides and cycle detection
+func TestLoadCustomThemeExtends(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"darker.yaml":  "name: Mocha Darker\nextends: Catppuccin Mocha\ncolors:\n  background: \"#11111b\"\n",
+		"same.json":    `{"name": "Mocha Same", "extends": "catppuccin mocha", "colors": {"foreground": "#CDD6F4"}}`,
+		"accent.toml":  "Name = \"Mocha Darker Accent\"\nExtends = \"Mocha Darker\"\n[Colors]\nBlue = \"#74c7ec\"\n",
+		"cycle-a.yaml": "name: Cycle A\nextends: Cycle B\n",
+		"cycle-b.yaml": "name: Cycle B\nextends: Cycle A\n",
+		"orphan.yaml":  "name: Orphan\nextends: Does Not Exist\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	themes, err := NewThemeLoader(dir).LoadAllThemes()
+	if err == nil || !strings.Contains(err.Error(), "extends cycle") || !strings.Contains(err.Error(), `extends unknown theme "Does Not Exist"`) {
+		t.Errorf("Expected the cycle and the orphan to be reported, got %v", err)
+	}
+
+	byName := make(map[string]Theme)
+	for _, theme := range themes {
+		byName[theme.Name] = theme
+	}
+
+	for _, name := range []string{"Cycle A", "Cycle B", "Orphan"} {
+		if _, ok := byName[name]; ok {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+
+	darker, ok := byName["Mocha Darker"]
+	if !ok {
+		t.Fatal("Expected Mocha Darker to load")
+	}
+	if darker.Colors.Background != "#11111b" || darker.Colors.Red != "#f38ba8" {
+		t.Errorf("Expected overridden background and inherited red, got %+v", darker.Colors)
+	}
+	if darker.Base != "Catppuccin Mocha" || len(darker.Overrides) != 1 || darker.Overrides[0] != "background" {
+		t.Errorf("Expected base Catppuccin Mocha with background override, got %q %v", darker.Base, darker.Overrides)
+	}
+	if darker.OfficialName() != "Mocha Darker" {
+		t.Errorf("Expected overridden theme to keep its own name, got %s", darker.OfficialName())
+	}
+
+	same := byName["Mocha Same"]
+	if same.OfficialName() != "Catppuccin Mocha" {
+		t.Errorf("Expected theme without visible overrides to resolve to Catppuccin Mocha, got %s", same.OfficialName())
+	}
+
+	accent := byName["Mocha Darker Accent"]
+	if accent.Base != "Catppuccin Mocha" || accent.Colors.Background != "#11111b" || accent.Colors.Blue != "#74c7ec" {
+		t.Errorf("Expected chained extends to resolve through Mocha Darker, got %q %+v", accent.Base, accent.Colors)
+	}
+	if len(accent.Overrides) != 2 {
+		t.Errorf("Expected 2 overrides relative to Catppuccin Mocha, got %v", accent.Overrides)
+	}
+}
+
+// testPaletteYAML returns a complete palette as a YAML colors block with the given background
+func testPaletteYAML(indent, background string) string {
+	var b strings.Builder
+	b.WriteString(indent + "colors:\n")
+	b.WriteString(indent + "  background: \"" + background + "\"\n")
+	for _, key := range []string{"foreground", "black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+		"brightblack", "brightred", "brightgreen", "brightyellow", "brightblue", "brightmagenta", "brightcyan", "brightwhite"} {
+		b.WriteString(indent + "  " + key + ": \"#808080\"\n")
+	}
+	return b.String()
+}
+
+// TestLoadCustomThemeFamilies verifies directory and single-file families become base themes with variants
+func TestLoadCustomThemeFamilies(t *testing.T) {
+	dir := t.TempDir()
+	familyDir := filepath.Join(dir, "monokai")
+	if err := os.MkdirAll(familyDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		filepath.Join(familyDir, "family.yaml"): "name: Monokai\ndescription: Classic\n",
+		filepath.Join(familyDir, "dark.yaml"):   "name: Monokai Dark\ndisplay_name: Dark (Dark)\n" + testPaletteYAML("", "#282828"),
+		filepath.Join(familyDir, "light.yaml"):  "name: Monokai Light\n" + testPaletteYAML("", "#fbf1c7"),
+		filepath.Join(dir, "neon.yaml"): "name: Neon\ndescription: City lights\nvariants:\n" +
+			"  - name: Night\n" + testPaletteYAML("    ", "#1a1b26") +
+			"  - name: Storm\n    extends: Neon Night\n    colors:\n      background: \"#24283b\"\n",
+		filepath.Join(dir, "single.yaml"): "name: Single\n" + testPaletteYAML("", "#000000"),
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	baseThemes, err := NewThemeLoader(dir).LoadBaseThemes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]BaseTheme)
+	for _, baseTheme := range baseThemes {
+		byName[baseTheme.Name] = baseTheme
+	}
+
+	monokai, ok := byName["Monokai"]
+	if !ok {
+		t.Fatal("Expected Monokai family from directory")
+	}
+	if monokai.Description != "Classic" || len(monokai.Variants) != 2 {
+		t.Fatalf("Expected 2 Monokai variants with family description, got %+v", monokai)
+	}
+	if v := monokai.Variants[0]; v.Name != "Dark" || v.DisplayName != "Dark (Dark)" || v.FullName != "Monokai Dark" {
+		t.Errorf("Unexpected first Monokai variant: %+v", v)
+	}
+
+	neon, ok := byName["Neon"]
+	if !ok || len(neon.Variants) != 2 {
+		t.Fatalf("Expected Neon family with 2 variants, got %+v", neon)
+	}
+	storm := neon.Variants[1]
+	if storm.FullName != "Neon Storm" || storm.Colors.Background != "#24283b" || storm.Colors.Red != "#808080" {
+		t.Errorf("Expected Neon Storm to inherit from Neon Night, got %+v", storm)
+	}
+
+	if single, ok := byName["Single"]; !ok || len(single.Variants) != 1 {
+		t.Errorf("Expected standalone theme as single-variant family, got %+v", single)
+	}
+
+	themes := FlattenBaseThemes(baseThemes)
+	if len(themes) != len(GetBuiltInThemes())+5 {
+		t.Errorf("Expected %d flattened themes, got %d", len(GetBuiltInThemes())+5, len(themes))
 	}
 }
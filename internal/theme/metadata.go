@@ -0,0 +1,199 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Appearance describes whether a theme is meant for light or dark mode
+type Appearance string
+
+const (
+	AppearanceDark  Appearance = "dark"
+	AppearanceLight Appearance = "light"
+)
+
+// ParseAppearance parses "light" or "dark" (case-insensitive); empty means unspecified
+func ParseAppearance(s string) (Appearance, error) {
+	switch Appearance(strings.ToLower(strings.TrimSpace(s))) {
+	case "":
+		return "", nil
+	case AppearanceDark:
+		return AppearanceDark, nil
+	case AppearanceLight:
+		return AppearanceLight, nil
+	default:
+		return "", fmt.Errorf("invalid appearance %q (use light or dark)", s)
+	}
+}
+
+// AppearanceOf classifies a background color by its luminance.
+// Invalid colors are treated as dark, the common case for terminal themes.
+func AppearanceOf(background string) Appearance {
+	if c, err := ParseColor(background); err == nil && c.IsLight() {
+		return AppearanceLight
+	}
+	return AppearanceDark
+}
+
+// ResolvedAppearance returns the declared appearance, or the one computed from the background
+func (t Theme) ResolvedAppearance() Appearance {
+	if t.Appearance != "" {
+		return t.Appearance
+	}
+	return AppearanceOf(t.Colors.Background)
+}
+
+// IsLight reports whether the theme is a light theme
+func (t Theme) IsLight() bool {
+	return t.ResolvedAppearance() == AppearanceLight
+}
+
+// Pair is a light and a dark theme for apps that follow the system appearance
+type Pair struct {
+	Light Theme
+	Dark  Theme
+}
+
+// NewPair orders two themes of opposite appearance into a Pair
+func NewPair(a, b Theme) (Pair, error) {
+	if a.IsLight() == b.IsLight() {
+		return Pair{}, fmt.Errorf("%q and %q are both %s themes", a.Name, b.Name, a.ResolvedAppearance())
+	}
+	if a.IsLight() {
+		return Pair{Light: a, Dark: b}, nil
+	}
+	return Pair{Light: b, Dark: a}, nil
+}
+
+// For returns the side of the pair for the appearance (dark unless light is requested)
+func (p Pair) For(appearance Appearance) Theme {
+	if appearance == AppearanceLight {
+		return p.Light
+	}
+	return p.Dark
+}
+
+// HasTag reports whether the theme has the tag (case-insensitive)
+func (t Theme) HasTag(tag string) bool {
+	for _, own := range t.Tags {
+		if strings.EqualFold(own, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeTags returns the tags of both lists without duplicates, in order
+func mergeTags(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	merged := make([]string, 0, len(a)+len(b))
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, tag := range append(append([]string{}, a...), b...) {
+		key := strings.ToLower(tag)
+		if !seen[key] {
+			seen[key] = true
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// Filter selects themes by tags, appearance and free-text search
+type Filter struct {
+	Tags       []string   // All tags must be present
+	Appearance Appearance // Empty matches both
+	Query      string     // Matched against name, description, author and tags
+}
+
+// ParseFilter parses a search query. The tokens "tag:<name>" and
+// "appearance:<light|dark>" (or just "light"/"dark") become filters, and the
+// remaining words form the free-text query.
+func ParseFilter(query string) (Filter, error) {
+	var f Filter
+	var words []string
+	for _, token := range strings.Fields(query) {
+		lower := strings.ToLower(token)
+		switch {
+		case strings.HasPrefix(lower, "tag:"):
+			f.Tags = append(f.Tags, token[len("tag:"):])
+		case strings.HasPrefix(lower, "appearance:"):
+			appearance, err := ParseAppearance(token[len("appearance:"):])
+			if err != nil {
+				return f, err
+			}
+			f.Appearance = appearance
+		case lower == string(AppearanceLight) || lower == string(AppearanceDark):
+			f.Appearance = Appearance(lower)
+		default:
+			words = append(words, token)
+		}
+	}
+	f.Query = strings.Join(words, " ")
+	return f, nil
+}
+
+// IsEmpty reports whether the filter matches every theme
+func (f Filter) IsEmpty() bool {
+	return len(f.Tags) == 0 && f.Appearance == "" && strings.TrimSpace(f.Query) == ""
+}
+
+// String formats the filter in the query syntax understood by ParseFilter
+func (f Filter) String() string {
+	var parts []string
+	for _, tag := range f.Tags {
+		parts = append(parts, "tag:"+tag)
+	}
+	if f.Appearance != "" {
+		parts = append(parts, "appearance:"+string(f.Appearance))
+	}
+	if f.Query != "" {
+		parts = append(parts, f.Query)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Matches reports whether the theme passes the filter
+func (f Filter) Matches(t Theme) bool {
+	if f.Appearance != "" && t.ResolvedAppearance() != f.Appearance {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !t.HasTag(tag) {
+			return false
+		}
+	}
+
+	haystack := strings.ToLower(strings.Join(append([]string{t.Name, t.Description, t.Author}, t.Tags...), " "))
+	for _, word := range strings.Fields(strings.ToLower(f.Query)) {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterBaseThemes returns the families with only the variants that pass the filter.
+// Families without any matching variant are left out.
+func FilterBaseThemes(baseThemes []BaseTheme, f Filter) []BaseTheme {
+	if f.IsEmpty() {
+		return baseThemes
+	}
+
+	var filtered []BaseTheme
+	for _, baseTheme := range baseThemes {
+		var variants []ThemeVariant
+		for _, variant := range baseTheme.Variants {
+			if f.Matches(variant.Theme(baseTheme)) {
+				variants = append(variants, variant)
+			}
+		}
+		if len(variants) > 0 {
+			baseTheme.Variants = variants
+			filtered = append(filtered, baseTheme)
+		}
+	}
+	return filtered
+}
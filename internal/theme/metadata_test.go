@@ -0,0 +1,133 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestBuiltInAppearanceMatchesLuminance verifies declared appearances agree with the backgrounds
+func TestBuiltInAppearanceMatchesLuminance(t *testing.T) {
+	for _, theme := range GetBuiltInThemes() {
+		if theme.Appearance == "" {
+			t.Errorf("Theme %s has no appearance", theme.Name)
+		}
+		if computed := AppearanceOf(theme.Colors.Background); computed != theme.Appearance {
+			t.Errorf("Theme %s declares %s but background is %s", theme.Name, theme.Appearance, computed)
+		}
+		if theme.Author == "" || theme.License == "" || len(theme.Tags) == 0 {
+			t.Errorf("Theme %s is missing metadata", theme.Name)
+		}
+	}
+}
+
+// TestParseFilter verifies tag and appearance tokens are extracted from a query
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter("tag:pastel Mocha appearance:DARK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Tags) != 1 || f.Tags[0] != "pastel" || f.Appearance != AppearanceDark || f.Query != "Mocha" {
+		t.Errorf("Unexpected filter: %+v", f)
+	}
+
+	if _, err := ParseFilter("appearance:dim"); err == nil {
+		t.Error("Expected invalid appearance to be rejected")
+	}
+}
+
+// TestFilterBaseThemes verifies variants are filtered and empty families dropped
+func TestFilterBaseThemes(t *testing.T) {
+	light := FilterBaseThemes(GetBuiltInBaseThemes(), Filter{Appearance: AppearanceLight})
+	if len(light) != 7 {
+		t.Fatalf("Expected 7 families with light variants, got %d", len(light))
+	}
+	for _, baseTheme := range light {
+		if len(baseTheme.Variants) != 1 {
+			t.Errorf("Expected only the light variant of %s, got %d", baseTheme.Name, len(baseTheme.Variants))
+		}
+	}
+
+	tagged := FilterBaseThemes(GetBuiltInBaseThemes(), Filter{Tags: []string{"ARCTIC"}})
+	if len(tagged) != 1 || tagged[0].Name != "Nord" {
+		t.Errorf("Expected tag filter to match Nord only, got %+v", tagged)
+	}
+
+	searched := FlattenBaseThemes(FilterBaseThemes(GetBuiltInBaseThemes(), Filter{Query: "moon"}))
+	if len(searched) != 1 || searched[0].Name != "Rose Pine Moon" {
+		t.Errorf("Expected search to match Rose Pine Moon only, got %+v", searched)
+	}
+}
+
+// TestCustomThemeMetadata verifies metadata is parsed and appearance is computed when absent
+func TestCustomThemeMetadata(t *testing.T) {
+	dir := t.TempDir()
+	content := "name: Paper\nauthor: Jane\nlicense: MIT\nhomepage: https://example.com\ntags: [minimal, print]\n" +
+		testPaletteYAML("", "#fafafa")
+	if err := os.WriteFile(filepath.Join(dir, "paper.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := "name: Bad\nappearance: dim\n" + testPaletteYAML("", "#000000")
+	if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(invalid), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	themes, err := NewThemeLoader(dir).LoadAllThemes()
+	if err == nil || !strings.Contains(err.Error(), "bad.yaml") {
+		t.Errorf("Expected bad.yaml to be reported, got %v", err)
+	}
+
+	var paper *Theme
+	for i := range themes {
+		if themes[i].Name == "Bad" {
+			t.Error("Expected theme with invalid appearance to be rejected")
+		}
+		if themes[i].Name == "Paper" {
+			paper = &themes[i]
+		}
+	}
+	if paper == nil {
+		t.Fatal("Expected Paper theme to load")
+	}
+	if paper.Author != "Jane" || paper.License != "MIT" || paper.Homepage != "https://example.com" || !paper.HasTag("print") {
+		t.Errorf("Unexpected metadata: %+v", paper)
+	}
+	if paper.Appearance != AppearanceLight {
+		t.Errorf("Expected appearance computed as light, got %q", paper.Appearance)
+	}
+}
+
+// TestNewPair verifies that pairs are ordered by appearance and that two
+// themes of the same appearance are rejected
+func TestNewPair(t *testing.T) {
+	var latte, mocha, frappe Theme
+	for _, th := range GetBuiltInThemes() {
+		switch th.Name {
+		case "Catppuccin Latte":
+			latte = th
+		case "Catppuccin Mocha":
+			mocha = th
+		case "Catppuccin Frappe":
+			frappe = th
+		}
+	}
+
+	pair, err := NewPair(mocha, latte)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pair.Light.Name != "Catppuccin Latte" || pair.Dark.Name != "Catppuccin Mocha" {
+		t.Errorf("Unexpected pair order: light %q, dark %q", pair.Light.Name, pair.Dark.Name)
+	}
+	if pair.For(AppearanceLight).Name != "Catppuccin Latte" {
+		t.Error("Expected light side for light appearance")
+	}
+	if pair.For("").Name != "Catppuccin Mocha" {
+		t.Error("Expected dark side by default")
+	}
+
+	if _, err := NewPair(mocha, frappe); err == nil {
+		t.Error("Expected error pairing two dark themes")
+	}
+}
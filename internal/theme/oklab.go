@@ -0,0 +1,144 @@
+package theme
+
+import "math"
+
+// OKLab is a color in the OKLab perceptual color space, where Euclidean
+// distance approximates perceived difference. L runs from 0 (black) to 1 (white).
+// https://bottosson.github.io/posts/oklab/
+type OKLab struct {
+	L, A, B float64
+}
+
+// OKLCH is OKLab in polar form: lightness, chroma and hue in degrees [0, 360)
+type OKLCH struct {
+	L, C, H float64
+}
+
+// OKLab converts the color to OKLab, ignoring alpha
+func (c Color) OKLab() OKLab {
+	r, g, b := srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return OKLab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// OKLCH converts the color to OKLCH, ignoring alpha
+func (c Color) OKLCH() OKLCH {
+	return c.OKLab().OKLCH()
+}
+
+// linearRGB returns the linear sRGB components, which fall outside [0, 1]
+// for colors outside the sRGB gamut
+func (p OKLab) linearRGB() (r, g, b float64) {
+	l := p.L + 0.3963377774*p.A + 0.2158037573*p.B
+	m := p.L - 0.1055613458*p.A - 0.0638541728*p.B
+	s := p.L - 0.0894841775*p.A - 1.2914855480*p.B
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	return 4.0767416621*l - 3.3077115913*m + 0.2309699292*s,
+		-1.2684380046*l + 2.6097574011*m - 0.3413193965*s,
+		-0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+}
+
+// InGamut reports whether the color can be shown in sRGB
+func (p OKLab) InGamut() bool {
+	const eps = 1e-4
+	r, g, b := p.linearRGB()
+	return r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+}
+
+// Color converts to sRGB, clipping each component to the gamut
+func (p OKLab) Color() Color {
+	r, g, b := p.linearRGB()
+	return Color{R: linearToSRGB(r), G: linearToSRGB(g), B: linearToSRGB(b), A: 0xff}
+}
+
+// OKLCH converts to polar form
+func (p OKLab) OKLCH() OKLCH {
+	h := math.Atan2(p.B, p.A) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return OKLCH{L: p.L, C: math.Hypot(p.A, p.B), H: h}
+}
+
+// DistanceTo returns the perceptual distance between two colors
+func (p OKLab) DistanceTo(q OKLab) float64 {
+	return math.Sqrt((p.L-q.L)*(p.L-q.L) + (p.A-q.A)*(p.A-q.A) + (p.B-q.B)*(p.B-q.B))
+}
+
+// OKLab converts to rectangular form
+func (p OKLCH) OKLab() OKLab {
+	h := p.H * math.Pi / 180
+	return OKLab{L: p.L, A: p.C * math.Cos(h), B: p.C * math.Sin(h)}
+}
+
+// Color converts to sRGB. Colors outside the gamut keep their lightness and
+// hue and lose chroma until they fit, which keeps them recognizably the same
+// color, unlike clipping each component.
+func (p OKLCH) Color() Color {
+	p.L = math.Max(0, math.Min(1, p.L))
+	if p.OKLab().InGamut() {
+		return p.OKLab().Color()
+	}
+	low, high := 0.0, p.C
+	for i := 0; i < 20; i++ {
+		p.C = (low + high) / 2
+		if p.OKLab().InGamut() {
+			low = p.C
+		} else {
+			high = p.C
+		}
+	}
+	p.C = low
+	return p.OKLab().Color()
+}
+
+// WithContrast returns the color with its lightness moved away from against,
+// in steps of 0.01, until their contrast ratio is at least min or the
+// lightness reaches 0 or 1. Hue and chroma are kept as far as the gamut allows.
+func (p OKLCH) WithContrast(against Color, min float64) Color {
+	step := 0.01
+	if against.IsLight() {
+		step = -0.01
+	}
+	color := p.Color()
+	for ContrastRatio(color, against) < min && p.L >= 0 && p.L <= 1 {
+		p.L += step
+		color = p.Color()
+	}
+	return color
+}
+
+// HueDistance returns the angle between two hues in degrees, at most 180
+func HueDistance(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 360)
+	return math.Min(d, 360-d)
+}
+
+// ContrastRatio returns the WCAG contrast ratio of two colors, from 1 to 21
+// https://www.w3.org/TR/WCAG20/#contrast-ratiodef
+func ContrastRatio(a, b Color) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func linearToSRGB(v float64) uint8 {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(v * 255))
+}
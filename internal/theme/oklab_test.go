@@ -0,0 +1,68 @@
+package theme
+
+import (
+	"math"
+	"testing"
+)
+
+// TestOKLab verifies conversions against the reference values of the OKLab paper
+func TestOKLab(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want OKLab
+	}{
+		{"#ffffff", OKLab{L: 1}},
+		{"#000000", OKLab{}},
+		{"#ff0000", OKLab{L: 0.62796, A: 0.22486, B: 0.12585}},
+		{"#0000ff", OKLab{L: 0.45201, A: -0.03246, B: -0.31153}},
+	}
+	for _, tt := range tests {
+		got := MustParseColor(tt.hex).OKLab()
+		if got.DistanceTo(tt.want) > 1e-3 {
+			t.Errorf("%s: expected %+v, got %+v", tt.hex, tt.want, got)
+		}
+		if back := got.Color().Hex(); back != tt.hex {
+			t.Errorf("%s: round trip gave %s", tt.hex, back)
+		}
+	}
+}
+
+// TestOKLCHGamutMapping verifies out-of-gamut colors keep their lightness and
+// hue and lose chroma
+func TestOKLCHGamutMapping(t *testing.T) {
+	want := OKLCH{L: 0.7, C: 0.4, H: 142}
+	if want.OKLab().InGamut() {
+		t.Fatal("Expected the test color to be out of gamut")
+	}
+	got := want.Color().OKLCH()
+	if math.Abs(got.L-want.L) > 0.01 || HueDistance(got.H, want.H) > 2 {
+		t.Errorf("Expected lightness %.2f and hue %.0f, got %+v", want.L, want.H, got)
+	}
+	if got.C >= want.C || got.C < 0.1 {
+		t.Errorf("Expected the chroma reduced only to fit, got %.3f", got.C)
+	}
+}
+
+// TestContrastRatio verifies the WCAG contrast ratio
+func TestContrastRatio(t *testing.T) {
+	black, white := MustParseColor("#000000"), MustParseColor("#ffffff")
+	if got := ContrastRatio(black, white); math.Abs(got-21) > 1e-9 {
+		t.Errorf("Expected 21, got %f", got)
+	}
+	if got := ContrastRatio(white, white); got != 1 {
+		t.Errorf("Expected 1, got %f", got)
+	}
+	if got := ContrastRatio(MustParseColor("#767676"), white); math.Abs(got-4.54) > 0.01 {
+		t.Errorf("Expected 4.54, got %f", got)
+	}
+}
+
+// TestHueDistance verifies hue angles wrap around
+func TestHueDistance(t *testing.T) {
+	if got := HueDistance(350, 10); got != 20 {
+		t.Errorf("Expected 20, got %f", got)
+	}
+	if got := HueDistance(0, 180); got != 180 {
+		t.Errorf("Expected 180, got %f", got)
+	}
+}
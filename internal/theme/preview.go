@@ -8,13 +8,39 @@ import (
 )
 
 type ThemePreview struct {
-	theme Theme
+	theme      Theme      // Colors as displayed, simulated when deficiency is set
+	deficiency Deficiency // Simulated color vision deficiency
+	confusable bool       // Red and green look alike with the deficiency
+	profile    ColorProfile
 }
 
 func NewThemePreview(theme Theme) *ThemePreview {
 	return &ThemePreview{theme: theme}
 }
 
+// Simulating returns a preview showing the theme as a person with the
+// deficiency sees it
+func (tp *ThemePreview) Simulating(d Deficiency) *ThemePreview {
+	simulated := *tp
+	simulated.deficiency = d
+	simulated.theme.Colors = tp.theme.Colors.Simulate(d)
+	simulated.confusable = d != NormalVision && DiffColorsConfusable(tp.theme.Colors, d)
+	return &simulated
+}
+
+// WithProfile returns a preview that renders each color as the nearest one
+// the profile has
+func (tp *ThemePreview) WithProfile(p ColorProfile) *ThemePreview {
+	approximated := *tp
+	approximated.profile = p
+	return &approximated
+}
+
+// color returns the color to render a palette value with in the preview's profile
+func (tp *ThemePreview) color(value string) lipgloss.Color {
+	return lipgloss.Color(tp.profile.Approximate(value))
+}
+
 // Render generates a visual preview of the theme
 func (tp *ThemePreview) Render() string {
 	var preview strings.Builder
@@ -22,11 +48,30 @@ func (tp *ThemePreview) Render() string {
 	// Title
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color(tp.theme.Colors.Blue)).
+		Foreground(tp.color(tp.theme.Colors.Blue)).
 		MarginBottom(1)
 
-	preview.WriteString(titleStyle.Render(fmt.Sprintf("Preview: %s", tp.theme.Name)))
+	title := fmt.Sprintf("Preview: %s", tp.theme.Name)
+	if tp.deficiency != NormalVision {
+		title += fmt.Sprintf(" (simulating %s)", tp.deficiency)
+	}
+	preview.WriteString(titleStyle.Render(title))
+	preview.WriteString("\n")
+	preview.WriteString(tp.renderMetadata())
 	preview.WriteString("\n\n")
+	if tp.profile != TrueColor {
+		noticeStyle := lipgloss.NewStyle().
+			Foreground(tp.color(tp.theme.Colors.Yellow))
+		preview.WriteString(noticeStyle.Render(fmt.Sprintf("◐ This terminal shows %s: colors are approximated", tp.profile)))
+		preview.WriteString("\n\n")
+	}
+	if tp.confusable {
+		warningStyle := lipgloss.NewStyle().
+			Foreground(tp.color(tp.theme.Colors.Yellow)).
+			Bold(true)
+		preview.WriteString(warningStyle.Render(fmt.Sprintf("⚠ With %s, red and green (removed and added lines in diffs) are hard to tell apart", tp.deficiency)))
+		preview.WriteString("\n\n")
+	}
 
 	// Color palette
 	preview.WriteString(tp.renderColorPalette())
@@ -38,10 +83,35 @@ func (tp *ThemePreview) Render() string {
 
 	// Terminal example
 	preview.WriteString(tp.renderTerminalExample())
+	preview.WriteString("\n\n")
+
+	// Contrast audit
+	preview.WriteString(tp.renderContrast())
 
 	return preview.String()
 }
 
+// renderMetadata renders appearance, author, license, homepage and tags on one line
+func (tp *ThemePreview) renderMetadata() string {
+	parts := []string{string(tp.theme.ResolvedAppearance())}
+	if tp.theme.Author != "" {
+		parts = append(parts, "by "+tp.theme.Author)
+	}
+	if tp.theme.License != "" {
+		parts = append(parts, tp.theme.License)
+	}
+	if tp.theme.Homepage != "" {
+		parts = append(parts, tp.theme.Homepage)
+	}
+	if len(tp.theme.Tags) > 0 {
+		parts = append(parts, "#"+strings.Join(tp.theme.Tags, " #"))
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(tp.color(tp.theme.Colors.BrightBlack)).
+		Render(strings.Join(parts, " • "))
+}
+
 func (tp *ThemePreview) renderColorPalette() string {
 	var palette strings.Builder
 
@@ -65,13 +135,13 @@ func (tp *ThemePreview) renderColorPalette() string {
 
 	for _, color := range colors {
 		colorBox := lipgloss.NewStyle().
-			Background(lipgloss.Color(color.value)).
-			Foreground(lipgloss.Color(tp.theme.Colors.Foreground)).
+			Background(tp.color(color.value)).
+			Foreground(tp.color(tp.theme.Colors.Foreground)).
 			Padding(0, 2).
 			Render("  ")
 
 		label := lipgloss.NewStyle().
-			Foreground(lipgloss.Color(tp.theme.Colors.Foreground)).
+			Foreground(tp.color(tp.theme.Colors.Foreground)).
 			Render(fmt.Sprintf("%-12s %s", color.name, color.value))
 
 		palette.WriteString(fmt.Sprintf("  %s %s\n", colorBox, label))
@@ -84,7 +154,7 @@ func (tp *ThemePreview) renderCodeExample() string {
 	var code strings.Builder
 
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(tp.theme.Colors.Blue)).
+		Foreground(tp.color(tp.theme.Colors.Blue)).
 		Bold(true)
 
 	code.WriteString(headerStyle.Render("Code Example:"))
@@ -92,19 +162,19 @@ func (tp *ThemePreview) renderCodeExample() string {
 
 	// Background box
 	codeBoxStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color(tp.theme.Colors.Background)).
-		Foreground(lipgloss.Color(tp.theme.Colors.Foreground)).
+		Background(tp.color(tp.theme.Colors.Background)).
+		Foreground(tp.color(tp.theme.Colors.Foreground)).
 		Padding(1, 2).
 		MarginTop(1).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(tp.theme.Colors.Black))
+		BorderForeground(tp.color(tp.theme.Colors.Black))
 
 	// Syntax-highlighted code
-	keywordStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Magenta))
-	functionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Blue))
-	stringStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Green))
-	commentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.BrightBlack))
-	numberStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Magenta))
+	keywordStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Magenta))
+	functionStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Blue))
+	stringStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Green))
+	commentStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.BrightBlack))
+	numberStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Magenta))
 
 	codeContent := fmt.Sprintf(`%s main() {
     %s name %s %s
@@ -114,10 +184,10 @@ func (tp *ThemePreview) renderCodeExample() string {
 }`,
 		keywordStyle.Render("func"),
 		keywordStyle.Render("var"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Foreground)).Render("="),
+		lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Foreground)).Render("="),
 		stringStyle.Render(`"Theme Manager"`),
 		keywordStyle.Render("var"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Foreground)).Render("="),
+		lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Foreground)).Render("="),
 		numberStyle.Render("42"),
 		functionStyle.Render("println"),
 		stringStyle.Render(`"Hello, World!"`),
@@ -133,23 +203,23 @@ func (tp *ThemePreview) renderTerminalExample() string {
 	var terminal strings.Builder
 
 	headerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(tp.theme.Colors.Blue)).
+		Foreground(tp.color(tp.theme.Colors.Blue)).
 		Bold(true)
 
 	terminal.WriteString(headerStyle.Render("Terminal Example:"))
 	terminal.WriteString("\n")
 
 	termBoxStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color(tp.theme.Colors.Background)).
-		Foreground(lipgloss.Color(tp.theme.Colors.Foreground)).
+		Background(tp.color(tp.theme.Colors.Background)).
+		Foreground(tp.color(tp.theme.Colors.Foreground)).
 		Padding(1, 2).
 		MarginTop(1).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(tp.theme.Colors.Black))
+		BorderForeground(tp.color(tp.theme.Colors.Black))
 
-	promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Green))
-	pathStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Cyan))
-	commandStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Yellow))
+	promptStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Green))
+	pathStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Cyan))
+	commandStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Yellow))
 
 	termContent := fmt.Sprintf(`%s %s %s
 %s
@@ -158,12 +228,12 @@ func (tp *ThemePreview) renderTerminalExample() string {
 %s`,
 		promptStyle.Render("user@host"),
 		pathStyle.Render("~/projects"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Blue)).Render("❯"),
+		lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Blue)).Render("❯"),
 		commandStyle.Render("ls -la"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Foreground)).Render("total 42"),
+		lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Foreground)).Render("total 42"),
 		promptStyle.Render("user@host"),
 		pathStyle.Render("~/projects"),
-		lipgloss.NewStyle().Foreground(lipgloss.Color(tp.theme.Colors.Blue)).Render("❯"),
+		lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Blue)).Render("❯"),
 		commandStyle.Render("git status"),
 	)
 
@@ -172,6 +242,45 @@ func (tp *ThemePreview) renderTerminalExample() string {
 	return terminal.String()
 }
 
+// renderContrast renders the contrast audit, two checks per line, each with
+// an AAA, AA or fail badge
+func (tp *ThemePreview) renderContrast() string {
+	var contrast strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(tp.color(tp.theme.Colors.Blue)).
+		Bold(true)
+
+	contrast.WriteString(headerStyle.Render("Contrast (WCAG):"))
+	contrast.WriteString("\n")
+
+	labelStyle := lipgloss.NewStyle().Foreground(tp.color(tp.theme.Colors.Foreground))
+	badgeColors := map[string]string{
+		"AAA":  tp.theme.Colors.Green,
+		"AA":   tp.theme.Colors.Yellow,
+		"fail": tp.theme.Colors.Red,
+	}
+
+	for i, check := range Audit(tp.theme).Checks {
+		level := check.Level()
+		badge := lipgloss.NewStyle().
+			Background(tp.color(badgeColors[level])).
+			Foreground(tp.color(tp.theme.Colors.Background)).
+			Bold(true).
+			Width(6).
+			Align(lipgloss.Center).
+			Render(level)
+		label := labelStyle.Render(fmt.Sprintf("%-22s %5.2f", check.Label, check.Ratio))
+
+		contrast.WriteString(fmt.Sprintf("  %s %s", badge, label))
+		if i%2 == 1 {
+			contrast.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSuffix(contrast.String(), "\n")
+}
+
 // RenderCompact creates a compact single-line preview
 func (tp *ThemePreview) RenderCompact() string {
 	colors := []string{
@@ -186,7 +295,7 @@ func (tp *ThemePreview) RenderCompact() string {
 	var boxes strings.Builder
 	for _, color := range colors {
 		box := lipgloss.NewStyle().
-			Background(lipgloss.Color(color)).
+			Background(tp.color(color)).
 			Render("  ")
 		boxes.WriteString(box)
 	}
@@ -194,7 +303,9 @@ func (tp *ThemePreview) RenderCompact() string {
 	return boxes.String()
 }
 
-// RenderColorGrid creates a grid of all colors
+// RenderColorGrid creates a grid of all colors. When bright colors duplicate
+// their normal colors, the brights are shown again before and after
+// SynthesizeBrights.
 func (tp *ThemePreview) RenderColorGrid() string {
 	var grid strings.Builder
 
@@ -228,7 +339,7 @@ func (tp *ThemePreview) RenderColorGrid() string {
 	row := 0
 	for i, color := range allColors {
 		box := lipgloss.NewStyle().
-			Background(lipgloss.Color(color.value)).
+			Background(tp.color(color.value)).
 			Width(8).
 			Align(lipgloss.Center).
 			Render(color.name)
@@ -241,5 +352,36 @@ func (tp *ThemePreview) RenderColorGrid() string {
 		}
 	}
 
+	synthesized, changed := SynthesizeBrights(tp.theme.Colors)
+	if len(changed) == 0 {
+		return grid.String()
+	}
+	grid.WriteString(fmt.Sprintf("\nBright synthesis (%d replaced):\n\n", len(changed)))
+	for _, row := range []struct {
+		label  string
+		colors ColorPalette
+	}{{"Before", tp.theme.Colors}, {"After", synthesized}} {
+		grid.WriteString(fmt.Sprintf("%-7s", row.label))
+		values := make(map[string]string)
+		for _, slot := range row.colors.slots() {
+			values[slot.key] = *slot.value
+		}
+		for _, pair := range brightPairs {
+			box := lipgloss.NewStyle().
+				Background(tp.color(values[pair[1]])).
+				Width(8).
+				Align(lipgloss.Center).
+				Render(brightLabels[pair[1]])
+			grid.WriteString(box)
+		}
+		grid.WriteString("\n")
+	}
+
 	return grid.String()
 }
+
+// brightLabels are the grid labels of the bright colors
+var brightLabels = map[string]string{
+	"bright_black": "BBlk", "bright_red": "BRed", "bright_green": "BGrn", "bright_yellow": "BYlw",
+	"bright_blue": "BBlu", "bright_magenta": "BMag", "bright_cyan": "BCyn", "bright_white": "BWht",
+}
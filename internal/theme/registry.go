@@ -0,0 +1,406 @@
+package theme
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Registry holds theme families and looks themes up by name or alias.
+// It is safe for concurrent use. Themes are copied in and out, so callers
+// can't modify registered themes.
+type Registry struct {
+	mu       sync.RWMutex
+	families []BaseTheme // Registration order
+	watchers []registryWatcher
+	nextID   int
+}
+
+// RegistryEventKind is the kind of change a RegistryEvent describes
+type RegistryEventKind int
+
+const (
+	FamilyRegistered RegistryEventKind = iota
+	FamilyUnregistered
+	FamilyChanged // Variants were added to or removed from the family
+)
+
+// RegistryEvent describes a change to a Registry
+type RegistryEvent struct {
+	Kind   RegistryEventKind
+	Family BaseTheme
+}
+
+type registryWatcher struct {
+	id int
+	fn func(RegistryEvent)
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// NewBuiltInRegistry returns a registry holding the built-in themes
+func NewBuiltInRegistry() *Registry {
+	r := NewRegistry()
+	for _, family := range GetBuiltInBaseThemes() {
+		if err := r.Register(family); err != nil {
+			panic(err) // Built-in themes never conflict
+		}
+	}
+	return r
+}
+
+var (
+	defaultRegistry     *Registry
+	defaultRegistryOnce sync.Once
+)
+
+// DefaultRegistry returns the registry shared by the TUI, the CLI and
+// integrations. It starts with the built-in themes; custom themes are added
+// with ThemeLoader.LoadInto or Register.
+func DefaultRegistry() *Registry {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry = NewBuiltInRegistry()
+	})
+	return defaultRegistry
+}
+
+// Register adds a theme family. It fails if the family has no variants or
+// if a family or theme with the same name or alias is already registered.
+func (r *Registry) Register(family BaseTheme) error {
+	if err := validateFamily(family); err != nil {
+		return err
+	}
+	family = family.clone()
+
+	r.mu.Lock()
+	for _, existing := range r.families {
+		if themeKey(existing.Name) == themeKey(family.Name) {
+			r.mu.Unlock()
+			return fmt.Errorf("theme family %q is already registered", family.Name)
+		}
+		for _, name := range family.names() {
+			if existing.hasName(name) {
+				r.mu.Unlock()
+				return fmt.Errorf("theme %q is already registered in family %q", name, existing.Name)
+			}
+		}
+	}
+	r.families = append(r.families, family)
+	watchers := r.watchers
+	r.mu.Unlock()
+
+	notify(watchers, RegistryEvent{Kind: FamilyRegistered, Family: family.clone()})
+	return nil
+}
+
+// RegisterTheme adds a standalone theme as a single-variant family
+func (r *Registry) RegisterTheme(t Theme) error {
+	return r.Register(standaloneFamily(t))
+}
+
+// Unregister removes the family with the given name, or the family holding
+// the theme with that name or alias
+func (r *Registry) Unregister(name string) error {
+	r.mu.Lock()
+	index := -1
+	for i, family := range r.families {
+		if themeKey(family.Name) == themeKey(name) || family.hasName(name) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("theme %q is not registered", name)
+	}
+	family := r.families[index]
+	r.families = slices.Delete(r.families, index, index+1)
+	watchers := r.watchers
+	r.mu.Unlock()
+
+	notify(watchers, RegistryEvent{Kind: FamilyUnregistered, Family: family.clone()})
+	return nil
+}
+
+// AddVariants appends variants to a registered family. It fails if the
+// family isn't registered or a theme with the same name or alias is.
+func (r *Registry) AddVariants(name string, variants ...ThemeVariant) error {
+	r.mu.Lock()
+	index := r.familyIndex(name)
+	if index < 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("theme family %q is not registered", name)
+	}
+	family := r.families[index].clone()
+	family.Variants = append(family.Variants, BaseTheme{Variants: variants}.clone().Variants...)
+	if err := validateFamily(family); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	for i, existing := range r.families {
+		if i == index {
+			continue
+		}
+		for _, v := range variants {
+			for _, n := range v.names() {
+				if existing.hasName(n) {
+					r.mu.Unlock()
+					return fmt.Errorf("theme %q is already registered in family %q", n, existing.Name)
+				}
+			}
+		}
+	}
+	r.families[index] = family
+	watchers := r.watchers
+	r.mu.Unlock()
+
+	notify(watchers, RegistryEvent{Kind: FamilyChanged, Family: family.clone()})
+	return nil
+}
+
+// RemoveVariants removes the variants with the given full names from a
+// registered family. Removing every variant unregisters the family.
+func (r *Registry) RemoveVariants(name string, fullNames ...string) error {
+	r.mu.Lock()
+	index := r.familyIndex(name)
+	if index < 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("theme family %q is not registered", name)
+	}
+	family := r.families[index].clone()
+	family.Variants = slices.DeleteFunc(family.Variants, func(v ThemeVariant) bool {
+		return slices.ContainsFunc(fullNames, func(n string) bool { return themeKey(n) == themeKey(v.FullName) })
+	})
+	event := RegistryEvent{Kind: FamilyChanged, Family: family.clone()}
+	if len(family.Variants) == 0 {
+		event = RegistryEvent{Kind: FamilyUnregistered, Family: r.families[index].clone()}
+		r.families = slices.Delete(r.families, index, index+1)
+	} else {
+		r.families[index] = family
+	}
+	watchers := r.watchers
+	r.mu.Unlock()
+
+	notify(watchers, event)
+	return nil
+}
+
+// familyIndex returns the index of the family with the given name, or -1.
+// The caller must hold r.mu.
+func (r *Registry) familyIndex(name string) int {
+	return slices.IndexFunc(r.families, func(f BaseTheme) bool {
+		return themeKey(f.Name) == themeKey(name)
+	})
+}
+
+// Lookup returns the theme with the given name or alias (case-insensitive)
+func (r *Registry) Lookup(name string) (Theme, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key := themeKey(name)
+	for _, family := range r.families {
+		for _, variant := range family.Variants {
+			if variant.hasName(key) {
+				return variant.Theme(family).clone(), true
+			}
+		}
+	}
+	return Theme{}, false
+}
+
+// Family returns the family with the given name (case-insensitive)
+func (r *Registry) Family(name string) (BaseTheme, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, family := range r.families {
+		if themeKey(family.Name) == themeKey(name) {
+			return family.clone(), true
+		}
+	}
+	return BaseTheme{}, false
+}
+
+// FamilyOf returns the family holding the theme with the given name or alias
+func (r *Registry) FamilyOf(name string) (BaseTheme, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, family := range r.families {
+		if family.hasName(name) {
+			return family.clone(), true
+		}
+	}
+	return BaseTheme{}, false
+}
+
+// Families returns all families in registration order
+func (r *Registry) Families() []BaseTheme {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	families := make([]BaseTheme, len(r.families))
+	for i, family := range r.families {
+		families[i] = family.clone()
+	}
+	return families
+}
+
+// Themes returns every registered theme, in family order
+func (r *Registry) Themes() []Theme {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	themes := FlattenBaseThemes(r.families)
+	for i := range themes {
+		themes[i] = themes[i].clone()
+	}
+	return themes
+}
+
+// Watch calls fn after every change to the registry until the returned
+// function is called. fn runs on the goroutine that made the change.
+func (r *Registry) Watch(fn func(RegistryEvent)) (stop func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.nextID
+	r.nextID++
+	// Copy on write so notify can use a snapshot without holding the lock
+	r.watchers = append(slices.Clip(r.watchers), registryWatcher{id: id, fn: fn})
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.watchers = slices.DeleteFunc(slices.Clone(r.watchers), func(w registryWatcher) bool {
+			return w.id == id
+		})
+	}
+}
+
+func notify(watchers []registryWatcher, event RegistryEvent) {
+	for _, w := range watchers {
+		w.fn(event)
+	}
+}
+
+// validateFamily checks that a family can be registered
+func validateFamily(family BaseTheme) error {
+	if family.Name == "" {
+		return fmt.Errorf("theme family has no name")
+	}
+	if len(family.Variants) == 0 {
+		return fmt.Errorf("theme family %q has no variants", family.Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, variant := range family.Variants {
+		if variant.FullName == "" {
+			return fmt.Errorf("theme family %q: variant %q has no full name", family.Name, variant.Name)
+		}
+		for _, name := range variant.names() {
+			if seen[themeKey(name)] {
+				return fmt.Errorf("theme family %q: duplicate theme name %q", family.Name, name)
+			}
+			seen[themeKey(name)] = true
+		}
+	}
+	return nil
+}
+
+// standaloneFamily wraps a theme in a single-variant family holding its metadata
+func standaloneFamily(t Theme) BaseTheme {
+	return BaseTheme{
+		Name:        t.Name,
+		Description: t.Description,
+		Author:      t.Author,
+		License:     t.License,
+		Homepage:    t.Homepage,
+		Tags:        t.Tags,
+		Variants: []ThemeVariant{{
+			Name:        t.Name,
+			DisplayName: t.Name,
+			FullName:    t.Name,
+			Colors:      t.Colors,
+			Appearance:  t.Appearance,
+			Source:      t.Source,
+			Wallpaper:   t.Wallpaper,
+			Aliases:     t.Aliases,
+			Extends:     t.Extends,
+			Base:        t.Base,
+			Overrides:   t.Overrides,
+
+			SynthesizeBrights: t.SynthesizeBrights,
+		}},
+	}
+}
+
+// clone returns a copy of the family that shares no slices with b
+func (b BaseTheme) clone() BaseTheme {
+	b.Tags = slices.Clone(b.Tags)
+	b.Variants = slices.Clone(b.Variants)
+	for i := range b.Variants {
+		v := &b.Variants[i]
+		v.Tags = slices.Clone(v.Tags)
+		v.Aliases = slices.Clone(v.Aliases)
+		v.Overrides = slices.Clone(v.Overrides)
+		v.Source = v.Source.clone()
+	}
+	return b
+}
+
+// clone returns a copy of the theme that shares no slices with t
+func (t Theme) clone() Theme {
+	t.Tags = slices.Clone(t.Tags)
+	t.Aliases = slices.Clone(t.Aliases)
+	t.Overrides = slices.Clone(t.Overrides)
+	t.Source = t.Source.clone()
+	return t
+}
+
+// clone returns a copy of the source, or nil
+func (s *ThemeSource) clone() *ThemeSource {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// names returns the full names and aliases of every variant
+func (b BaseTheme) names() []string {
+	var names []string
+	for _, variant := range b.Variants {
+		names = append(names, variant.names()...)
+	}
+	return names
+}
+
+// hasName reports whether a variant has the given full name or alias
+func (b BaseTheme) hasName(name string) bool {
+	for _, variant := range b.Variants {
+		if variant.hasName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// names returns the variant's full name followed by its aliases
+func (v ThemeVariant) names() []string {
+	return append([]string{v.FullName}, v.Aliases...)
+}
+
+// hasName reports whether the variant has the given full name or alias (case-insensitive)
+func (v ThemeVariant) hasName(name string) bool {
+	for _, n := range v.names() {
+		if themeKey(n) == themeKey(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestRegistryLookup verifies lookups by name and alias, ignoring case
+func TestRegistryLookup(t *testing.T) {
+	r := NewBuiltInRegistry()
+
+	for _, name := range []string{"Catppuccin Mocha", "catppuccin mocha", "Rosé Pine Dawn", "  NORD "} {
+		if _, ok := r.Lookup(name); !ok {
+			t.Errorf("Expected %q to be found", name)
+		}
+	}
+	if th, _ := r.Lookup("Catppuccin Frappé"); th.Name != "Catppuccin Frappe" {
+		t.Errorf("Expected alias to resolve to Catppuccin Frappe, got %q", th.Name)
+	}
+	if _, ok := r.Lookup("Catppuccin"); ok {
+		t.Error("Expected family names not to match themes")
+	}
+	if family, ok := r.Family("catppuccin"); !ok || len(family.Variants) != 4 {
+		t.Errorf("Expected Catppuccin family with 4 variants, got %+v", family)
+	}
+}
+
+// TestRegistryRegisterUnregister verifies registration conflicts and removal
+func TestRegistryRegisterUnregister(t *testing.T) {
+	r := NewBuiltInRegistry()
+	custom := GetBuiltInThemes()[0]
+	custom.Name = "Polar Night"
+	custom.Aliases = []string{"Polar"}
+
+	if err := r.RegisterTheme(custom); err != nil {
+		t.Fatal(err)
+	}
+	if th, ok := r.Lookup("polar"); !ok || th.Name != "Polar Night" {
+		t.Errorf("Expected registered theme by alias, got %+v", th)
+	}
+
+	if err := r.RegisterTheme(custom); err == nil {
+		t.Error("Expected registering the same theme twice to fail")
+	}
+	clash := custom
+	clash.Name = "Other"
+	clash.Aliases = []string{"Catppuccin Mocha"}
+	if err := r.RegisterTheme(clash); err == nil {
+		t.Error("Expected an alias clashing with a registered theme to fail")
+	}
+	if err := r.Register(BaseTheme{Name: "Empty"}); err == nil {
+		t.Error("Expected a family without variants to fail")
+	}
+
+	if err := r.Unregister("Polar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Lookup("Polar Night"); ok {
+		t.Error("Expected unregistered theme to be gone")
+	}
+	if err := r.Unregister("Polar Night"); err == nil {
+		t.Error("Expected unregistering an unknown theme to fail")
+	}
+	if want := len(GetBuiltInThemes()); len(r.Themes()) != want {
+		t.Errorf("Expected the %d built-in themes to remain, got %d", want, len(r.Themes()))
+	}
+}
+
+// TestRegistryReturnsCopies verifies callers can't modify registered themes
+func TestRegistryReturnsCopies(t *testing.T) {
+	r := NewBuiltInRegistry()
+
+	th, _ := r.Lookup("Nord")
+	th.Tags[0] = "modified"
+	th.Colors.Background = "#000000"
+
+	families := r.Families()
+	families[0].Variants[0].FullName = "Modified"
+	families[0].Tags[1] = "modified"
+
+	themes := r.Themes()
+	themes[0].Tags[2] = "modified"
+
+	got, ok := r.Lookup("Nord")
+	if !ok {
+		t.Fatal("Expected Nord to be unchanged")
+	}
+	for _, tag := range got.Tags {
+		if tag == "modified" {
+			t.Errorf("Expected tags to be unchanged, got %v", got.Tags)
+		}
+	}
+	if got.Colors.Background != "#2e3440" {
+		t.Errorf("Expected background to be unchanged, got %s", got.Colors.Background)
+	}
+}
+
+// TestRegistryWatch verifies change notifications until the watcher stops
+func TestRegistryWatch(t *testing.T) {
+	r := NewRegistry()
+	var events []RegistryEvent
+	stop := r.Watch(func(e RegistryEvent) {
+		events = append(events, e)
+	})
+
+	family := GetBuiltInBaseThemes()[0]
+	if err := r.Register(family); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Unregister(family.Name); err != nil {
+		t.Fatal(err)
+	}
+	stop()
+	if err := r.Register(family); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Kind != FamilyRegistered || events[1].Kind != FamilyUnregistered || events[0].Family.Name != "Nord" {
+		t.Errorf("Unexpected events: %+v", events)
+	}
+}
+
+// TestRegistryConcurrentAccess exercises the registry from several goroutines
+// (run with -race to check for data races)
+func TestRegistryConcurrentAccess(t *testing.T) {
+	r := NewBuiltInRegistry()
+	stop := r.Watch(func(RegistryEvent) {})
+	defer stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			custom := GetBuiltInThemes()[0]
+			custom.Name = fmt.Sprintf("Custom %d", i)
+			for j := 0; j < 50; j++ {
+				if err := r.RegisterTheme(custom); err != nil {
+					t.Error(err)
+					return
+				}
+				r.Lookup("Catppuccin Mocha")
+				r.Themes()
+				if err := r.Unregister(custom.Name); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(r.Families()) != len(GetBuiltInBaseThemes()) {
+		t.Errorf("Expected only the built-in families to remain, got %d", len(r.Families()))
+	}
+}
+
+// TestLoadIntoRegistry verifies custom themes can extend programmatically
+// registered themes and that loading again replaces them
+func TestLoadIntoRegistry(t *testing.T) {
+	dir := t.TempDir()
+	r := NewBuiltInRegistry()
+	base := GetBuiltInThemes()[0]
+	base.Name = "Company Base"
+	if err := r.RegisterTheme(base); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "team.yaml")
+	if err := os.WriteFile(path, []byte("name: Team\nextends: company base\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader := NewThemeLoader(dir)
+	if err := loader.LoadInto(r); err != nil {
+		t.Fatal(err)
+	}
+	team, ok := r.Lookup("Team")
+	if !ok {
+		t.Fatal("Expected custom theme to be registered")
+	}
+	if team.Colors != base.Colors || team.OfficialName() != "Company Base" {
+		t.Errorf("Expected Team to inherit Company Base, got %+v", team)
+	}
+
+	// Reloading after the file changed replaces the registered theme
+	if err := os.WriteFile(path, []byte("name: Team\nextends: company base\ncolors:\n  background: \"#000000\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.LoadInto(r); err != nil {
+		t.Fatal(err)
+	}
+	if team, _ := r.Lookup("Team"); team.Colors.Background != "#000000" {
+		t.Errorf("Expected reloaded background, got %s", team.Colors.Background)
+	}
+	if want := len(GetBuiltInBaseThemes()) + 2; len(r.Families()) != want {
+		t.Errorf("Expected %d families after reload, got %d", want, len(r.Families()))
+	}
+}
+
+// TestRegistryAddVariants verifies variants can be added to and removed from
+// a registered family
+func TestRegistryAddVariants(t *testing.T) {
+	r := NewBuiltInRegistry()
+	var events []RegistryEvent
+	stop := r.Watch(func(e RegistryEvent) {
+		events = append(events, e)
+	})
+	defer stop()
+
+	nord, _ := r.Lookup("Nord")
+	variant := ThemeVariant{Name: "Light", DisplayName: "Light", FullName: "Nord Light", Colors: nord.Colors}
+	if err := r.AddVariants("nord", variant); err != nil {
+		t.Fatal(err)
+	}
+	if family, ok := r.FamilyOf("Nord Light"); !ok || family.Name != "Nord" || len(family.Variants) != 2 {
+		t.Errorf("Expected Nord Light in the Nord family, got %+v", family)
+	}
+	if err := r.AddVariants("Nord", variant); err == nil {
+		t.Error("Expected a duplicate variant to be rejected")
+	}
+	variant.FullName = "Dracula"
+	if err := r.AddVariants("Nord", variant); err == nil {
+		t.Error("Expected a name registered in another family to be rejected")
+	}
+	if err := r.AddVariants("Missing", variant); err == nil {
+		t.Error("Expected an unregistered family to be rejected")
+	}
+
+	if err := r.RemoveVariants("Nord", "nord light"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Lookup("Nord Light"); ok {
+		t.Error("Expected Nord Light to be removed")
+	}
+	if err := r.RemoveVariants("Nord", "Nord"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Family("Nord"); ok {
+		t.Error("Expected removing the last variant to unregister the family")
+	}
+
+	if len(events) != 3 || events[0].Kind != FamilyChanged || events[1].Kind != FamilyChanged || events[2].Kind != FamilyUnregistered {
+		t.Errorf("Unexpected events: %+v", events)
+	}
+}
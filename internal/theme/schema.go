@@ -0,0 +1,318 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaField describes one key of the theme file schema.
+// Keys are snake_case; legacy spellings such as "brightBlack", "BrightBlack"
+// or "brightblack" are accepted because keys are matched ignoring case,
+// underscores and dashes.
+type schemaField struct {
+	key         string
+	kind        string // "string", "boolean", "array" or "object"
+	description string
+	fields      []schemaField // Keys of an object, or of each object in an array
+}
+
+// paletteFields returns the schema of the colors table
+func paletteFields() []schemaField {
+	fields := []schemaField{
+		{key: "background", kind: "string", description: "Default background color"},
+		{key: "foreground", kind: "string", description: "Default text color"},
+	}
+	for i, slot := range new(ColorPalette).slots()[2:18] {
+		name := strings.ReplaceAll(slot.key, "_", " ")
+		fields = append(fields, schemaField{key: slot.key, kind: "string", description: fmt.Sprintf("ANSI color %d (%s)", i, name)})
+	}
+	return append(fields,
+		schemaField{key: "cursor", kind: "string", description: "Cursor color (optional)"},
+		schemaField{key: "cursor_text", kind: "string", description: "Color of the text under the cursor (optional)"},
+		schemaField{key: "selection_background", kind: "string", description: "Background of selected text (optional)"},
+		schemaField{key: "selection_foreground", kind: "string", description: "Color of selected text (optional)"},
+		schemaField{key: "accent", kind: "string", description: "Accent color of the application UI (optional)"},
+	)
+}
+
+// themeFields returns the schema of a theme file. Family files also accept
+// variants, each described by the theme fields plus the variant naming keys.
+func themeFields(family bool) []schemaField {
+	fields := []schemaField{
+		{key: "name", kind: "string", description: "Theme name, or the short variant name inside variants"},
+		{key: "description", kind: "string", description: "Short description shown in the theme list"},
+		{key: "aliases", kind: "array", description: "Other names the theme can be looked up by"},
+		{key: "extends", kind: "string", description: "Theme to inherit unset colors and the description from"},
+		{key: "colors", kind: "object", description: "Color palette", fields: paletteFields()},
+		{key: "author", kind: "string", description: "Theme author"},
+		{key: "license", kind: "string", description: "License of the theme"},
+		{key: "homepage", kind: "string", description: "Theme homepage URL"},
+		{key: "appearance", kind: "string", description: "light or dark; computed from the background when absent"},
+		{key: "tags", kind: "array", description: "Tags used for search and filtering"},
+		{key: "wallpaper", kind: "string", description: "Image set as the desktop wallpaper, e.g. the one the theme was generated from"},
+		{key: "synthesize_brights", kind: "boolean", description: "Replace bright colors that duplicate their normal color with brighter ones when applying"},
+		{key: "source", kind: "object", description: "Application theme an imported theme was converted from", fields: []schemaField{
+			{key: "app", kind: "string", description: "Application, e.g. vscode"},
+			{key: "extension_id", kind: "string", description: "Extension providing the theme"},
+			{key: "theme", kind: "string", description: "Name the application knows the theme by"},
+		}},
+		{key: "variant", kind: "string", description: "Short variant name when the file is part of a family directory"},
+		{key: "display_name", kind: "string", description: "Name shown in the variant list"},
+		{key: "full_name", kind: "string", description: "Full theme name of a variant; defaults to \"<family> <variant>\""},
+	}
+	if family {
+		fields = append(fields, schemaField{key: "variants", kind: "array", description: "Variants of a theme family", fields: themeFields(false)})
+	}
+	return fields
+}
+
+// keyFold reduces a key to the form used to match legacy spellings
+func keyFold(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(key))
+}
+
+// lookupField returns the schema field matching key
+func lookupField(fields []schemaField, key string) (schemaField, bool) {
+	folded := keyFold(key)
+	for _, field := range fields {
+		if keyFold(field.key) == folded {
+			return field, true
+		}
+	}
+	return schemaField{}, false
+}
+
+// canonicalizeKeys renames the keys of a decoded theme file in place to their
+// canonical spelling. Legacy spellings are reported as warnings, unknown
+// and duplicate keys as errors.
+func canonicalizeKeys(obj map[string]any, fields []schemaField, prefix string) []LintIssue {
+	var issues []LintIssue
+
+	// Sort keys so issues are reported in a stable order
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := lookupField(fields, key)
+		if !ok {
+			issues = append(issues, LintIssue{Field: prefix + key, Message: "unknown key"})
+			continue
+		}
+
+		value := obj[key]
+		if key != field.key {
+			if _, exists := obj[field.key]; exists {
+				issues = append(issues, LintIssue{Field: prefix + key, Message: fmt.Sprintf("duplicate of %q", field.key)})
+				delete(obj, key)
+				continue
+			}
+			issues = append(issues, LintIssue{Field: prefix + key, Message: fmt.Sprintf("use %q", field.key), Warning: true})
+			delete(obj, key)
+			obj[field.key] = value
+		}
+
+		if field.fields == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			issues = append(issues, canonicalizeKeys(v, field.fields, prefix+field.key+".")...)
+		case []any:
+			for i, item := range v {
+				if itemObj, ok := item.(map[string]any); ok {
+					issues = append(issues, canonicalizeKeys(itemObj, field.fields, fmt.Sprintf("%s%s[%d].", prefix, field.key, i))...)
+				}
+			}
+		case []map[string]any:
+			// TOML arrays of tables
+			for i, itemObj := range v {
+				issues = append(issues, canonicalizeKeys(itemObj, field.fields, fmt.Sprintf("%s%s[%d].", prefix, field.key, i))...)
+			}
+		}
+	}
+
+	return issues
+}
+
+// decodeThemeTree decodes a JSON, YAML or TOML theme file into generic maps
+// with canonical keys, along with issues found in its keys
+func decodeThemeTree(path string) (map[string]any, []LintIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseThemeTree(path, data)
+}
+
+// parseThemeTree is decodeThemeTree for data already read from path
+func parseThemeTree(path string, data []byte) (map[string]any, []LintIssue, error) {
+	var (
+		tree map[string]any
+		err  error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &tree)
+	case ".yaml", ".yml":
+		// Decode through yaml.Node so unquoted scalars such as 000000 keep
+		// their text instead of becoming numbers
+		var doc yaml.Node
+		if err = yaml.Unmarshal(data, &doc); err == nil {
+			tree, err = yamlTree(&doc)
+		}
+	case ".toml":
+		// Use the BurntSushi/toml package
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, nil, fmt.Errorf("%s: unsupported theme format", path)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if tree == nil {
+		tree = make(map[string]any)
+	}
+
+	return tree, canonicalizeKeys(tree, themeFields(true), ""), nil
+}
+
+// yamlTree converts a YAML document into generic maps, keeping every scalar
+// other than true and false as a string
+func yamlTree(doc *yaml.Node) (map[string]any, error) {
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return nil, nil // Empty document
+	}
+	value, err := yamlValue(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	tree, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("line %d: expected a mapping at the top level", doc.Content[0].Line)
+	}
+	return tree, nil
+}
+
+func yamlValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return nil, nil
+		case "!!bool":
+			var b bool
+			err := node.Decode(&b)
+			return b, err
+		}
+		return node.Value, nil
+	case yaml.SequenceNode:
+		items := make([]any, 0, len(node.Content))
+		for _, child := range node.Content {
+			item, err := yamlValue(child)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case yaml.MappingNode:
+		obj := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := yamlValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			obj[node.Content[i].Value] = value
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of theme files
+func JSONSchema() ([]byte, error) {
+	slotKeys := make([]string, 0, 18)
+	for _, slot := range new(ColorPalette).slots() {
+		if !slot.optional {
+			slotKeys = append(slotKeys, slot.key)
+		}
+	}
+
+	schema := map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "Zakaranda theme",
+		"description": "A custom theme, or a theme family with variants. Colors accept hex, rgb(), hsl() and CSS color names.",
+		"type":        "object",
+		"required":    []string{"name"},
+		"$defs": map[string]any{
+			"palette": objectSchema(paletteFields(), nil),
+			// Themes that don't extend another theme must set every color
+			"fullPalette": map[string]any{"required": slotKeys},
+		},
+	}
+	for key, value := range objectSchema(themeFields(true), nil) {
+		schema[key] = value
+	}
+	schema["if"] = map[string]any{"not": map[string]any{"anyOf": []any{
+		map[string]any{"required": []string{"extends"}},
+		map[string]any{"required": []string{"variants"}},
+	}}}
+	schema["then"] = map[string]any{
+		"required":   []string{"colors"},
+		"properties": map[string]any{"colors": map[string]any{"$ref": "#/$defs/fullPalette"}},
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// objectSchema returns the JSON Schema properties of an object with the given fields
+func objectSchema(fields []schemaField, required []string) map[string]any {
+	properties := make(map[string]any, len(fields))
+	for _, field := range fields {
+		properties[field.key] = fieldSchema(field)
+	}
+	obj := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+func fieldSchema(field schemaField) map[string]any {
+	switch {
+	case field.key == "colors":
+		return map[string]any{"description": field.description, "$ref": "#/$defs/palette"}
+	case field.key == "appearance":
+		return map[string]any{"description": field.description, "type": "string", "enum": []string{string(AppearanceLight), string(AppearanceDark)}}
+	case field.kind == "array" && field.fields != nil:
+		item := objectSchema(field.fields, []string{"name"})
+		return map[string]any{"description": field.description, "type": "array", "items": item}
+	case field.kind == "array":
+		return map[string]any{"description": field.description, "type": "array", "items": map[string]any{"type": "string"}}
+	case field.kind == "object" && field.fields != nil:
+		obj := objectSchema(field.fields, []string{"app", "theme"})
+		obj["description"] = field.description
+		return obj
+	}
+	return map[string]any{"description": field.description, "type": field.kind}
+}
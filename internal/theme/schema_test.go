@@ -0,0 +1,207 @@
+package theme
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadCustomThemeKeySpellings verifies that canonical and legacy key
+// spellings decode to the same theme in every format
+func TestLoadCustomThemeKeySpellings(t *testing.T) {
+	dir := t.TempDir()
+	loader := NewThemeLoader(dir)
+
+	files := map[string]string{
+		"snake.yaml":  "name: Test\n" + testPaletteYAML("", "#101010"),
+		"camel.json":  `{"Name": "Test", "Colors": {"Background": "#101010", "foreground": "#808080", "black": "#808080", "red": "#808080", "green": "#808080", "yellow": "#808080", "blue": "#808080", "magenta": "#808080", "cyan": "#808080", "white": "#808080", "brightBlack": "#808080", "brightRed": "#808080", "brightGreen": "#808080", "brightYellow": "#808080", "brightBlue": "#808080", "brightMagenta": "#808080", "brightCyan": "#808080", "brightWhite": "#808080"}}`,
+		"pascal.toml": "Name = \"Test\"\n[Colors]\n" + strings.ReplaceAll(strings.ReplaceAll(testPaletteYAML("", "#101010")[len("colors:\n"):], ": ", " = "), "  ", ""),
+		"kebab.yml":   "name: Test\n" + strings.ReplaceAll(testPaletteYAML("", "#101010"), "bright", "bright-"),
+	}
+
+	var want ColorPalette
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		file, err := loader.loadThemeFile(path)
+		if err != nil {
+			t.Errorf("%s: expected theme to load, got: %v", name, err)
+			continue
+		}
+		if file.Name != "Test" || file.Colors.BrightWhite != "#808080" {
+			t.Errorf("%s: unexpected theme %+v", name, file)
+		}
+		if want == (ColorPalette{}) {
+			want = file.Colors
+		} else if file.Colors != want {
+			t.Errorf("%s: expected palette %+v, got %+v", name, want, file.Colors)
+		}
+	}
+}
+
+// TestExportThemeRoundTrip verifies exported themes use snake_case keys and load back unchanged
+func TestExportThemeRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	loader := NewThemeLoader(dir)
+	original := GetBuiltInThemes()[0]
+
+	for _, name := range []string{"export.json", "export.yaml", "export.toml"} {
+		path := filepath.Join(dir, name)
+		if err := loader.ExportTheme(original, path); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "bright_black") {
+			t.Errorf("%s: expected snake_case keys, got:\n%s", name, data)
+		}
+
+		file, err := loader.loadThemeFile(path)
+		if err != nil {
+			t.Fatalf("%s: expected exported theme to load, got: %v", name, err)
+		}
+		if !reflect.DeepEqual(file.theme(), original) {
+			t.Errorf("%s: round trip changed the theme:\nwant %+v\ngot  %+v", name, original, file.theme())
+		}
+	}
+}
+
+// TestJSONSchemaUpToDate verifies the committed schema matches the generated one
+func TestJSONSchemaUpToDate(t *testing.T) {
+	generated, err := JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	committed, err := os.ReadFile(filepath.Join("..", "..", "schema", "theme.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated, committed) {
+		t.Error("schema/theme.schema.json is out of date; regenerate it with: zakaranda schema > schema/theme.schema.json")
+	}
+}
+
+// TestSaveCustomThemeTOML verifies themes saved as TOML load back from the custom themes directory
+func TestSaveCustomThemeTOML(t *testing.T) {
+	dir := t.TempDir()
+	loader := NewThemeLoader(dir)
+	custom := GetBuiltInThemes()[0]
+	custom.Name = "Saved Theme"
+	custom.Colors.Background = "rgb(0, 0, 0)"
+
+	if err := loader.SaveCustomTheme(custom, "toml"); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := loader.loadThemeFile(filepath.Join(dir, "Saved_Theme.toml"))
+	if err != nil {
+		t.Fatalf("Expected saved theme to load, got: %v", err)
+	}
+	if file.Name != "Saved Theme" || file.Colors.Background != "#000000" {
+		t.Errorf("Unexpected saved theme: %+v", file)
+	}
+
+	if err := loader.SaveCustomTheme(custom, "ini"); err == nil {
+		t.Error("Expected unsupported format to be rejected")
+	}
+}
+
+// TestSaveCustomThemeSource verifies the source of an imported theme survives
+// saving and loading in every format
+func TestSaveCustomThemeSource(t *testing.T) {
+	dir := t.TempDir()
+	loader := NewThemeLoader(dir)
+	source := &ThemeSource{App: "vscode", ExtensionID: "acme.harbor", Theme: "Harbor Night"}
+
+	for i, format := range []string{"toml", "yaml", "json"} {
+		custom := GetBuiltInThemes()[0]
+		custom.Name = fmt.Sprintf("Imported %d", i)
+		custom.Source = source
+		if err := loader.SaveCustomTheme(custom, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	themes, err := loader.LoadAllThemes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, th := range themes {
+		if !strings.HasPrefix(th.Name, "Imported ") {
+			continue
+		}
+		found++
+		if th.Source == nil || *th.Source != *source {
+			t.Errorf("%s: expected source %+v, got %+v", th.Name, source, th.Source)
+		}
+	}
+	if found != 3 {
+		t.Errorf("Expected 3 imported themes, got %d", found)
+	}
+}
+
+// TestSaveCustomFamily verifies families saved in every format load back with
+// their variants
+func TestSaveCustomFamily(t *testing.T) {
+	dir := t.TempDir()
+	loader := NewThemeLoader(dir)
+	catppuccin := GetBuiltInBaseThemes()[1]
+
+	for _, format := range ThemeFormats {
+		family := catppuccin
+		family.Name = "Saved " + format
+		family.Variants = nil
+		for _, v := range catppuccin.Variants[:2] {
+			v.FullName = family.Name + " " + v.Name
+			v.Aliases = nil
+			family.Variants = append(family.Variants, v)
+		}
+		family.Variants[0].Colors.Background = "rgb(0, 0, 0)"
+		if err := loader.SaveCustomFamily(family, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	families, err := loader.LoadBaseThemes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, family := range families {
+		if !strings.HasPrefix(family.Name, "Saved ") {
+			continue
+		}
+		found++
+		if len(family.Variants) != 2 {
+			t.Fatalf("%s: expected 2 variants, got %d", family.Name, len(family.Variants))
+		}
+		first, second := family.Variants[0], family.Variants[1]
+		if first.FullName != family.Name+" "+catppuccin.Variants[0].Name || first.Colors.Background != "#000000" {
+			t.Errorf("%s: unexpected first variant %s with background %s", family.Name, first.FullName, first.Colors.Background)
+		}
+		if second.Appearance != catppuccin.Variants[1].Appearance || second.Colors != catppuccin.Variants[1].Colors {
+			t.Errorf("%s: second variant doesn't match %s", family.Name, catppuccin.Variants[1].FullName)
+		}
+	}
+	if found != len(ThemeFormats) {
+		t.Errorf("Expected %d saved families, got %d", len(ThemeFormats), found)
+	}
+
+	bad := catppuccin
+	bad.Variants = []ThemeVariant{{Name: "Broken", FullName: "Broken"}}
+	if err := loader.SaveCustomFamily(bad, "toml"); err == nil {
+		t.Error("Expected a variant without colors to be rejected")
+	}
+}
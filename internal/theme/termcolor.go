@@ -0,0 +1,174 @@
+package theme
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ColorProfile is the set of colors a terminal can show
+type ColorProfile int
+
+const (
+	TrueColor ColorProfile = iota // 24-bit color
+	ANSI256                       // The xterm 256-color palette
+	ANSI16                        // The 16 ANSI colors
+)
+
+func (p ColorProfile) String() string {
+	switch p {
+	case ANSI256:
+		return "256 colors"
+	case ANSI16:
+		return "16 colors"
+	}
+	return "truecolor"
+}
+
+// ParseColorProfile parses "truecolor" (or "24bit"), "256" or "16"
+func ParseColorProfile(s string) (ColorProfile, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "truecolor", "24bit":
+		return TrueColor, nil
+	case "256":
+		return ANSI256, nil
+	case "16":
+		return ANSI16, nil
+	}
+	return TrueColor, fmt.Errorf("invalid color profile %q (use truecolor, 256 or 16)", s)
+}
+
+// tmuxFeatures returns the terminal features tmux reports for the current
+// client, replaceable in tests
+var tmuxFeatures = func() string {
+	out, err := exec.Command("tmux", "display-message", "-p", "#{client_termfeatures}").Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// DetectColorProfile guesses the colors the terminal can show from the
+// environment read by getenv. ZAKARANDA_COLORS (truecolor, 256 or 16)
+// overrides the guess.
+func DetectColorProfile(getenv func(string) string) ColorProfile {
+	if p, err := ParseColorProfile(getenv("ZAKARANDA_COLORS")); err == nil {
+		return p
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+	limited := ANSI16
+	if strings.Contains(term, "256color") {
+		limited = ANSI256
+	}
+	colorterm := strings.ToLower(getenv("COLORTERM"))
+	switch {
+	case getenv("TMUX") != "":
+		// COLORTERM may come from the outer terminal; tmux only passes 24-bit
+		// color on with the RGB feature (Tc in terminal-overrides)
+		if strings.Contains(tmuxFeatures(), "RGB") {
+			return TrueColor
+		}
+		return limited
+	case colorterm == "truecolor" || colorterm == "24bit" || strings.HasSuffix(term, "-direct"):
+		return TrueColor
+	case getenv("TERM_PROGRAM") == "Apple_Terminal":
+		// Terminal.app shows 256 colors whatever TERM says
+		return ANSI256
+	}
+	return limited
+}
+
+// xtermColors are the default colors of the xterm 256-color palette
+var xtermColors = func() [256]Color {
+	var colors [256]Color
+	for i, hex := range []string{
+		"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+		"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+	} {
+		colors[i], _ = ParseColor(hex)
+	}
+	levels := []uint8{0, 95, 135, 175, 215, 255}
+	for i := 0; i < 216; i++ {
+		colors[16+i] = Color{R: levels[i/36], G: levels[i/6%6], B: levels[i%6], A: 0xff}
+	}
+	for i := 0; i < 24; i++ {
+		gray := uint8(8 + 10*i)
+		colors[232+i] = Color{R: gray, G: gray, B: gray, A: 0xff}
+	}
+	return colors
+}()
+
+// XtermColor returns the default color of an xterm palette index
+func XtermColor(index int) Color {
+	return xtermColors[index]
+}
+
+// Nearest returns the palette index of the color closest to c in OKLab: one
+// of the 6×6×6 cube and gray ramp (16-255) for ANSI256, whose colors are the
+// same in every terminal, or of the 16 ANSI colors, at their xterm defaults,
+// for ANSI16. It returns -1 for TrueColor.
+func (p ColorProfile) Nearest(c Color) int {
+	low, high := 16, 256
+	switch p {
+	case ANSI16:
+		low, high = 0, 16
+	case TrueColor:
+		return -1
+	}
+
+	target := c.OKLab()
+	best, bestDistance := low, -1.0
+	for i := low; i < high; i++ {
+		if d := target.DistanceTo(xtermColors[i].OKLab()); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best
+}
+
+// Approximate returns a color value as terminal styling libraries take it:
+// the value itself for TrueColor, otherwise its nearest palette index.
+// Invalid values are returned as they are.
+func (p ColorProfile) Approximate(value string) string {
+	c, err := ParseColor(value)
+	if p == TrueColor || err != nil {
+		return value
+	}
+	return strconv.Itoa(p.Nearest(c))
+}
+
+// PaletteIndexes returns the nearest palette index of every color the
+// palette sets, by key
+func PaletteIndexes(p ColorPalette, profile ColorProfile) (map[string]int, error) {
+	if profile == TrueColor {
+		return nil, fmt.Errorf("truecolor colors have no palette index")
+	}
+	indexes := make(map[string]int)
+	for key, c := range paletteColors(p) {
+		indexes[key] = profile.Nearest(c)
+	}
+	return indexes, nil
+}
+
+// indexedDocument is a theme approximated by palette indexes
+type indexedDocument struct {
+	Name    string         `json:"name" yaml:"name" toml:"name"`
+	Profile string         `json:"profile" yaml:"profile" toml:"profile"` // "256" or "16"
+	Colors  map[string]int `json:"colors" yaml:"colors" toml:"colors"`
+}
+
+// MarshalIndexedTheme encodes the theme's colors as their nearest palette
+// indexes in JSON, YAML or TOML, for tools that only take palette indexes
+func MarshalIndexedTheme(t Theme, profile ColorProfile, format string) ([]byte, error) {
+	indexes, err := PaletteIndexes(t.Colors, profile)
+	if err != nil {
+		return nil, err
+	}
+	doc := indexedDocument{Name: t.Name, Profile: "256", Colors: indexes}
+	if profile == ANSI16 {
+		doc.Profile = "16"
+	}
+	return marshalDocument(doc, format)
+}
@@ -0,0 +1,98 @@
+package theme
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestDetectColorProfile verifies the profile guessed from the environment
+func TestDetectColorProfile(t *testing.T) {
+	features := "256,RGB,title"
+	defer func(original func() string) { tmuxFeatures = original }(tmuxFeatures)
+	tmuxFeatures = func() string { return features }
+
+	tests := []struct {
+		env  map[string]string
+		want ColorProfile
+	}{
+		{map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, TrueColor},
+		{map[string]string{"TERM": "xterm-256color"}, ANSI256}, // e.g. over SSH, which drops COLORTERM
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "Apple_Terminal"}, ANSI256},
+		{map[string]string{"TERM": "xterm"}, ANSI16},
+		{map[string]string{"TERM": "tmux-256color", "TMUX": "/tmp/tmux-1000/default", "COLORTERM": "truecolor"}, TrueColor},
+		{map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor", "ZAKARANDA_COLORS": "16"}, ANSI16},
+	}
+	for _, tt := range tests {
+		if got := DetectColorProfile(func(key string) string { return tt.env[key] }); got != tt.want {
+			t.Errorf("DetectColorProfile(%v) = %s, want %s", tt.env, got, tt.want)
+		}
+	}
+
+	features = "256,title" // tmux without RGB (Tc)
+	env := map[string]string{"TERM": "tmux-256color", "TMUX": "/tmp/tmux-1000/default", "COLORTERM": "truecolor"}
+	if got := DetectColorProfile(func(key string) string { return env[key] }); got != ANSI256 {
+		t.Errorf("Expected 256 colors in tmux without RGB, got %s", got)
+	}
+}
+
+// TestNearest verifies the nearest palette indexes of the cube, the gray ramp and the ANSI colors
+func TestNearest(t *testing.T) {
+	if c := XtermColor(67); c.Hex() != "#5f87af" {
+		t.Errorf("Expected index 67 to be #5f87af, got %s", c.Hex())
+	}
+	tests := []struct {
+		hex     string
+		profile ColorProfile
+		want    int
+	}{
+		{"#5f87af", ANSI256, 67},
+		{"#5f87b0", ANSI256, 67},
+		{"#303030", ANSI256, 236},
+		{"#000000", ANSI256, 16},
+		{"#ee1111", ANSI16, 9},
+		{"#282a36", ANSI16, 0},
+	}
+	for _, tt := range tests {
+		c, _ := ParseColor(tt.hex)
+		if got := tt.profile.Nearest(c); got != tt.want {
+			t.Errorf("Nearest(%s) with %s = %d, want %d", tt.hex, tt.profile, got, tt.want)
+		}
+	}
+	if got := TrueColor.Approximate("#5f87b0"); got != "#5f87b0" {
+		t.Errorf("Expected truecolor to keep the value, got %s", got)
+	}
+	if got := ANSI256.Approximate("#5f87b0"); got != "67" {
+		t.Errorf("Expected index 67, got %s", got)
+	}
+}
+
+// TestMarshalIndexedTheme verifies the palette index export
+func TestMarshalIndexedTheme(t *testing.T) {
+	nord, _ := DefaultRegistry().Lookup("Nord")
+	data, err := MarshalIndexedTheme(nord, ANSI256, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Name    string
+		Profile string
+		Colors  map[string]int
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Name != "Nord" || doc.Profile != "256" || len(doc.Colors) != 18 {
+		t.Errorf("Unexpected document %+v", doc)
+	}
+	if doc.Colors["background"] != 236 {
+		t.Errorf("Expected Nord's background as 236, got %d", doc.Colors["background"])
+	}
+
+	if _, err := MarshalIndexedTheme(nord, TrueColor, "json"); err == nil {
+		t.Error("Expected an error for truecolor")
+	}
+	if preview := NewThemePreview(nord).WithProfile(ANSI16).Render(); !strings.Contains(preview, "shows 16 colors") {
+		t.Error("Expected the preview to note the approximated colors")
+	}
+}
@@ -1,30 +1,177 @@
 package theme
 
+import (
+	"errors"
+	"strings"
+)
+
 // Theme represents a color theme with a name, description, and color palette
 type Theme struct {
-	Name        string
-	Description string
-	Colors      ColorPalette
+	Name        string       `json:"name" yaml:"name" toml:"name"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Aliases     []string     `json:"aliases,omitempty" yaml:"aliases,omitempty" toml:"aliases,omitempty"` // Other names the theme can be looked up by
+	Extends     string       `json:"extends,omitempty" yaml:"extends,omitempty" toml:"extends,omitempty"` // Name of the theme this one builds on; unset colors are inherited
+	Colors      ColorPalette `json:"colors" yaml:"colors" toml:"colors"`
+
+	// Metadata
+	Author     string       `json:"author,omitempty" yaml:"author,omitempty" toml:"author,omitempty"`
+	License    string       `json:"license,omitempty" yaml:"license,omitempty" toml:"license,omitempty"`
+	Homepage   string       `json:"homepage,omitempty" yaml:"homepage,omitempty" toml:"homepage,omitempty"`
+	Appearance Appearance   `json:"appearance,omitempty" yaml:"appearance,omitempty" toml:"appearance,omitempty"` // Computed from the background luminance when absent
+	Tags       []string     `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`
+	Source     *ThemeSource `json:"source,omitempty" yaml:"source,omitempty" toml:"source,omitempty"`          // Set on imported themes
+	Wallpaper  string       `json:"wallpaper,omitempty" yaml:"wallpaper,omitempty" toml:"wallpaper,omitempty"` // Image set as the desktop wallpaper
+
+	// SynthesizeBrights replaces bright colors that duplicate their normal
+	// color when applying (see SynthesizeBrights); integrations configured
+	// otherwise take precedence
+	SynthesizeBrights *bool `json:"synthesize_brights,omitempty" yaml:"synthesize_brights,omitempty" toml:"synthesize_brights,omitempty"`
+
+	// Resolved by ThemeLoader for themes that extend a registered theme
+	Base      string   `json:"-" yaml:"-" toml:"-"` // Registered theme at the root of the Extends chain
+	Overrides []string `json:"-" yaml:"-" toml:"-"` // Palette slots whose color differs from Base
+}
+
+// ThemeSource names the application theme an imported theme was converted
+// from, so the application itself can use the original
+type ThemeSource struct {
+	App         string `json:"app" yaml:"app" toml:"app"`                                                          // e.g. "vscode"
+	ExtensionID string `json:"extension_id,omitempty" yaml:"extension_id,omitempty" toml:"extension_id,omitempty"` // Extension providing the theme
+	Theme       string `json:"theme" yaml:"theme" toml:"theme"`                                                    // Name the application knows the theme by
 }
 
-// ColorPalette defines the color scheme for a theme
+// OfficialName returns the name integrations use to look up official app themes.
+// A theme that extends a built-in theme without visibly changing any color
+// resolves to that built-in, so the official extension or config is used.
+// A built-in whose own colors were changed, such as by bright synthesis, has none.
+func (t Theme) OfficialName() string {
+	if t.Base != "" && len(t.Overrides) == 0 {
+		return t.Base
+	}
+	if t.Base == t.Name && len(t.Overrides) > 0 {
+		return ""
+	}
+	return t.Name
+}
+
+// ColorPalette defines the color scheme for a theme.
+// Empty slots are inherited through Extends and omitted when saving.
 type ColorPalette struct {
-	Background    string
-	Foreground    string
-	Black         string
-	Red           string
-	Green         string
-	Yellow        string
-	Blue          string
-	Magenta       string
-	Cyan          string
-	White         string
-	BrightBlack   string
-	BrightRed     string
-	BrightGreen   string
-	BrightYellow  string
-	BrightBlue    string
-	BrightMagenta string
-	BrightCyan    string
-	BrightWhite   string
+	Background    string `json:"background,omitempty" yaml:"background,omitempty" toml:"background,omitempty"`
+	Foreground    string `json:"foreground,omitempty" yaml:"foreground,omitempty" toml:"foreground,omitempty"`
+	Black         string `json:"black,omitempty" yaml:"black,omitempty" toml:"black,omitempty"`
+	Red           string `json:"red,omitempty" yaml:"red,omitempty" toml:"red,omitempty"`
+	Green         string `json:"green,omitempty" yaml:"green,omitempty" toml:"green,omitempty"`
+	Yellow        string `json:"yellow,omitempty" yaml:"yellow,omitempty" toml:"yellow,omitempty"`
+	Blue          string `json:"blue,omitempty" yaml:"blue,omitempty" toml:"blue,omitempty"`
+	Magenta       string `json:"magenta,omitempty" yaml:"magenta,omitempty" toml:"magenta,omitempty"`
+	Cyan          string `json:"cyan,omitempty" yaml:"cyan,omitempty" toml:"cyan,omitempty"`
+	White         string `json:"white,omitempty" yaml:"white,omitempty" toml:"white,omitempty"`
+	BrightBlack   string `json:"bright_black,omitempty" yaml:"bright_black,omitempty" toml:"bright_black,omitempty"`
+	BrightRed     string `json:"bright_red,omitempty" yaml:"bright_red,omitempty" toml:"bright_red,omitempty"`
+	BrightGreen   string `json:"bright_green,omitempty" yaml:"bright_green,omitempty" toml:"bright_green,omitempty"`
+	BrightYellow  string `json:"bright_yellow,omitempty" yaml:"bright_yellow,omitempty" toml:"bright_yellow,omitempty"`
+	BrightBlue    string `json:"bright_blue,omitempty" yaml:"bright_blue,omitempty" toml:"bright_blue,omitempty"`
+	BrightMagenta string `json:"bright_magenta,omitempty" yaml:"bright_magenta,omitempty" toml:"bright_magenta,omitempty"`
+	BrightCyan    string `json:"bright_cyan,omitempty" yaml:"bright_cyan,omitempty" toml:"bright_cyan,omitempty"`
+	BrightWhite   string `json:"bright_white,omitempty" yaml:"bright_white,omitempty" toml:"bright_white,omitempty"`
+
+	// Optional; integrations derive these from the colors above when unset
+	Cursor              string `json:"cursor,omitempty" yaml:"cursor,omitempty" toml:"cursor,omitempty"`
+	CursorText          string `json:"cursor_text,omitempty" yaml:"cursor_text,omitempty" toml:"cursor_text,omitempty"`
+	SelectionBackground string `json:"selection_background,omitempty" yaml:"selection_background,omitempty" toml:"selection_background,omitempty"`
+	SelectionForeground string `json:"selection_foreground,omitempty" yaml:"selection_foreground,omitempty" toml:"selection_foreground,omitempty"`
+	Accent              string `json:"accent,omitempty" yaml:"accent,omitempty" toml:"accent,omitempty"` // UI highlights, e.g. Warp's tabs and blocks
+}
+
+// paletteSlot pairs a palette field's key with a pointer to its value
+type paletteSlot struct {
+	key      string
+	value    *string
+	optional bool // May stay empty in a complete palette
+}
+
+// slots returns every color slot of the palette in display order
+func (p *ColorPalette) slots() []paletteSlot {
+	return []paletteSlot{
+		{"background", &p.Background, false},
+		{"foreground", &p.Foreground, false},
+		{"black", &p.Black, false},
+		{"red", &p.Red, false},
+		{"green", &p.Green, false},
+		{"yellow", &p.Yellow, false},
+		{"blue", &p.Blue, false},
+		{"magenta", &p.Magenta, false},
+		{"cyan", &p.Cyan, false},
+		{"white", &p.White, false},
+		{"bright_black", &p.BrightBlack, false},
+		{"bright_red", &p.BrightRed, false},
+		{"bright_green", &p.BrightGreen, false},
+		{"bright_yellow", &p.BrightYellow, false},
+		{"bright_blue", &p.BrightBlue, false},
+		{"bright_magenta", &p.BrightMagenta, false},
+		{"bright_cyan", &p.BrightCyan, false},
+		{"bright_white", &p.BrightWhite, false},
+		{"cursor", &p.Cursor, true},
+		{"cursor_text", &p.CursorText, true},
+		{"selection_background", &p.SelectionBackground, true},
+		{"selection_foreground", &p.SelectionForeground, true},
+		{"accent", &p.Accent, true},
+	}
+}
+
+// Normalize validates every color in the palette and rewrites it in canonical hex form.
+// All invalid or missing colors are reported, each as a *ColorError.
+func (p *ColorPalette) Normalize() error {
+	return p.normalize("", false)
+}
+
+// normalize validates the palette, attributing errors to file.
+// With allowMissing, empty slots are left for inheritance instead of reported.
+func (p *ColorPalette) normalize(file string, allowMissing bool) error {
+	var errs []error
+	for _, slot := range p.slots() {
+		if *slot.value == "" && (allowMissing || slot.optional) {
+			continue
+		}
+		hex, err := NormalizeColor(*slot.value)
+		if err != nil {
+			errs = append(errs, &ColorError{
+				File:  file,
+				Field: "colors." + slot.key,
+				Value: *slot.value,
+				Err:   err,
+			})
+			continue
+		}
+		*slot.value = hex
+	}
+	return errors.Join(errs...)
+}
+
+// Normalize validates the theme's palette and rewrites it in canonical hex form
+func (t *Theme) Normalize() error {
+	return t.Colors.Normalize()
+}
+
+// inherit fills every empty slot with the corresponding color from parent
+func (p *ColorPalette) inherit(parent ColorPalette) {
+	parentSlots := parent.slots()
+	for i, slot := range p.slots() {
+		if *slot.value == "" {
+			*slot.value = *parentSlots[i].value
+		}
+	}
+}
+
+// diff returns the keys of slots whose color differs between the two palettes
+func (p *ColorPalette) diff(other ColorPalette) []string {
+	var keys []string
+	otherSlots := other.slots()
+	for i, slot := range p.slots() {
+		if !strings.EqualFold(*slot.value, *otherSlots[i].value) {
+			keys = append(keys, slot.key)
+		}
+	}
+	return keys
 }
@@ -3,33 +3,44 @@ package ui
 import (
 	"fmt"
 	"os"
-	"zakaranda/internal/integrations"
-	"zakaranda/internal/theme"
+	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dahromy/zakaranda/internal/config"
+	"github.com/dahromy/zakaranda/internal/integrations"
+	"github.com/dahromy/zakaranda/internal/theme"
+	"github.com/dahromy/zakaranda/pkg/zakaranda"
 )
 
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#7aa2f7")).
-			MarginBottom(1)
-
-	selectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#bb9af7")).
-			Bold(true)
-
-	normalStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#c0caf5"))
-
-	successStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9ece6a")).
-			Bold(true)
+// styles are the TUI's styles, in the colors the terminal can show
+type styles struct {
+	title, selected, normal, success, dim lipgloss.Style
+}
 
-	dimStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#565f89"))
-)
+// newStyles returns the styles with the nearest colors the profile has
+func newStyles(profile theme.ColorProfile) styles {
+	color := func(hex string) lipgloss.Color {
+		return lipgloss.Color(profile.Approximate(hex))
+	}
+	return styles{
+		title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(color("#7aa2f7")).
+			MarginBottom(1),
+		selected: lipgloss.NewStyle().
+			Foreground(color("#bb9af7")).
+			Bold(true),
+		normal: lipgloss.NewStyle().
+			Foreground(color("#c0caf5")),
+		success: lipgloss.NewStyle().
+			Foreground(color("#9ece6a")).
+			Bold(true),
+		dim: lipgloss.NewStyle().
+			Foreground(color("#565f89")),
+	}
+}
 
 type state int
 
@@ -39,13 +50,19 @@ const (
 	previewingTheme
 	selectingApps
 	selectingVSCodeVariant
+	selectingPairTheme
+	exportingTheme
 	applying
 	complete
 )
 
 type model struct {
-	baseThemes          []BaseTheme
-	themes              []Theme
+	allBaseThemes       []BaseTheme // Unfiltered theme families
+	baseThemes          []BaseTheme // Families passing the current filter
+	themes              []Theme     // baseThemes flattened, indexed by calculateThemeIndex
+	filter              theme.Filter
+	searching           bool
+	searchInput         string
 	selectedBaseTheme   int
 	selectedVariant     int
 	selectedTheme       int
@@ -57,6 +74,17 @@ type model struct {
 	results             []string
 	vscodeVariants      []VSCodeVariant
 	selectedVSCVariants map[int]bool
+	pairTheme           *Theme  // Opposite-appearance theme paired with the selected one
+	pairCandidates      []Theme // Themes offered when choosing pairTheme
+	preferredAppearance theme.Appearance
+	configManager       *config.ConfigManager
+	client              *zakaranda.Client
+	exportPath          string             // Path typed in the export prompt
+	statusMessage       string             // Result of the last export or derive, or a failure to save the preferred appearance
+	deficiency          theme.Deficiency   // Color vision deficiency simulated in the preview
+	showColorGrid       bool               // Show every color, and synthesized brights, below the preview
+	colorProfile        theme.ColorProfile // Colors the terminal can show; the preview uses the nearest ones
+	styles              styles
 }
 
 // Type aliases for imported types
@@ -67,14 +95,27 @@ type ThemeVariant = theme.ThemeVariant
 type VSCodeVariant = integrations.VSCodeVariant
 
 func initialModel() model {
-	baseThemes := theme.GetBuiltInBaseThemes()
-	themes := theme.GetBuiltInThemes()
-	apps := integrations.GetAllIntegrations()
+	cm, err := config.NewConfigManager()
+	if err != nil {
+		fmt.Printf("Warning: Could not load config, custom themes disabled: %v\n", err)
+	}
+
+	var opts []zakaranda.Option
+	if cm != nil {
+		opts = append(opts, zakaranda.WithBrightSynthesis(cm.GetBrightSynthesis()))
+	}
+	client := zakaranda.New(opts...)
+	baseThemes := loadBaseThemes(client, cm)
+	// Flatten in family order so calculateThemeIndex matches
+	themes := theme.FlattenBaseThemes(baseThemes)
+	apps := client.Integrations()
 
 	// Cache VS Code variants during initialization to avoid repeated calls
 	vscodeVariants := integrations.GetVSCodeVariants()
+	profile := theme.DetectColorProfile(os.Getenv)
 
 	return model{
+		allBaseThemes:       baseThemes,
 		baseThemes:          baseThemes,
 		themes:              themes,
 		selectedBaseTheme:   0,
@@ -86,7 +127,37 @@ func initialModel() model {
 		state:               selectingTheme,
 		vscodeVariants:      vscodeVariants,
 		selectedVSCVariants: make(map[int]bool),
+		preferredAppearance: preferredAppearance(cm),
+		configManager:       cm,
+		client:              client,
+		colorProfile:        profile,
+		styles:              newStyles(profile),
+	}
+}
+
+// preferredAppearance returns the configured side of a pair for apps that
+// can't follow the system appearance
+func preferredAppearance(cm *config.ConfigManager) theme.Appearance {
+	if cm != nil && cm.GetPreferredAppearance() == "light" {
+		return theme.AppearanceLight
+	}
+	return theme.AppearanceDark
+}
+
+// loadBaseThemes registers the Alacritty community themes and the custom
+// themes and families from the configured themes directory in the client's
+// registry and returns all registered families
+func loadBaseThemes(client *zakaranda.Client, cm *config.ConfigManager) []BaseTheme {
+	// Custom themes may extend or join the Alacritty community family
+	if err := client.LoadAlacrittyThemes(); err != nil {
+		fmt.Printf("Warning: Could not load some Alacritty themes: %v\n", err)
+	}
+	if cm != nil {
+		if err := client.LoadThemes(cm.GetCustomThemesPath()); err != nil {
+			fmt.Printf("Warning: Could not load some custom themes: %v\n", err)
+		}
 	}
+	return client.Registry().Families()
 }
 
 func (m model) Init() tea.Cmd {
@@ -96,6 +167,14 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// While typing a search query, keys edit the query instead of navigating
+		if m.searching {
+			return m.updateSearch(msg), nil
+		}
+		if m.state == exportingTheme {
+			return m.updateExport(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -117,6 +196,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.cursor > 0 {
 					m.cursor--
 				}
+			} else if m.state == selectingPairTheme {
+				if m.cursor > 0 {
+					m.cursor--
+				}
 			}
 
 		case "down", "j":
@@ -137,9 +220,93 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.cursor < len(m.vscodeVariants)-1 {
 					m.cursor++
 				}
+			} else if m.state == selectingPairTheme {
+				if m.cursor < len(m.pairCandidates)-1 {
+					m.cursor++
+				}
+			}
+
+		case "/":
+			if m.state == selectingTheme {
+				m.searching = true
+				m.searchInput = m.filter.String()
+			}
+
+		case "a":
+			// Cycle the appearance filter: all → dark → light
+			if m.state == selectingTheme {
+				switch m.filter.Appearance {
+				case "":
+					m.filter.Appearance = theme.AppearanceDark
+				case theme.AppearanceDark:
+					m.filter.Appearance = theme.AppearanceLight
+				default:
+					m.filter.Appearance = ""
+				}
+				m.applyFilter()
+			}
+
+		case "l":
+			// Pair the previewed theme with one of the opposite appearance
+			if m.state == previewingTheme {
+				m.pairCandidates = oppositeThemes(m.themes[m.selectedTheme], m.allBaseThemes)
+				if len(m.pairCandidates) > 0 {
+					m.cursor = 0
+					m.state = selectingPairTheme
+				}
+			}
+
+		case "e":
+			// Export the previewed theme to a file
+			if m.state == previewingTheme {
+				name := theme.SanitizeFileName(m.themes[m.selectedTheme].Name)
+				m.exportPath = filepath.Join("~", name+"."+theme.ThemeFormats[0])
+				m.statusMessage = ""
+				m.state = exportingTheme
+			}
+
+		case "d":
+			// Derive a variant with the other appearance into the family
+			if m.state == selectingVariant {
+				m.selectedVariant = m.cursor
+				m.selectedTheme = m.calculateThemeIndex()
+				m.statusMessage = m.deriveVariant()
+			} else if m.state == previewingTheme {
+				m.statusMessage = m.deriveVariant()
+			}
+
+		case "c":
+			// Cycle through the simulated color vision deficiencies
+			if m.state == previewingTheme {
+				m.deficiency = nextDeficiency(m.deficiency)
+			}
+
+		case "g":
+			// Toggle the color grid with the bright synthesis comparison
+			if m.state == previewingTheme {
+				m.showColorGrid = !m.showColorGrid
+			}
+
+		case "s":
+			// Switch which side of the pair other apps get
+			if m.state == selectingApps && m.pairTheme != nil {
+				if m.preferredAppearance == theme.AppearanceLight {
+					m.preferredAppearance = theme.AppearanceDark
+				} else {
+					m.preferredAppearance = theme.AppearanceLight
+				}
+				m.statusMessage = ""
+				if m.configManager != nil {
+					if err := m.configManager.SetPreferredAppearance(string(m.preferredAppearance)); err != nil {
+						m.statusMessage = fmt.Sprintf("❌ Could not save the %s preference: %v", m.preferredAppearance, err)
+					}
+				}
 			}
 
 		case "enter":
+			if m.state == selectingTheme && len(m.baseThemes) == 0 {
+				break
+			}
 			if m.state == selectingTheme {
 				m.selectedBaseTheme = m.cursor
 				baseTheme := m.baseThemes[m.selectedBaseTheme]
@@ -148,6 +315,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedVariant = 0
 					// Calculate the theme index in the flattened themes list
 					m.selectedTheme = m.calculateThemeIndex()
+					m.pairTheme = nil
 					m.state = previewingTheme
 				} else {
 					// Show variant selection
@@ -156,17 +324,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			} else if m.state == selectingVariant {
 				m.selectedVariant = m.cursor
+				m.statusMessage = ""
 				// Calculate the theme index in the flattened themes list
 				m.selectedTheme = m.calculateThemeIndex()
+				m.pairTheme = nil
+				m.state = previewingTheme
+			} else if m.state == selectingPairTheme {
+				pairTheme := m.pairCandidates[m.cursor]
+				m.pairTheme = &pairTheme
+				m.cursor = 0
 				m.state = previewingTheme
 			} else if m.state == previewingTheme {
 				m.cursor = 0
+				m.statusMessage = ""
 				m.state = selectingApps
 			} else if m.state == selectingApps {
 				// Check if VS Code is selected
 				vscodeSelected := false
 				for idx, selected := range m.selectedApps {
-					if selected && m.apps[idx].Name() == "VS Code" {
+					if _, ok := m.apps[idx].(*integrations.VSCodeIntegration); ok && selected {
 						vscodeSelected = true
 						break
 					}
@@ -201,17 +377,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "p":
-			// Toggle preview from theme selection
-			if m.state == selectingTheme {
-				m.selectedTheme = m.cursor
+			// Toggle preview from theme selection (previews the first variant)
+			if m.state == selectingTheme && len(m.baseThemes) > 0 {
+				m.selectedBaseTheme = m.cursor
+				m.selectedVariant = 0
+				m.selectedTheme = m.calculateThemeIndex()
+				m.pairTheme = nil
 				m.state = previewingTheme
 			}
 
 		case "esc":
-			if m.state == selectingVariant {
+			if m.state == selectingTheme && !m.filter.IsEmpty() {
+				// Clear the filter
+				m.filter = theme.Filter{}
+				m.applyFilter()
+			} else if m.state == selectingVariant {
+				m.statusMessage = ""
 				m.state = selectingTheme
 				m.cursor = m.selectedBaseTheme
+			} else if m.state == selectingPairTheme {
+				m.cursor = 0
+				m.state = previewingTheme
+			} else if m.state == previewingTheme && m.pairTheme != nil {
+				// Drop the pair before leaving the preview
+				m.pairTheme = nil
 			} else if m.state == previewingTheme {
+				m.statusMessage = ""
 				// Go back to variant selection if theme has multiple variants
 				baseTheme := m.baseThemes[m.selectedBaseTheme]
 				if len(baseTheme.Variants) > 1 {
@@ -224,6 +415,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.state == selectingApps {
 				m.state = previewingTheme
 				m.cursor = 0
+				m.statusMessage = ""
 				m.selectedApps = make(map[int]bool)
 			} else if m.state == selectingVSCodeVariant {
 				m.cursor = 0
@@ -241,11 +433,183 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateSearch edits the search query; the filter is applied as the user types
+func (m model) updateSearch(msg tea.KeyMsg) model {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		return m
+	case tea.KeyEsc:
+		m.searching = false
+		m.searchInput = ""
+	case tea.KeyBackspace:
+		if runes := []rune(m.searchInput); len(runes) > 0 {
+			m.searchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.searchInput += " "
+	case tea.KeyRunes:
+		m.searchInput += string(msg.Runes)
+	default:
+		return m
+	}
+
+	// Keep the last valid filter while an appearance token is half typed
+	if filter, err := theme.ParseFilter(m.searchInput); err == nil {
+		m.filter = filter
+		m.applyFilter()
+	}
+	return m
+}
+
+// updateExport edits the export path; tab switches the format by changing the extension
+func (m model) updateExport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.state = previewingTheme
+	case tea.KeyEnter:
+		m.statusMessage = m.exportTheme()
+		m.state = previewingTheme
+	case tea.KeyTab:
+		m.exportPath = strings.TrimSuffix(m.exportPath, filepath.Ext(m.exportPath)) + "." + nextFormat(exportFormat(m.exportPath))
+	case tea.KeyBackspace:
+		if runes := []rune(m.exportPath); len(runes) > 0 {
+			m.exportPath = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.exportPath += " "
+	case tea.KeyRunes:
+		m.exportPath += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// exportTheme writes the previewed theme to the export path and describes the result
+func (m model) exportTheme() string {
+	path := m.exportPath
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Sprintf("❌ Export failed: %v", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	t := m.themes[m.selectedTheme]
+	if err := theme.NewThemeLoader("").ExportTheme(t, path); err != nil {
+		return fmt.Sprintf("❌ Export failed: %v", err)
+	}
+	return fmt.Sprintf("✅ Exported %s to %s", t.Name, path)
+}
+
+// deriveVariant saves a variant of the selected theme with the other
+// appearance in its family, reloads the themes and describes the result
+func (m *model) deriveVariant() string {
+	if m.configManager == nil {
+		return "❌ Derive failed: custom themes are disabled"
+	}
+	t := m.themes[m.selectedTheme]
+	family := m.baseThemes[m.selectedBaseTheme].Name
+	appearance := theme.AppearanceLight
+	if t.IsLight() {
+		appearance = theme.AppearanceDark
+	}
+
+	derived, err := theme.DeriveAppearance(t, appearance)
+	if err != nil {
+		return fmt.Sprintf("❌ Derive failed: %v", err)
+	}
+	loader := theme.NewThemeLoader(m.configManager.GetCustomThemesPath())
+	if err := loader.SaveCustomVariant(family, derived, theme.ThemeFormats[0]); err != nil {
+		return fmt.Sprintf("❌ Derive failed: %v", err)
+	}
+
+	// Reload, keeping the family and variant selected
+	m.allBaseThemes = loadBaseThemes(m.client, m.configManager)
+	m.baseThemes = theme.FilterBaseThemes(m.allBaseThemes, m.filter)
+	m.themes = theme.FlattenBaseThemes(m.baseThemes)
+	for i, baseTheme := range m.baseThemes {
+		if baseTheme.Name == family {
+			m.selectedBaseTheme = i
+		}
+	}
+	m.selectedTheme = m.calculateThemeIndex()
+	return fmt.Sprintf("✅ Added %s to %s", derived.Name, family)
+}
+
+// exportFormat returns the format named by the path's extension
+func exportFormat(path string) string {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if format == "yml" {
+		return "yaml"
+	}
+	return format
+}
+
+// nextFormat returns the export format after format, wrapping around
+func nextFormat(format string) string {
+	for i, f := range theme.ThemeFormats {
+		if f == format {
+			return theme.ThemeFormats[(i+1)%len(theme.ThemeFormats)]
+		}
+	}
+	return theme.ThemeFormats[0]
+}
+
+// applyFilter recomputes the visible families from the current filter
+func (m *model) applyFilter() {
+	m.baseThemes = theme.FilterBaseThemes(m.allBaseThemes, m.filter)
+	m.themes = theme.FlattenBaseThemes(m.baseThemes)
+	m.cursor = 0
+	m.selectedBaseTheme = 0
+	m.selectedVariant = 0
+	m.selectedTheme = 0
+}
+
 type applyCompleteMsg struct {
 	results []string
 	err     error
 }
 
+// nextDeficiency returns the deficiency simulated after d, back to normal
+// vision after the last one
+func nextDeficiency(d theme.Deficiency) theme.Deficiency {
+	for i, candidate := range theme.Deficiencies {
+		if candidate == d && i+1 < len(theme.Deficiencies) {
+			return theme.Deficiencies[i+1]
+		}
+	}
+	if d == theme.NormalVision {
+		return theme.Deficiencies[0]
+	}
+	return theme.NormalVision
+}
+
+// oppositeThemes returns every theme whose appearance differs from t
+func oppositeThemes(t Theme, baseThemes []BaseTheme) []Theme {
+	var themes []Theme
+	for _, candidate := range theme.FlattenBaseThemes(baseThemes) {
+		if candidate.IsLight() != t.IsLight() {
+			themes = append(themes, candidate)
+		}
+	}
+	return themes
+}
+
+// selectedPair returns the light/dark pair, if a pair theme was chosen
+func (m *model) selectedPair() (theme.Pair, bool) {
+	if m.pairTheme == nil {
+		return theme.Pair{}, false
+	}
+	pair, err := theme.NewPair(m.themes[m.selectedTheme], *m.pairTheme)
+	if err != nil {
+		return theme.Pair{}, false
+	}
+	return pair, true
+}
+
 // calculateThemeIndex calculates the index of the selected theme in the flattened themes list
 func (m *model) calculateThemeIndex() int {
 	index := 0
@@ -259,72 +623,39 @@ func (m *model) calculateThemeIndex() int {
 func (m model) applyThemes() tea.Cmd {
 	return func() tea.Msg {
 		var results []string
-		theme := m.themes[m.selectedTheme]
-
-		for idx, selected := range m.selectedApps {
-			if selected {
-				app := m.apps[idx]
-
-				// Special handling for VS Code - apply to all selected variants
-				if app.Name() == "VS Code" {
-					vscode, ok := app.(*integrations.VSCodeIntegration)
-					if !ok {
-						results = append(results, fmt.Sprintf("❌ VS Code: Type assertion failed"))
-						continue
-					}
-
-					// Check if any variants were selected
-					hasSelectedVariants := false
-					for _, variantSelected := range m.selectedVSCVariants {
-						if variantSelected {
-							hasSelectedVariants = true
-							break
-						}
-					}
-
-					if !hasSelectedVariants {
-						results = append(results, fmt.Sprintf("⚠️  VS Code: No variants selected"))
-						continue
-					}
+		req := zakaranda.Request{Theme: m.themes[m.selectedTheme], Preferred: m.preferredAppearance}
+		if pair, ok := m.selectedPair(); ok {
+			req.Pair = &pair
+		}
 
-					// Apply theme to each selected variant
-					for variantIdx, variantSelected := range m.selectedVSCVariants {
-						if variantSelected && variantIdx < len(m.vscodeVariants) {
-							variant := m.vscodeVariants[variantIdx]
-
-							// Check if this specific variant is installed
-							_, appErr := os.Stat(variant.AppPath)
-							_, configErr := os.Stat(variant.ConfigDir)
-							if appErr != nil && configErr != nil {
-								results = append(results, fmt.Sprintf("⚠️  %s: Not installed or not found", variant.Name))
-								continue
-							}
-
-							vscode.SetVariant(variant)
-
-							err := vscode.Apply(theme)
-							if err != nil {
-								results = append(results, fmt.Sprintf("❌ %s: %v", variant.Name, err))
-							} else {
-								results = append(results, fmt.Sprintf("✅ %s: Theme applied successfully", variant.Name))
-							}
-						}
-					}
-					continue
-				}
+		for idx, app := range m.apps {
+			if !m.selectedApps[idx] {
+				continue
+			}
 
-				// For non-VSCode apps, apply normally
-				if !app.IsInstalled() {
-					results = append(results, fmt.Sprintf("⚠️  %s: Not installed or not found", app.Name()))
-					continue
+			// VS Code is applied to each selected variant
+			vscode, ok := app.(*integrations.VSCodeIntegration)
+			if !ok {
+				req.Integrations = append(req.Integrations, app)
+				continue
+			}
+			hasSelectedVariants := false
+			for variantIdx, variant := range m.vscodeVariants {
+				if m.selectedVSCVariants[variantIdx] {
+					req.Integrations = append(req.Integrations, vscode.WithVariant(variant))
+					hasSelectedVariants = true
 				}
+			}
+			if !hasSelectedVariants {
+				results = append(results, "⚠️  VS Code: No variants selected")
+			}
+		}
 
-				err := app.Apply(theme)
-				if err != nil {
-					results = append(results, fmt.Sprintf("❌ %s: %v", app.Name(), err))
-				} else {
-					results = append(results, fmt.Sprintf("✅ %s: Theme applied successfully", app.Name()))
-				}
+		if len(req.Integrations) > 0 {
+			// Failures are reported in the results
+			applied, _ := m.client.Apply(req)
+			for _, result := range applied {
+				results = append(results, result.String())
 			}
 		}
 
@@ -332,48 +663,142 @@ func (m model) applyThemes() tea.Cmd {
 	}
 }
 
+// maxListRows is how many entries of a long list are shown at once
+const maxListRows = 15
+
+// listWindow returns the range of a list of total entries to show so that
+// the cursor stays visible
+func listWindow(cursor, total int) (start, end int) {
+	if total <= maxListRows {
+		return 0, total
+	}
+	start = cursor - maxListRows/2
+	start = max(0, min(start, total-maxListRows))
+	return start, start + maxListRows
+}
+
+// moreAbove and moreBelow render how many entries are scrolled out of view
+func (m model) moreAbove(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return m.styles.dim.Render(fmt.Sprintf("  ↑ %d more", n)) + "\n"
+}
+
+func (m model) moreBelow(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return m.styles.dim.Render(fmt.Sprintf("  ↓ %d more", n)) + "\n"
+}
+
 func (m model) View() string {
-	s := titleStyle.Render("🎨 Theme Manager") + "\n\n"
+	s := m.styles.title.Render("🎨 Theme Manager") + "\n\n"
 
 	switch m.state {
 	case selectingTheme:
-		s += normalStyle.Render("Select a theme:") + "\n\n"
+		s += m.styles.normal.Render("Select a theme:") + "\n"
+		if m.searching {
+			s += m.styles.selected.Render(fmt.Sprintf("/ %s█", m.searchInput)) + "\n"
+		} else if !m.filter.IsEmpty() {
+			s += m.styles.dim.Render(fmt.Sprintf("Filter: %s", m.filter.String())) + "\n"
+		}
+		s += "\n"
+		if len(m.baseThemes) == 0 {
+			s += m.styles.dim.Render("  No themes match the filter") + "\n"
+		}
 		for i, baseTheme := range m.baseThemes {
 			cursor := " "
 			if m.cursor == i {
 				cursor = ">"
-				s += selectedStyle.Render(fmt.Sprintf("%s %s", cursor, baseTheme.Name)) + "\n"
-				s += dimStyle.Render(fmt.Sprintf("  %s", baseTheme.Description)) + "\n"
+				s += m.styles.selected.Render(fmt.Sprintf("%s %s", cursor, baseTheme.Name)) + "\n"
+				s += m.styles.dim.Render(fmt.Sprintf("  %s", baseTheme.Description)) + "\n"
+				if details := familyDetails(baseTheme); details != "" {
+					s += m.styles.dim.Render(fmt.Sprintf("  %s", details)) + "\n"
+				}
 			} else {
-				s += normalStyle.Render(fmt.Sprintf("%s %s", cursor, baseTheme.Name)) + "\n"
+				s += m.styles.normal.Render(fmt.Sprintf("%s %s", cursor, baseTheme.Name)) + "\n"
 			}
 		}
-		s += "\n" + dimStyle.Render("↑/↓: navigate • enter: select • q: quit")
+		if m.searching {
+			s += "\n" + m.styles.dim.Render("type to search (tag:name, light, dark) • enter: done • esc: cancel")
+		} else {
+			s += "\n" + m.styles.dim.Render("↑/↓: navigate • enter: select • /: search • a: light/dark • esc: clear filter • q: quit")
+		}
 
 	case selectingVariant:
 		baseTheme := m.baseThemes[m.selectedBaseTheme]
-		s += successStyle.Render(fmt.Sprintf("Theme: %s", baseTheme.Name)) + "\n\n"
-		s += normalStyle.Render("Select variant:") + "\n\n"
-		for i, variant := range baseTheme.Variants {
+		s += m.styles.success.Render(fmt.Sprintf("Theme: %s", baseTheme.Name)) + "\n\n"
+		s += m.styles.normal.Render("Select variant:") + "\n\n"
+		start, end := listWindow(m.cursor, len(baseTheme.Variants))
+		s += m.moreAbove(start)
+		for i := start; i < end; i++ {
+			variant := baseTheme.Variants[i]
 			cursor := " "
 			if m.cursor == i {
 				cursor = ">"
-				s += selectedStyle.Render(fmt.Sprintf("%s %s", cursor, variant.DisplayName)) + "\n"
+				s += m.styles.selected.Render(fmt.Sprintf("%s %s", cursor, variant.DisplayName)) + "\n"
 			} else {
-				s += normalStyle.Render(fmt.Sprintf("%s %s", cursor, variant.DisplayName)) + "\n"
+				s += m.styles.normal.Render(fmt.Sprintf("%s %s", cursor, variant.DisplayName)) + "\n"
 			}
 		}
-		s += "\n" + dimStyle.Render("↑/↓: navigate • enter: preview • esc: back • q: quit")
+		s += m.moreBelow(len(baseTheme.Variants) - end)
+		if m.statusMessage != "" {
+			s += "\n" + m.statusMessage + "\n"
+		}
+		s += "\n" + m.styles.dim.Render("↑/↓: navigate • enter: preview • d: derive light/dark variant • esc: back • q: quit")
 
 	case previewingTheme:
 		themeToPreview := m.themes[m.selectedTheme]
-		preview := theme.NewThemePreview(themeToPreview)
+		preview := theme.NewThemePreview(themeToPreview).Simulating(m.deficiency).WithProfile(m.colorProfile)
 		s += preview.Render()
-		s += "\n" + dimStyle.Render("enter: continue to app selection • esc: back • q: quit")
+		if m.showColorGrid {
+			s += "\n\n" + preview.RenderColorGrid()
+		}
+		if m.statusMessage != "" {
+			s += "\n" + m.statusMessage + "\n"
+		}
+		if m.pairTheme != nil {
+			s += "\n" + m.styles.success.Render(fmt.Sprintf("Paired with: %s", m.pairTheme.Name)) + "\n"
+			s += "\n" + m.styles.dim.Render("enter: continue to app selection • l: change pair • e: export • d: derive light/dark variant • c: color blindness • g: color grid • esc: remove pair • q: quit")
+		} else {
+			s += "\n" + m.styles.dim.Render("enter: continue to app selection • l: pair with light/dark theme • e: export • d: derive light/dark variant • c: color blindness • g: color grid • esc: back • q: quit")
+		}
+
+	case exportingTheme:
+		s += m.styles.success.Render(fmt.Sprintf("Export: %s", m.themes[m.selectedTheme].Name)) + "\n\n"
+		s += m.styles.normal.Render(fmt.Sprintf("Format: %s", exportFormat(m.exportPath))) + "\n"
+		s += m.styles.normal.Render("Path:") + "\n"
+		s += m.styles.selected.Render(fmt.Sprintf("> %s█", m.exportPath)) + "\n"
+		s += "\n" + m.styles.dim.Render("type a path • tab: toml/yaml/json • enter: export • esc: cancel")
+
+	case selectingPairTheme:
+		current := m.themes[m.selectedTheme]
+		s += m.styles.success.Render(fmt.Sprintf("Theme: %s (%s)", current.Name, current.ResolvedAppearance())) + "\n\n"
+		s += m.styles.normal.Render("Select a theme to pair with:") + "\n\n"
+		start, end := listWindow(m.cursor, len(m.pairCandidates))
+		s += m.moreAbove(start)
+		for i := start; i < end; i++ {
+			candidate := m.pairCandidates[i]
+			cursor := " "
+			if m.cursor == i {
+				cursor = ">"
+				s += m.styles.selected.Render(fmt.Sprintf("%s %s", cursor, candidate.Name)) + "\n"
+			} else {
+				s += m.styles.normal.Render(fmt.Sprintf("%s %s", cursor, candidate.Name)) + "\n"
+			}
+		}
+		s += m.moreBelow(len(m.pairCandidates) - end)
+		s += "\n" + m.styles.dim.Render("↑/↓: navigate • enter: pair • esc: back • q: quit")
 
 	case selectingApps:
-		s += successStyle.Render(fmt.Sprintf("Theme: %s", m.themes[m.selectedTheme].Name)) + "\n\n"
-		s += normalStyle.Render("Select applications to theme:") + "\n\n"
+		pair, hasPair := m.selectedPair()
+		if hasPair {
+			s += m.styles.success.Render(fmt.Sprintf("Themes: %s + %s", pair.Light.Name, pair.Dark.Name)) + "\n\n"
+		} else {
+			s += m.styles.success.Render(fmt.Sprintf("Theme: %s", m.themes[m.selectedTheme].Name)) + "\n\n"
+		}
+		s += m.styles.normal.Render("Select applications to theme:") + "\n\n"
 		for i, app := range m.apps {
 			cursor := " "
 			checkbox := "[ ]"
@@ -382,26 +807,41 @@ func (m model) View() string {
 			}
 
 			status := ""
+			if hasPair {
+				// Show which side of the pair each app receives
+				if _, ok := app.(integrations.AppearanceIntegration); ok {
+					status = m.styles.dim.Render(" (light + dark)")
+				} else {
+					status = m.styles.dim.Render(fmt.Sprintf(" (%s)", m.preferredAppearance))
+				}
+			}
 			if !app.IsInstalled() {
-				status = dimStyle.Render(" (not found)")
+				status += m.styles.dim.Render(" (not found)")
 			}
 
 			if m.cursor == i {
 				cursor = ">"
-				s += selectedStyle.Render(fmt.Sprintf("%s %s %s%s", cursor, checkbox, app.Name(), status)) + "\n"
+				s += m.styles.selected.Render(fmt.Sprintf("%s %s %s%s", cursor, checkbox, app.Name(), status)) + "\n"
 				// Only show config path if it's not empty
 				if configPath := app.ConfigPath(); configPath != "" {
-					s += dimStyle.Render(fmt.Sprintf("   %s", configPath)) + "\n"
+					s += m.styles.dim.Render(fmt.Sprintf("   %s", configPath)) + "\n"
 				}
 			} else {
-				s += normalStyle.Render(fmt.Sprintf("%s %s %s%s", cursor, checkbox, app.Name(), status)) + "\n"
+				s += m.styles.normal.Render(fmt.Sprintf("%s %s %s%s", cursor, checkbox, app.Name(), status)) + "\n"
 			}
 		}
-		s += "\n" + dimStyle.Render("↑/↓: navigate • space: toggle • enter: apply • esc: back • q: quit")
+		if m.statusMessage != "" {
+			s += "\n" + m.statusMessage + "\n"
+		}
+		if hasPair {
+			s += "\n" + m.styles.dim.Render("↑/↓: navigate • space: toggle • s: switch light/dark for other apps • enter: apply • esc: back • q: quit")
+		} else {
+			s += "\n" + m.styles.dim.Render("↑/↓: navigate • space: toggle • enter: apply • esc: back • q: quit")
+		}
 
 	case selectingVSCodeVariant:
-		s += successStyle.Render(fmt.Sprintf("Theme: %s", m.themes[m.selectedTheme].Name)) + "\n\n"
-		s += normalStyle.Render("Select VS Code variants (multiple allowed):") + "\n\n"
+		s += m.styles.success.Render(fmt.Sprintf("Theme: %s", m.themes[m.selectedTheme].Name)) + "\n\n"
+		s += m.styles.normal.Render("Select VS Code variants (multiple allowed):") + "\n\n"
 		for i, variant := range m.vscodeVariants {
 			cursor := " "
 			checkbox := "[ ]"
@@ -412,35 +852,47 @@ func (m model) View() string {
 
 			// Check if variant is actually installed
 			if _, err := os.Stat(variant.AppPath); err == nil {
-				status = successStyle.Render(" ✓")
+				status = m.styles.success.Render(" ✓")
 			} else {
-				status = dimStyle.Render(" (app not found)")
+				status = m.styles.dim.Render(" (app not found)")
 			}
 
 			if m.cursor == i {
 				cursor = ">"
-				s += selectedStyle.Render(fmt.Sprintf("%s %s %s%s", cursor, checkbox, variant.Name, status)) + "\n"
-				s += dimStyle.Render(fmt.Sprintf("   Config: %s", variant.ConfigDir)) + "\n"
+				s += m.styles.selected.Render(fmt.Sprintf("%s %s %s%s", cursor, checkbox, variant.Name, status)) + "\n"
+				s += m.styles.dim.Render(fmt.Sprintf("   Config: %s", variant.ConfigDir)) + "\n"
 			} else {
-				s += normalStyle.Render(fmt.Sprintf("%s %s %s%s", cursor, checkbox, variant.Name, status)) + "\n"
+				s += m.styles.normal.Render(fmt.Sprintf("%s %s %s%s", cursor, checkbox, variant.Name, status)) + "\n"
 			}
 		}
-		s += "\n" + dimStyle.Render("↑/↓: navigate • space: toggle • enter: apply • esc: back • q: quit")
+		s += "\n" + m.styles.dim.Render("↑/↓: navigate • space: toggle • enter: apply • esc: back • q: quit")
 
 	case applying:
-		s += normalStyle.Render("Applying themes...") + "\n"
+		s += m.styles.normal.Render("Applying themes...") + "\n"
 
 	case complete:
-		s += successStyle.Render("✨ Theme Application Complete!") + "\n\n"
+		s += m.styles.success.Render("✨ Theme Application Complete!") + "\n\n"
 		for _, result := range m.results {
 			s += result + "\n"
 		}
-		s += "\n" + dimStyle.Render("Press enter or q to quit")
+		s += "\n" + m.styles.dim.Render("Press enter or q to quit")
 	}
 
 	return s + "\n"
 }
 
+// familyDetails formats a family's author and tags for the theme list
+func familyDetails(baseTheme BaseTheme) string {
+	var parts []string
+	if baseTheme.Author != "" {
+		parts = append(parts, "by "+baseTheme.Author)
+	}
+	if len(baseTheme.Tags) > 0 {
+		parts = append(parts, "#"+strings.Join(baseTheme.Tags, " #"))
+	}
+	return strings.Join(parts, " • ")
+}
+
 // Run starts the TUI application
 func Run() error {
 	p := tea.NewProgram(initialModel())
@@ -0,0 +1,63 @@
+package zakaranda
+
+import "fmt"
+
+// Status is the outcome of one integration's part of an apply or restore
+type Status int
+
+const (
+	Applied Status = iota
+	Skipped
+	Failed
+	Planned // Dry run: nothing was written
+	Restored
+)
+
+func (s Status) String() string {
+	switch s {
+	case Applied:
+		return "applied"
+	case Skipped:
+		return "skipped"
+	case Failed:
+		return "failed"
+	case Planned:
+		return "planned"
+	case Restored:
+		return "restored"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Result is the outcome of applying or restoring one integration
+type Result struct {
+	Integration string
+	Status      Status
+	Theme       string // Empty for restores
+	Message     string
+	Err         error    // Set when Status is Failed
+	Notices     []string // Manual steps and steps skipped or failed without failing the apply
+}
+
+// String returns the result as the line the TUI and CLI show, followed by
+// an indented line for each notice
+func (r Result) String() string {
+	var line string
+	switch r.Status {
+	case Failed:
+		line = fmt.Sprintf("❌ %s: %v", r.Integration, r.Err)
+	case Skipped:
+		line = fmt.Sprintf("⚠️  %s: %s", r.Integration, r.Message)
+	case Planned:
+		line = fmt.Sprintf("• %s: %s", r.Integration, r.Message)
+	default:
+		line = fmt.Sprintf("✅ %s: %s", r.Integration, r.Message)
+	}
+	for _, notice := range r.Notices {
+		line += "\n   " + notice
+	}
+	return line
+}
+
+// Reporter receives each result as soon as it is known
+type Reporter func(Result)
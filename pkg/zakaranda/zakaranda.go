@@ -0,0 +1,444 @@
+// Package zakaranda lets Go programs load themes and apply them to
+// applications the same way the zakaranda TUI and CLI do.
+//
+//	client := zakaranda.New(zakaranda.WithDryRun(true))
+//	t, err := client.Lookup("Catppuccin Mocha")
+//	if err != nil {
+//		return err
+//	}
+//	results, err := client.Apply(zakaranda.Request{Theme: t, Apps: []string{"Alacritty", "Zed"}})
+package zakaranda
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dahromy/zakaranda/internal/importers"
+	"github.com/dahromy/zakaranda/internal/integrations"
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// Types shared with the TUI and CLI
+type (
+	Theme                 = theme.Theme
+	ColorPalette          = theme.ColorPalette
+	BaseTheme             = theme.BaseTheme
+	ThemeVariant          = theme.ThemeVariant
+	Pair                  = theme.Pair
+	Appearance            = theme.Appearance
+	Registry              = theme.Registry
+	RegistryEvent         = theme.RegistryEvent
+	Integration           = integrations.Integration
+	AppearanceIntegration = integrations.AppearanceIntegration
+	BackupIntegration     = integrations.BackupIntegration
+	PaletteIntegration    = integrations.PaletteIntegration
+	NoticeIntegration     = integrations.NoticeIntegration
+)
+
+const (
+	AppearanceDark  = theme.AppearanceDark
+	AppearanceLight = theme.AppearanceLight
+)
+
+// Client loads themes and applies them to integrations. Its methods are
+// safe for concurrent use.
+type Client struct {
+	root     string // Stands in for the user's home directory
+	sandbox  bool   // Skip changes outside root (see WithSystemChanges)
+	system   *bool  // Set by WithSystemChanges
+	dryRun   bool
+	reporter Reporter
+	registry *Registry
+	brights  map[string]bool // Bright synthesis by lowercase app name
+
+	mu        sync.Mutex
+	loaders   map[string]*theme.ThemeLoader // By themes directory, so reloading replaces
+	alacritty []string                      // Full names of the registered Alacritty community themes
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithRoot makes integrations read and write their configuration below dir
+// instead of the user's home directory. Unless WithSystemChanges(true) is
+// given too, applying then skips the steps that change the system outside
+// dir: setting the desktop picture, installing VS Code extensions, copying to
+// the clipboard, opening Slack and downloading the alacritty-theme
+// repository. Skipped steps are listed in each result's Notices.
+func WithRoot(dir string) Option {
+	return func(c *Client) {
+		c.root = dir
+	}
+}
+
+// WithDryRun makes Apply and Restore report what they would do without
+// writing any files
+func WithDryRun(dryRun bool) Option {
+	return func(c *Client) {
+		c.dryRun = dryRun
+	}
+}
+
+// WithSystemChanges allows or skips the steps that change the system outside
+// the root (see WithRoot). They are allowed by default without WithRoot and
+// skipped with it.
+func WithSystemChanges(allow bool) Option {
+	return func(c *Client) {
+		c.system = &allow
+	}
+}
+
+// WithReporter calls r with each result as soon as it is known
+func WithReporter(r Reporter) Option {
+	return func(c *Client) {
+		c.reporter = r
+	}
+}
+
+// WithRegistry looks themes up in r instead of the default registry shared
+// with the TUI and CLI
+func WithRegistry(r *Registry) Option {
+	return func(c *Client) {
+		c.registry = r
+	}
+}
+
+// WithBrightSynthesis turns bright synthesis (see theme.SynthesizeBrights)
+// on or off for the named apps, overriding each theme's synthesize_brights
+func WithBrightSynthesis(apps map[string]bool) Option {
+	return func(c *Client) {
+		c.brights = make(map[string]bool, len(apps))
+		for name, enabled := range apps {
+			c.brights[strings.ToLower(name)] = enabled
+		}
+	}
+}
+
+// New returns a client for the user's home directory and the default registry
+func New(opts ...Option) *Client {
+	home, _ := os.UserHomeDir()
+	c := &Client{
+		root:     home,
+		registry: theme.DefaultRegistry(),
+		loaders:  make(map[string]*theme.ThemeLoader),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.sandbox = c.root != home
+	if c.system != nil {
+		c.sandbox = !*c.system
+	}
+	return c
+}
+
+// Registry returns the registry the client looks themes up in
+func (c *Client) Registry() *Registry {
+	return c.registry
+}
+
+// LoadThemes registers the custom themes and families in dir. Loading the
+// same directory again replaces the themes registered from it.
+func (c *Client) LoadThemes(dir string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	loader, ok := c.loaders[dir]
+	if !ok {
+		loader = theme.NewThemeLoader(dir)
+		c.loaders[dir] = loader
+	}
+	return loader.LoadInto(c.registry)
+}
+
+// LoadAlacrittyThemes registers the "Alacritty community" family: the themes
+// of the alacritty-theme repository Alacritty's integration clones, and the
+// colors of the user's Alacritty config. Loading again replaces those themes
+// and keeps custom variants added to the family, so load it before the custom
+// themes to let them extend or join it. Files that fail to parse are reported
+// in the error; the others are still registered.
+func (c *Client) LoadAlacrittyThemes() error {
+	app, err := c.Integration("Alacritty")
+	if err != nil {
+		return err
+	}
+	alacritty, ok := app.(*integrations.AlacrittyIntegration)
+	if !ok {
+		return fmt.Errorf("unexpected Alacritty integration %T", app)
+	}
+	family, parseErr := importers.AlacrittyCommunityFamily(alacritty.CommunityThemesPath(), alacritty.ConfigPath())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.alacritty) > 0 {
+		// Ignore a family someone else already unregistered
+		_ = c.registry.RemoveVariants(family.Name, c.alacritty...)
+		c.alacritty = nil
+	}
+	if len(family.Variants) == 0 {
+		return parseErr
+	}
+	if _, ok := c.registry.Family(family.Name); ok {
+		err = c.registry.AddVariants(family.Name, family.Variants...)
+	} else {
+		err = c.registry.Register(family)
+	}
+	if err != nil {
+		return errors.Join(parseErr, err)
+	}
+	for _, v := range family.Variants {
+		c.alacritty = append(c.alacritty, v.FullName)
+	}
+	return parseErr
+}
+
+// Lookup returns the registered theme with the given name or alias (case-insensitive)
+func (c *Client) Lookup(name string) (Theme, error) {
+	if t, ok := c.registry.Lookup(name); ok {
+		return t, nil
+	}
+	return Theme{}, fmt.Errorf("theme %q not found", name)
+}
+
+// Integrations returns every integration, configured below the client's
+// root and, with WithRoot, kept to it (see WithSystemChanges)
+func (c *Client) Integrations() []Integration {
+	if c.sandbox {
+		return integrations.GetSandboxedIntegrations(c.root)
+	}
+	return integrations.GetIntegrationsForHome(c.root)
+}
+
+// Integration returns the integration with the given name (case-insensitive).
+// Installed VS Code variants such as "Cursor" are found by their own name.
+func (c *Client) Integration(name string) (Integration, error) {
+	for _, app := range c.Integrations() {
+		if strings.EqualFold(app.Name(), name) {
+			return app, nil
+		}
+		if vscode, ok := app.(*integrations.VSCodeIntegration); ok {
+			for _, variant := range vscode.Variants() {
+				if strings.EqualFold(variant.Name, name) {
+					return vscode.WithVariant(variant), nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown app %q", name)
+}
+
+// Request describes what Plan and Apply should do
+type Request struct {
+	Theme Theme
+
+	// Pair, if set, is applied to apps that follow the system appearance;
+	// other apps get the Preferred side (dark by default) instead of Theme
+	Pair      *Pair
+	Preferred Appearance
+
+	// Apps names the integrations to apply to. Integrations, if set, is used
+	// instead; with neither, every integration is used.
+	Apps         []string
+	Integrations []Integration
+
+	// PaletteOnly writes the palette with ApplyPalette, without official
+	// themes or backups, and skips integrations that can't. It suits themes
+	// that change often, such as the steps of a transition.
+	PaletteOnly bool
+
+	// Backup, with PaletteOnly, backs up the configuration before writing the
+	// palette. Set it on the first of a series of palettes only, so Restore
+	// returns to the configuration before the series.
+	Backup bool
+}
+
+// Step is one integration's part of a plan
+type Step struct {
+	Integration Integration
+	Theme       Theme
+	Pair        *Pair  // Set when the integration applies the whole pair
+	Skip        string // Why the step won't run, if it won't
+}
+
+// Plan returns the step Apply takes for each integration of the request
+func (c *Client) Plan(req Request) ([]Step, error) {
+	t := req.Theme
+	if req.Pair != nil {
+		t = req.Pair.For(req.Preferred)
+	}
+	if t.Name == "" {
+		return nil, fmt.Errorf("no theme given")
+	}
+	if req.PaletteOnly && req.Pair != nil {
+		return nil, fmt.Errorf("a pair can't be applied as a palette")
+	}
+
+	apps, err := c.resolve(req.Apps, req.Integrations)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]Step, 0, len(apps))
+	for _, app := range apps {
+		step := Step{Integration: app, Theme: c.withBrights(app, t)}
+		if _, ok := app.(AppearanceIntegration); ok && req.Pair != nil {
+			pair := *req.Pair
+			pair.Light, pair.Dark = c.withBrights(app, pair.Light), c.withBrights(app, pair.Dark)
+			step.Pair = &pair
+		}
+		if _, ok := app.(PaletteIntegration); req.PaletteOnly && !ok {
+			step.Skip = "Can't apply a palette directly"
+		}
+		if !app.IsInstalled() {
+			step.Skip = "Not installed or not found"
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// Apply applies the request's theme to each integration and returns a
+// result for each. The error joins the errors of the failed integrations.
+func (c *Client) Apply(req Request) ([]Result, error) {
+	steps, err := c.Plan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(steps))
+	var errs []error
+	for _, step := range steps {
+		result := Result{Integration: step.Integration.Name(), Theme: step.Theme.Name}
+		if step.Pair != nil {
+			result.Theme = step.Pair.Light.Name + " / " + step.Pair.Dark.Name
+		}
+
+		switch {
+		case step.Skip != "":
+			result.Status, result.Message = Skipped, step.Skip
+		case c.dryRun:
+			result.Status, result.Message = Planned, "Would apply "+result.Theme
+		default:
+			if step.Pair != nil {
+				result.Err = step.Integration.(AppearanceIntegration).ApplyPair(*step.Pair)
+			} else if req.PaletteOnly {
+				app := step.Integration.(PaletteIntegration)
+				if req.Backup {
+					result.Err = app.Backup()
+				}
+				if result.Err == nil {
+					result.Err = app.ApplyPalette(step.Theme)
+				}
+			} else {
+				result.Err = step.Integration.Apply(step.Theme)
+			}
+			if noticer, ok := step.Integration.(NoticeIntegration); ok {
+				result.Notices = noticer.Notices()
+			}
+			if result.Err != nil {
+				result.Status = Failed
+				errs = append(errs, fmt.Errorf("%s: %w", result.Integration, result.Err))
+			} else {
+				result.Status, result.Message = Applied, "Theme applied successfully"
+			}
+		}
+		results = append(results, c.report(result))
+	}
+	return results, errors.Join(errs...)
+}
+
+// Restore copies the backups made by previous applies over the current
+// configuration of the named integrations, or of every integration that
+// keeps backups when no names are given
+func (c *Client) Restore(apps ...string) ([]Result, error) {
+	targets, err := c.resolve(apps, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	var errs []error
+	for _, app := range targets {
+		result := Result{Integration: app.Name()}
+		backupApp, ok := app.(BackupIntegration)
+		if !ok {
+			if len(apps) == 0 {
+				continue
+			}
+			result.Status, result.Message = Skipped, "Doesn't keep backups"
+			results = append(results, c.report(result))
+			continue
+		}
+
+		backups := backupApp.Backups()
+		switch {
+		case len(backups) == 0:
+			result.Status, result.Message = Skipped, "No backup found"
+		case c.dryRun:
+			result.Status, result.Message = Planned, "Would restore "+strings.Join(backups, ", ")
+		default:
+			for _, backup := range backups {
+				if err := integrations.RestoreBackup(backup); err != nil {
+					result.Err = errors.Join(result.Err, err)
+				}
+			}
+			if result.Err != nil {
+				result.Status = Failed
+				errs = append(errs, fmt.Errorf("%s: %w", result.Integration, result.Err))
+			} else {
+				result.Status, result.Message = Restored, "Restored "+strings.Join(backups, ", ")
+			}
+		}
+		results = append(results, c.report(result))
+	}
+	return results, errors.Join(errs...)
+}
+
+// withBrights returns the theme with synthesized brights when the app's
+// configuration, or else the theme, asks for them
+func (c *Client) withBrights(app Integration, t Theme) Theme {
+	enabled, ok := c.brights[strings.ToLower(app.Name())]
+	if !ok {
+		enabled = t.BrightSynthesis()
+	}
+	if enabled {
+		return t.WithSynthesizedBrights()
+	}
+	return t
+}
+
+// resolve returns the given integrations, the named ones, or all of them
+func (c *Client) resolve(names []string, apps []Integration) ([]Integration, error) {
+	if len(apps) > 0 {
+		return apps, nil
+	}
+	if len(names) == 0 {
+		return c.Integrations(), nil
+	}
+
+	apps = make([]Integration, 0, len(names))
+	for _, name := range names {
+		app, err := c.Integration(name)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// report passes the result to the reporter, if any, and returns it
+func (c *Client) report(result Result) Result {
+	if c.reporter != nil {
+		c.reporter(result)
+	}
+	return result
+}
+
+// Preview renders the theme's palette and sample code for a terminal
+func Preview(t Theme) string {
+	return theme.NewThemePreview(t).Render()
+}
@@ -0,0 +1,270 @@
+package zakaranda
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dahromy/zakaranda/internal/theme"
+)
+
+// newTestRoot returns a root directory with a Starship configuration
+func newTestRoot(t *testing.T) (root, configPath string) {
+	root = t.TempDir()
+	configPath = filepath.Join(root, ".config", "starship.toml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("format = \"$all\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root, configPath
+}
+
+// TestApplyWithRoot verifies themes are applied below the root and
+// uninstalled apps are skipped
+func TestApplyWithRoot(t *testing.T) {
+	root, configPath := newTestRoot(t)
+	var reported []Result
+	client := New(WithRoot(root), WithReporter(func(r Result) { reported = append(reported, r) }))
+
+	nord, err := client.Lookup("nord")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := client.Apply(Request{Theme: nord, Apps: []string{"starship", "Zed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 || results[0].Status != Applied || results[1].Status != Skipped {
+		t.Fatalf("Expected Starship applied and Zed skipped, got %v", results)
+	}
+	if len(reported) != 2 {
+		t.Errorf("Expected 2 reported results, got %d", len(reported))
+	}
+	if results[0].String() != "✅ Starship: Theme applied successfully" {
+		t.Errorf("Unexpected result line %q", results[0])
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "#2e3440") {
+		t.Error("Expected the Nord palette in the Starship configuration")
+	}
+}
+
+// TestApplySandboxed verifies a root keeps applies from changing the system
+// outside it and reports the skipped steps
+func TestApplySandboxed(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Library", "Application Support", "Code", "User"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	picture := filepath.Join(t.TempDir(), "harbor.png")
+	if err := os.WriteFile(picture, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client := New(WithRoot(root))
+
+	dracula, _ := client.Lookup("Dracula")
+	dracula.Wallpaper = picture
+	results, err := client.Apply(Request{Theme: dracula, Apps: []string{"VS Code", "macOS Wallpaper"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Status != Applied || len(result.Notices) != 1 || !strings.HasSuffix(result.Notices[0], "(sandboxed)") {
+			t.Errorf("Expected %s applied with its system step skipped, got %+v", result.Integration, result)
+		}
+	}
+	if !strings.Contains(results[1].String(), "\n   Skipped setting the desktop picture (sandboxed)") {
+		t.Errorf("Expected the notice below the result line, got %q", results[1])
+	}
+	if _, err := os.Stat(filepath.Join(root, ".config", "zakaranda", "wallpapers", "Dracula.png")); err != nil {
+		t.Error("Expected the wallpaper copied below the root")
+	}
+}
+
+// TestApplyDryRun verifies a dry run reports the plan without writing files
+func TestApplyDryRun(t *testing.T) {
+	root, configPath := newTestRoot(t)
+	client := New(WithRoot(root), WithDryRun(true))
+
+	nord, _ := client.Lookup("Nord")
+	results, err := client.Apply(Request{Theme: nord, Apps: []string{"Starship"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Status != Planned {
+		t.Fatalf("Expected a planned result, got %v", results)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if string(data) != "format = \"$all\"\n" {
+		t.Error("Expected a dry run not to change the configuration")
+	}
+	if _, err := os.Stat(configPath + ".backup"); err == nil {
+		t.Error("Expected a dry run not to create a backup")
+	}
+
+	if _, err := client.Apply(Request{Theme: nord, Apps: []string{"Notepad"}}); err == nil {
+		t.Error("Expected an unknown app to be rejected")
+	}
+	if _, err := client.Plan(Request{}); err == nil {
+		t.Error("Expected a request without a theme to be rejected")
+	}
+}
+
+// TestRestore verifies restoring puts back the configuration from before the last apply
+func TestRestore(t *testing.T) {
+	root, configPath := newTestRoot(t)
+	client := New(WithRoot(root))
+
+	nord, _ := client.Lookup("Nord")
+	if _, err := client.Apply(Request{Theme: nord, Apps: []string{"Starship"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.Restore("Starship", "Slack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Status != Restored || results[1].Status != Skipped {
+		t.Fatalf("Expected Starship restored and Slack skipped, got %v", results)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if string(data) != "format = \"$all\"\n" {
+		t.Errorf("Expected the original configuration, got:\n%s", data)
+	}
+}
+
+// TestApplyPaletteOnly verifies a palette-only apply writes the colors
+// without backups and skips apps that can't take a palette
+func TestApplyPaletteOnly(t *testing.T) {
+	root, _ := newTestRoot(t)
+	alacrittyConfig := filepath.Join(root, ".config", "alacritty", "alacritty.toml")
+	if err := os.MkdirAll(filepath.Dir(alacrittyConfig), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(alacrittyConfig, []byte("[font]\nsize = 12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client := New(WithRoot(root))
+
+	dawn, _ := client.Lookup("Rose Pine Dawn")
+	moon, _ := client.Lookup("Rose Pine Moon")
+	blend := theme.Blend(dawn, moon, 0.5)
+	results, err := client.Apply(Request{Theme: blend, Apps: []string{"Alacritty", "Starship"}, PaletteOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Status != Applied || results[1].Status != Skipped {
+		t.Fatalf("Expected Alacritty applied and Starship skipped, got %v", results)
+	}
+
+	data, _ := os.ReadFile(alacrittyConfig)
+	if !strings.Contains(string(data), blend.Colors.Background) || !strings.Contains(string(data), "size = 12") {
+		t.Errorf("Expected the blended colors next to the existing settings, got:\n%s", data)
+	}
+	if _, err := os.Stat(alacrittyConfig + ".backup"); err == nil {
+		t.Error("Expected a palette-only apply not to create a backup")
+	}
+
+	if _, err := client.Apply(Request{Theme: moon, Apps: []string{"Alacritty"}, PaletteOnly: true, Backup: true}); err != nil {
+		t.Fatal(err)
+	}
+	if backup, _ := os.ReadFile(alacrittyConfig + ".backup"); !strings.Contains(string(backup), blend.Colors.Background) {
+		t.Errorf("Expected a backup of the configuration before the palette, got:\n%s", backup)
+	}
+}
+
+// TestPlanBrightSynthesis verifies the app configuration decides whether a
+// step's theme gets synthesized brights
+func TestPlanBrightSynthesis(t *testing.T) {
+	root, _ := newTestRoot(t)
+	client := New(WithRoot(root), WithBrightSynthesis(map[string]bool{"starship": true}))
+
+	nord, _ := client.Lookup("Nord")
+	steps, err := client.Plan(Request{Theme: nord, Apps: []string{"Starship", "Zed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps[0].Theme.Colors.BrightRed == nord.Colors.BrightRed {
+		t.Error("Expected Starship to get synthesized brights")
+	}
+	if steps[1].Theme.Colors.BrightRed != nord.Colors.BrightRed {
+		t.Error("Expected Zed to get Nord's own brights")
+	}
+}
+
+// TestLoadAlacrittyThemes verifies the cloned alacritty-theme repository is
+// registered as a family, that loading again replaces its themes and that
+// custom themes can join and extend it
+func TestLoadAlacrittyThemes(t *testing.T) {
+	root := t.TempDir()
+	themesDir := filepath.Join(root, ".config", "alacritty", "themes", "alacritty", "themes")
+	if err := os.MkdirAll(themesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	nord, err := New().Lookup("Nord")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := nord.Colors
+	data := fmt.Sprintf("[colors.primary]\nbackground = '%s'\nforeground = '%s'\n[colors.normal]\nblack = '%s'\nred = '%s'\ngreen = '%s'\nyellow = '%s'\nblue = '%s'\nmagenta = '%s'\ncyan = '%s'\nwhite = '%s'\n",
+		c.Background, c.Foreground, c.Black, c.Red, c.Green, c.Yellow, c.Blue, c.Magenta, c.Cyan, c.White)
+	if err := os.WriteFile(filepath.Join(themesDir, "arctic_frost.toml"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := New(WithRoot(root), WithRegistry(theme.NewBuiltInRegistry()))
+	for i := 0; i < 2; i++ {
+		if err := client.LoadAlacrittyThemes(); err != nil {
+			t.Fatalf("LoadAlacrittyThemes failed: %v", err)
+		}
+	}
+
+	family, ok := client.Registry().Family("Alacritty community")
+	if !ok || len(family.Variants) != 1 {
+		t.Fatalf("Expected the family with one variant, got %+v", family)
+	}
+	if _, err := client.Lookup("Arctic Frost (Alacritty)"); err != nil {
+		t.Error(err)
+	}
+
+	// Custom themes can join and extend the family, and keep their place in
+	// it when the community themes are loaded again
+	customDir := t.TempDir()
+	variant := nord
+	variant.Name, variant.Aliases = "Arctic Dusk", nil
+	if err := theme.NewThemeLoader(customDir).SaveCustomVariant("Alacritty community", variant, "yaml"); err != nil {
+		t.Fatal(err)
+	}
+	extending := "name: Arctic Ember\nextends: Arctic Frost (Alacritty)\ncolors:\n  red: \"#ff5555\"\n"
+	if err := os.WriteFile(filepath.Join(customDir, "ember.yaml"), []byte(extending), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LoadThemes(customDir); err != nil {
+		t.Fatalf("LoadThemes failed: %v", err)
+	}
+	if err := client.LoadAlacrittyThemes(); err != nil {
+		t.Fatalf("LoadAlacrittyThemes failed: %v", err)
+	}
+	family, _ = client.Registry().Family("Alacritty community")
+	if len(family.Variants) != 2 {
+		t.Errorf("Expected the community and custom variants, got %+v", family.Variants)
+	}
+	ember, err := client.Lookup("Arctic Ember")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ember.Colors.Background != c.Background || ember.Colors.Red != "#ff5555" {
+		t.Errorf("Expected Arctic Ember to extend Arctic Frost, got %+v", ember.Colors)
+	}
+}